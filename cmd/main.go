@@ -2,10 +2,16 @@ package main
 
 import (
 	"backend/internal/router"
+	"backend/internal/service"
 	"backend/pkg/config"
 	"backend/pkg/database"
+	"backend/pkg/hasher"
+	"backend/pkg/jwt"
 	"backend/pkg/logger"
+	"backend/pkg/mailer"
+	"backend/pkg/netguard"
 	"fmt"
+	"time"
 
 	_ "backend/docs" // 导入swagger文档
 
@@ -47,11 +53,46 @@ func init() {
 		config.GetString("log.output"),
 	)
 
+	// 初始化令牌配置和签名密钥
+	if err := jwt.Init(); err != nil {
+		logger.Error(fmt.Sprintf("初始化JWT失败: %v", err))
+		panic(err)
+	}
+
+	// 初始化密码哈希算法
+	if err := hasher.Init(); err != nil {
+		logger.Error(fmt.Sprintf("初始化密码哈希失败: %v", err))
+		panic(err)
+	}
+
+	// 初始化邮件发送
+	if err := mailer.Init(); err != nil {
+		logger.Error(fmt.Sprintf("初始化邮件发送失败: %v", err))
+		panic(err)
+	}
+
+	// 初始化出站连接限制
+	if err := netguard.Init(); err != nil {
+		logger.Error(fmt.Sprintf("初始化出站连接限制失败: %v", err))
+		panic(err)
+	}
+
 	// 初始化数据库连接
 	if err := database.Init(); err != nil {
 		logger.Error(fmt.Sprintf("初始化数据库失败: %v", err))
 		panic(err)
 	}
+
+	// 加载令牌撤销列表并定期同步
+	syncInterval := config.GetDuration("jwt.revocation_sync_interval")
+	if syncInterval <= 0 {
+		syncInterval = time.Minute
+	}
+	revocationService := &service.RevocationService{}
+	if err := revocationService.StartSync(syncInterval); err != nil {
+		logger.Error(fmt.Sprintf("加载令牌撤销列表失败: %v", err))
+		panic(err)
+	}
 }
 
 func main() {
@@ -60,6 +101,11 @@ func main() {
 	// 创建gin实例
 	r := gin.Default()
 
+	// 只信任配置的反向代理转发的客户端IP，未配置时为 nil，不信任任何代理
+	if err := r.SetTrustedProxies(config.GetStringSlice("server.trusted_proxies")); err != nil {
+		logger.Fatal("可信代理配置无效: " + err.Error())
+	}
+
 	// 注册路由
 	router.RegisterRoutes(r)
 
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateAPIKeyRequest 创建API Key请求
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=64" example:"ci-deploy"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1" example:"orgs:read"`
+	ExpiresAt *time.Time `json:"expires_at"` // 可选，为空表示永不过期
+}
+
+// CreateAPIKeyResponse 创建API Key响应
+type CreateAPIKeyResponse struct {
+	Key    string        `json:"key"` // 完整密钥，仅展示一次
+	APIKey *model.APIKey `json:"api_key"`
+}
+
+// APIKey API Key控制器
+type APIKey struct {
+	apiKeyService *service.APIKeyService
+}
+
+// NewAPIKey creates a new APIKey controller
+func NewAPIKey() *APIKey {
+	return &APIKey{
+		apiKeyService: &service.APIKeyService{},
+	}
+}
+
+// Create 创建API Key
+// @Summary      创建API Key
+// @Description  为当前用户创建个人访问令牌，完整密钥只在响应中返回一次
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body CreateAPIKeyRequest true "API Key信息"
+// @Success      201  {object}  CreateAPIKeyResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Router       /auth/api-keys [post]
+func (a *APIKey) Create(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	apiKey, rawKey, err := a.apiKeyService.Create(currentUser.ID, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateAPIKeyResponse{Key: rawKey, APIKey: apiKey})
+}
+
+// List 获取API Key列表
+// @Summary      获取API Key列表
+// @Description  获取当前用户的个人访问令牌列表（不含密钥）
+// @Tags         api-keys
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {array}   model.APIKey
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      500  {object}  response.ErrorResponse
+// @Router       /auth/api-keys [get]
+func (a *APIKey) List(c *gin.Context) {
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	keys, err := a.apiKeyService.List(currentUser.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// Revoke 撤销API Key
+// @Summary      撤销API Key
+// @Description  撤销当前用户的个人访问令牌
+// @Tags         api-keys
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "API Key ID"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /auth/api-keys/{id} [delete]
+func (a *APIKey) Revoke(c *gin.Context) {
+	id := c.Param("id")
+	var keyID uint
+	if _, err := fmt.Sscanf(id, "%d", &keyID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "API Key ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := a.apiKeyService.Revoke(currentUser.ID, keyID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "API Key已撤销"})
+}
@@ -3,12 +3,13 @@ package controller
 import (
 	"backend/internal/model"
 	"backend/internal/service"
-	"backend/pkg/database"
 	"backend/pkg/jwt"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // LoginRequest 普通用户登录请求
@@ -27,14 +28,37 @@ type AdminLoginRequest struct {
 // RegisterRequest 注册请求
 type RegisterRequest struct {
 	Username       string `json:"username" binding:"required,min=3,max=32"`
-	Password       string `json:"password" binding:"required,min=6,max=32"`
+	Password       string `json:"password" binding:"required,max=128"`
 	Email          string `json:"email" binding:"required,email"`
 	OrganizationID uint   `json:"organization_id" binding:"required"` // 设为必填项
 }
 
+// RefreshRequest 刷新令牌请求
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest 注销请求
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"` // 可选，提供时同时撤销该刷新令牌所在的会话
+}
+
+// RevokeUserTokensRequest 强制用户下线请求（管理员使用）
+type RevokeUserTokensRequest struct {
+	UserID uint `json:"user_id" binding:"required"`
+}
+
+// UnlockLoginRequest 解除登录锁定请求（管理员使用）
+type UnlockLoginRequest struct {
+	UserID uint   `json:"user_id" binding:"required"`
+	IP     string `json:"ip" binding:"omitempty,ip"` // 可选，同时解除该IP的锁定
+}
+
 // LoginResponse 登录响应
 type LoginResponse struct {
-	Token        string `json:"token"`
+	Token        string `json:"token"`         // 访问令牌
+	RefreshToken string `json:"refresh_token"` // 刷新令牌
+	ExpiresIn    int64  `json:"expires_in"`    // 访问令牌有效期（秒）
 	UserID       uint   `json:"user_id"`
 	Username     string `json:"username"`
 	Role         string `json:"role"`
@@ -44,37 +68,78 @@ type LoginResponse struct {
 // CreateAdminRequest 创建超级管理员请求
 type CreateAdminRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=32"`
-	Password string `json:"password" binding:"required,min=6,max=32"`
+	Password string `json:"password" binding:"required,max=128"`
 	Email    string `json:"email" binding:"required,email"`
 }
 
 // ChangePasswordRequest 修改密码请求
 type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password" binding:"required"`
-	NewPassword string `json:"new_password" binding:"required,min=6,max=32"`
+	NewPassword string `json:"new_password" binding:"required,max=128"`
 }
 
 // ResetPasswordRequest 重置密码请求（管理员使用）
 type ResetPasswordRequest struct {
 	UserID      uint   `json:"user_id" binding:"required"`
-	NewPassword string `json:"new_password" binding:"required,min=6,max=32"`
+	NewPassword string `json:"new_password" binding:"required,max=128"`
+}
+
+// ForgotPasswordRequest 找回密码请求
+type ForgotPasswordRequest struct {
+	OrganizationCode string `json:"organization_code" binding:"required"`
+	Email            string `json:"email" binding:"required,email"`
+}
+
+// ConfirmPasswordResetRequest 通过邮件令牌重置密码请求
+type ConfirmPasswordResetRequest struct {
+	Token       string `json:"token" binding:"required"` // 重置密码邮件中的令牌
+	NewPassword string `json:"new_password" binding:"required,max=128"`
+}
+
+// VerifyEmailRequest 验证邮箱请求
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"` // 验证邮件中的令牌
+}
+
+// ResendVerificationRequest 重新发送验证邮件请求
+type ResendVerificationRequest struct {
+	OrganizationCode string `json:"organization_code" binding:"required"`
+	Email            string `json:"email" binding:"required,email"`
+}
+
+// UpdateProfileRequest 修改个人资料请求，省略的字段保持不变，空字符串表示清除
+type UpdateProfileRequest struct {
+	Email       *string `json:"email" binding:"omitempty,email,max=128" example:"john@example.com"` // 修改后需要重新验证
+	DisplayName *string `json:"display_name" binding:"omitempty,max=64" example:"张三"`
+	Locale      *string `json:"locale" binding:"omitempty,max=16" example:"zh-CN"`           // BCP 47 语言标签
+	Timezone    *string `json:"timezone" binding:"omitempty,max=64" example:"Asia/Shanghai"` // IANA 时区名称
+}
+
+// PasswordPolicyErrorResponse 密码不符合策略时的响应
+type PasswordPolicyErrorResponse struct {
+	Error      string                      `json:"error" example:"密码不符合安全策略"`
+	Violations []service.PasswordViolation `json:"violations"`
 }
 
 // Auth 认证控制器
 type Auth struct {
-	authService *service.AuthService
+	authService    *service.AuthService
+	emailService   *service.EmailVerificationService
+	profileService *service.ProfileService
 }
 
 // NewAuth creates a new Auth controller
 func NewAuth() *Auth {
 	return &Auth{
-		authService: &service.AuthService{},
+		authService:    &service.AuthService{},
+		emailService:   &service.EmailVerificationService{},
+		profileService: &service.ProfileService{},
 	}
 }
 
 // Login 普通用户登录
 // @Summary      用户登录
-// @Description  普通用户登录接口，用于获取登录凭证
+// @Description  普通用户登录接口，用于获取登录凭证；已启用双因素认证的用户返回 MFAChallengeResponse
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -82,6 +147,7 @@ func NewAuth() *Auth {
 // @Success      200  {object}  LoginResponse
 // @Failure      400  {object}  response.ErrorResponse
 // @Failure      401  {object}  response.ErrorResponse
+// @Failure      429  {object}  response.ErrorResponse
 // @Router       /auth/login [post]
 func (a *Auth) Login(c *gin.Context) {
 	var req LoginRequest
@@ -90,22 +156,27 @@ func (a *Auth) Login(c *gin.Context) {
 		return
 	}
 
-	user, token, err := a.authService.Login(req.Username, req.Password, req.OrganizationCode)
+	result, err := a.authService.Login(req.Username, req.Password, req.OrganizationCode, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeLoginError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, LoginResponse{
-		Token:        token,
-		UserID:       user.ID,
-		Username:     user.Username,
-		Role:         user.Role,
-		Organization: user.Organization.Code,
-	})
+	writeLoginResult(c, result)
 }
 
 // AdminLogin 管理员登录
+// @Summary      管理员登录
+// @Description  超级管理员通过 system 组织登录，不需要提供组织代码；已启用双因素认证的管理员返回 MFAChallengeResponse
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body AdminLoginRequest true "登录信息"
+// @Success      200  {object}  LoginResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      429  {object}  response.ErrorResponse
+// @Router       /auth/admin/login [post]
 func (a *Auth) AdminLogin(c *gin.Context) {
 	var req AdminLoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -113,54 +184,232 @@ func (a *Auth) AdminLogin(c *gin.Context) {
 		return
 	}
 
-	// 查找管理员用户
-	var user model.User
-	var systemOrg model.Organization
-	if err := database.DB.Where("code = ?", "system").First(&systemOrg).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "system 组织未找到"})
+	result, err := a.authService.AdminLogin(req.Username, req.Password, clientInfo(c))
+	if err != nil {
+		writeLoginError(c, err)
 		return
 	}
 
-	if err := database.DB.Preload("Organization").
-		Where("username = ? AND role = ? AND organization_id = ?",
-			req.Username, model.RoleSuperAdmin, systemOrg.ID).
-		First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "错误的用户名/密码"})
+	writeLoginResult(c, result)
+}
+
+// Refresh 刷新令牌
+// @Summary      刷新令牌
+// @Description  使用刷新令牌换取新的访问令牌和刷新令牌，旧的刷新令牌随即失效
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body RefreshRequest true "刷新令牌"
+// @Success      200  {object}  LoginResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /auth/refresh [post]
+func (a *Auth) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// 验证密码
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "错误的用户名/密码"})
+	user, pair, err := a.authService.Refresh(req.RefreshToken, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if isAccountStatusError(err) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// 生成 token
-	token, err := jwt.GenerateToken(user.ID, user.Username, user.Role, user.OrganizationID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成token失败"})
+	c.JSON(http.StatusOK, newLoginResponse(user, pair))
+}
+
+// Logout 注销登录
+// @Summary      注销登录
+// @Description  撤销当前访问令牌，提供刷新令牌时同时撤销对应会话
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body LogoutRequest false "注销信息"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Router       /auth/logout [post]
+func (a *Auth) Logout(c *gin.Context) {
+	var req LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	value, exists := c.Get("claims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	claims := value.(*jwt.CustomClaims)
+
+	if err := a.authService.Logout(claims, req.RefreshToken); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "注销成功"})
+}
+
+// RevokeUserTokens 强制用户下线（需要 user.revoke_tokens 权限）
+// @Summary      强制用户下线
+// @Description  撤销指定用户所有已签发的访问令牌和刷新令牌。非超级管理员只能操作本组织及下级组织中权限不超过自己的用户
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body RevokeUserTokensRequest true "用户信息"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /auth/revoke-tokens [post]
+func (a *Auth) RevokeUserTokens(c *gin.Context) {
+	var req RevokeUserTokensRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := a.authService.RevokeUserTokens(currentUser, req.UserID); err != nil {
+		writeUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "用户已强制下线"})
+}
+
+// UnlockLogin 解除登录锁定（需要 user.unlock 权限）
+// @Summary      解除登录锁定
+// @Description  清除用户因连续登录失败产生的延迟和锁定，可同时解除指定IP的锁定。非超级管理员只能操作本组织中权限不超过自己的用户
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body UnlockLoginRequest true "解锁信息"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /auth/unlock [post]
+func (a *Auth) UnlockLogin(c *gin.Context) {
+	var req UnlockLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := a.authService.UnlockLogin(currentUser, req.UserID, req.IP); err != nil {
+		writeUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已解除登录锁定"})
+}
+
+// writeLoginResult 输出登录结果，需要双因素认证时返回挑战令牌
+func writeLoginResult(c *gin.Context, result *service.LoginResult) {
+	if result.MFAChallenge != "" {
+		c.JSON(http.StatusOK, MFAChallengeResponse{
+			MFARequired: true,
+			MFAToken:    result.MFAChallenge,
+			ExpiresIn:   result.MFAExpiresIn,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, newLoginResponse(result.User, result.Tokens))
+}
+
+// clientInfo 获取发起请求的客户端信息，记录到登录会话中
+func clientInfo(c *gin.Context) service.ClientInfo {
+	return service.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// writeLoginError 写入登录失败响应，被限制的登录返回 429 和 Retry-After，邮箱未验证或账号不是正常状态返回 403
+func writeLoginError(c *gin.Context, err error) {
+	var throttled *service.LoginThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrEmailNotVerified) || isAccountStatusError(err) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+}
+
+// isAccountStatusError 检查是否为账号不是正常状态导致的错误
+func isAccountStatusError(err error) bool {
+	return errors.Is(err, service.ErrAccountPending) || errors.Is(err, service.ErrAccountDisabled) ||
+		errors.Is(err, service.ErrAccountLocked) || errors.Is(err, service.ErrAccountDeactivated)
+}
+
+// writePasswordError 写入设置密码失败的响应，不符合密码策略时返回全部违规项
+func writePasswordError(c *gin.Context, err error) {
+	var policyErr *service.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		c.JSON(http.StatusBadRequest, PasswordPolicyErrorResponse{Error: err.Error(), Violations: policyErr.Violations})
 		return
 	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
 
-	// 返回登录信息
-	c.JSON(http.StatusOK, LoginResponse{
-		Token:        token,
+// newLoginResponse 根据用户和令牌对构建登录响应
+func newLoginResponse(user *model.User, pair *service.TokenPair) LoginResponse {
+	return LoginResponse{
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresIn:    pair.ExpiresIn,
 		UserID:       user.ID,
 		Username:     user.Username,
 		Role:         user.Role,
 		Organization: user.Organization.Code,
-	})
+	}
 }
 
 // Register 用户注册
 // @Summary      用户注册
-// @Description  注册新用户
+// @Description  按组织的注册方式注册新用户：closed 时返回 403，domain 只允许指定域名的邮箱注册且验证邮箱后才能登录，approval 时新用户处于 pending 状态，组织管理员审核通过后才能登录
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        request body RegisterRequest true "注册信息"
 // @Success      201  {object}  model.User
-// @Failure      400  {object}  response.ErrorResponse
+// @Failure      400  {object}  PasswordPolicyErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
 // @Router       /auth/register [post]
 func (a *Auth) Register(c *gin.Context) {
 	var req RegisterRequest
@@ -171,7 +420,11 @@ func (a *Auth) Register(c *gin.Context) {
 
 	user, err := a.authService.Register(req.Username, req.Password, req.Email, req.OrganizationID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if errors.Is(err, service.ErrRegistrationClosed) || errors.Is(err, service.ErrEmailDomainNotAllowed) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		writePasswordError(c, err)
 		return
 	}
 
@@ -179,27 +432,68 @@ func (a *Auth) Register(c *gin.Context) {
 }
 
 // GetCurrentUser 获取当前用户信息
+// @Summary      获取当前用户
+// @Description  获取当前用户的账号信息和个人资料，组织和角色为当前令牌所在的组织及其中的角色
+// @Tags         auth
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {object}  service.Profile
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      500  {object}  response.ErrorResponse
+// @Router       /auth/me [get]
 func (a *Auth) GetCurrentUser(c *gin.Context) {
 	user, exists := c.Get("currentUser")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
 		return
 	}
-
 	currentUser := user.(*model.User)
-	var org model.Organization
-	if err := database.DB.First(&org, currentUser.OrganizationID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取组织信息失败"})
+
+	profile, err := a.profileService.Get(currentUser)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, profile)
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"user_id":      currentUser.ID,
-		"username":     currentUser.Username,
-		"email":        currentUser.Email,
-		"role":         currentUser.Role,
-		"organization": org.Code,
+// UpdateProfile 修改个人资料
+// @Summary      修改个人资料
+// @Description  修改自己的邮箱、显示名称、语言区域和时区。修改邮箱后需要重新验证，要求验证邮箱的组织在验证前不能再次登录；目录用户和联合登录用户不能修改邮箱
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body UpdateProfileRequest true "个人资料"
+// @Success      200  {object}  service.Profile
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Router       /auth/me [patch]
+func (a *Auth) UpdateProfile(c *gin.Context) {
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	profile, err := a.profileService.Update(currentUser, service.ProfileInput{
+		Email:       req.Email,
+		DisplayName: req.DisplayName,
+		Locale:      req.Locale,
+		Timezone:    req.Timezone,
 	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, profile)
 }
 
 // ChangePassword 用户修改密码
@@ -211,7 +505,7 @@ func (a *Auth) GetCurrentUser(c *gin.Context) {
 // @Security     Bearer
 // @Param        request body ChangePasswordRequest true "密码修改信息"
 // @Success      200  {object}  response.SuccessResponse
-// @Failure      400  {object}  response.ErrorResponse
+// @Failure      400  {object}  PasswordPolicyErrorResponse
 // @Failure      401  {object}  response.ErrorResponse
 // @Router       /auth/change-password [post]
 func (a *Auth) ChangePassword(c *gin.Context) {
@@ -230,25 +524,26 @@ func (a *Auth) ChangePassword(c *gin.Context) {
 	currentUser := user.(*model.User)
 
 	if err := a.authService.ChangePassword(currentUser.ID, req.OldPassword, req.NewPassword); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writePasswordError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "密码修改成功"})
 }
 
-// ResetPassword 重置用户密码（需要管理员权限）
+// ResetPassword 重置用户密码（需要 user.reset_password 权限）
 // @Summary      重置密码
-// @Description  管理员重置用户密码
+// @Description  管理员重置用户密码。非超级管理员只能重置本组织中权限不超过自己的用户，其他用户返回403
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Security     Bearer
 // @Param        request body ResetPasswordRequest true "密码重置信息"
 // @Success      200  {object}  response.SuccessResponse
-// @Failure      400  {object}  response.ErrorResponse
+// @Failure      400  {object}  PasswordPolicyErrorResponse
 // @Failure      401  {object}  response.ErrorResponse
 // @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
 // @Router       /auth/reset-password [post]
 func (a *Auth) ResetPassword(c *gin.Context) {
 	var req ResetPasswordRequest
@@ -257,14 +552,113 @@ func (a *Auth) ResetPassword(c *gin.Context) {
 		return
 	}
 
-	if err := a.authService.ResetPassword(req.UserID, req.NewPassword); err != nil {
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := a.authService.ResetPassword(currentUser, req.UserID, req.NewPassword); err != nil {
+		writeUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "密码重置成功"})
+}
+
+// ForgotPassword 找回密码
+// @Summary      找回密码
+// @Description  向组织内使用该邮箱的用户发送重置密码邮件。无论账号是否存在都返回相同的响应
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ForgotPasswordRequest true "组织代码和邮箱"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Router       /auth/forgot-password [post]
+func (a *Auth) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	a.authService.ForgotPassword(req.OrganizationCode, req.Email)
+	c.JSON(http.StatusOK, gin.H{"message": "如果该邮箱已注册，您将收到重置密码邮件"})
+}
+
+// ConfirmPasswordReset 通过邮件重置密码
+// @Summary      通过邮件重置密码
+// @Description  使用重置密码邮件中的一次性令牌设置新密码，成功后已签发的令牌全部失效
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ConfirmPasswordResetRequest true "令牌和新密码"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  PasswordPolicyErrorResponse
+// @Router       /auth/reset-password/confirm [post]
+func (a *Auth) ConfirmPasswordReset(c *gin.Context) {
+	var req ConfirmPasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	if err := a.authService.ConfirmPasswordReset(req.Token, req.NewPassword); err != nil {
+		writePasswordError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "密码重置成功"})
 }
 
+// VerifyEmail 验证邮箱
+// @Summary      验证邮箱
+// @Description  使用验证邮件中的一次性令牌确认邮箱
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body VerifyEmailRequest true "验证令牌"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Router       /auth/verify-email [post]
+func (a *Auth) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := a.emailService.Confirm(req.Token); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "邮箱验证成功"})
+}
+
+// ResendVerification 重新发送验证邮件
+// @Summary      重新发送验证邮件
+// @Description  向组织内使用该邮箱且尚未验证的用户重新发送验证邮件，同一用户有发送间隔限制。无论账号是否存在都返回相同的响应
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ResendVerificationRequest true "组织代码和邮箱"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Router       /auth/verify-email/resend [post]
+func (a *Auth) ResendVerification(c *gin.Context) {
+	var req ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	a.emailService.Resend(req.OrganizationCode, req.Email)
+	c.JSON(http.StatusOK, gin.H{"message": "如果该邮箱已注册且尚未验证，您将收到验证邮件"})
+}
+
 // CreateAdmin 创建超级管理员（需要超级管理员权限）
 // @Summary      创建管理员
 // @Description  创建新的超级管理员（需要超级管理员权限）
@@ -274,7 +668,7 @@ func (a *Auth) ResetPassword(c *gin.Context) {
 // @Security     Bearer
 // @Param        request body CreateAdminRequest true "管理员创建信息"
 // @Success      201  {object}  model.User
-// @Failure      400  {object}  response.ErrorResponse
+// @Failure      400  {object}  PasswordPolicyErrorResponse
 // @Failure      401  {object}  response.ErrorResponse
 // @Failure      403  {object}  response.ErrorResponse
 // @Router       /auth/create-admin [post]
@@ -287,7 +681,7 @@ func (a *Auth) CreateAdmin(c *gin.Context) {
 
 	admin, err := a.authService.CreateAdmin(req.Username, req.Password, req.Email)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writePasswordError(c, err)
 		return
 	}
 
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonateRequest 模拟登录请求
+type ImpersonateRequest struct {
+	UserID uint   `json:"user_id" binding:"required"`
+	Reason string `json:"reason" binding:"required,max=256"` // 模拟登录原因，记录在审计日志中
+}
+
+// ImpersonationResponse 模拟登录响应
+type ImpersonationResponse struct {
+	Token          string    `json:"token"`      // 模拟登录访问令牌，不附带刷新令牌
+	ExpiresIn      int64     `json:"expires_in"` // 访问令牌有效期（秒）
+	ExpiresAt      time.Time `json:"expires_at"`
+	UserID         uint      `json:"user_id"`
+	Username       string    `json:"username"`
+	Role           string    `json:"role"`
+	Organization   string    `json:"organization"`
+	ImpersonatedBy string    `json:"impersonated_by"` // 发起模拟登录的超级管理员
+}
+
+// Impersonation 模拟登录控制器
+type Impersonation struct {
+	impersonationService *service.ImpersonationService
+}
+
+// NewImpersonation creates a new Impersonation controller
+func NewImpersonation() *Impersonation {
+	return &Impersonation{
+		impersonationService: &service.ImpersonationService{},
+	}
+}
+
+// Start 以指定用户的身份登录（需要超级管理员权限）
+// @Summary      模拟登录
+// @Description  超级管理员以指定用户的身份获取限时访问令牌，用于排查用户遇到的问题。令牌中的 act 声明记录模拟者，使用该令牌的响应带有 X-Impersonated-By 头，每个请求都记录审计日志；修改密码、双因素认证和凭证管理等操作被禁止
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body ImpersonateRequest true "模拟登录信息"
+// @Success      200  {object}  ImpersonationResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /auth/impersonate [post]
+func (i *Impersonation) Start(c *gin.Context) {
+	var req ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	result, err := i.impersonationService.Start(currentUser, req.UserID, req.Reason, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ImpersonationResponse{
+		Token:          result.Token,
+		ExpiresIn:      result.ExpiresIn,
+		ExpiresAt:      result.ExpiresAt,
+		UserID:         result.User.ID,
+		Username:       result.User.Username,
+		Role:           result.User.Role,
+		Organization:   result.User.Organization.Code,
+		ImpersonatedBy: currentUser.Username,
+	})
+}
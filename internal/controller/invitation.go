@@ -0,0 +1,242 @@
+package controller
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateInvitationRequest 创建组织邀请请求
+type CreateInvitationRequest struct {
+	Email string `json:"email" binding:"required,email,max=128" example:"new@acme.io"`
+	Role  string `json:"role" binding:"omitempty,max=32" example:"org_member"` // 加入后的角色，可以是内置角色或组织的自定义角色，默认为组织成员
+}
+
+// AcceptInvitationRequest 接受邀请请求
+// 受邀邮箱已是组织成员时不需要用户名和密码
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" binding:"required"` // 邀请邮件中的令牌
+	Username string `json:"username" binding:"omitempty,min=3,max=32"`
+	Password string `json:"password" binding:"omitempty,max=128"`
+}
+
+// JoinInvitationRequest 已登录用户接受邀请请求
+type JoinInvitationRequest struct {
+	Token string `json:"token" binding:"required"` // 邀请邮件中的令牌
+}
+
+// AcceptInvitationResponse 接受邀请响应
+type AcceptInvitationResponse struct {
+	Created bool        `json:"created"` // 是否新建了账号
+	User    *model.User `json:"user"`
+}
+
+// Invitation 组织邀请控制器
+type Invitation struct {
+	invitationService *service.InvitationService
+}
+
+// NewInvitation creates a new Invitation controller
+func NewInvitation() *Invitation {
+	return &Invitation{
+		invitationService: &service.InvitationService{},
+	}
+}
+
+// Create 邀请用户加入组织
+// @Summary      创建组织邀请
+// @Description  向指定邮箱发送加入组织的一次性邀请链接，同一邮箱之前未接受的邀请自动作废；组织管理员只能邀请用户加入自己所在的组织及其下级组织
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int                      true  "组织ID"
+// @Param        request  body      CreateInvitationRequest  true  "邀请信息"
+// @Success      201  {object}  model.Invitation
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/invitations [post]
+func (i *Invitation) Create(c *gin.Context) {
+	var orgID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	var req CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	invitation, err := i.invitationService.Create(currentUser, orgID, req.Email, req.Role)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// List 获取组织待接受的邀请
+// @Summary      获取组织邀请列表
+// @Description  获取组织未接受、未撤销且未过期的邀请
+// @Tags         invitations
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "组织ID"
+// @Success      200  {array}   model.Invitation
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/invitations [get]
+func (i *Invitation) List(c *gin.Context) {
+	var orgID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	invitations, err := i.invitationService.List(currentUser, orgID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, invitations)
+}
+
+// Revoke 撤销组织邀请
+// @Summary      撤销组织邀请
+// @Description  撤销尚未接受的邀请，邀请链接立即失效
+// @Tags         invitations
+// @Produce      json
+// @Security     Bearer
+// @Param        id             path      int  true  "组织ID"
+// @Param        invitation_id  path      int  true  "邀请ID"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/invitations/{invitation_id} [delete]
+func (i *Invitation) Revoke(c *gin.Context) {
+	var orgID, invitationID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+	if _, err := fmt.Sscanf(c.Param("invitation_id"), "%d", &invitationID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "邀请ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := i.invitationService.Revoke(currentUser, orgID, invitationID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrInvitationNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "邀请已撤销"})
+}
+
+// Accept 接受组织邀请
+// @Summary      接受组织邀请
+// @Description  使用邀请邮件中的令牌加入组织。受邀邮箱已是组织成员时直接关联该用户，否则使用提供的用户名和密码创建账号；受邀邮箱视为已验证
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Param        request body AcceptInvitationRequest true "接受邀请信息"
+// @Success      200  {object}  AcceptInvitationResponse
+// @Failure      400  {object}  PasswordPolicyErrorResponse
+// @Router       /auth/invitations/accept [post]
+func (i *Invitation) Accept(c *gin.Context) {
+	var req AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, created, err := i.invitationService.Accept(req.Token, req.Username, req.Password)
+	if err != nil {
+		writePasswordError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, AcceptInvitationResponse{Created: created, User: user})
+}
+
+// Join 已登录的其他组织用户接受邀请
+// @Summary      以现有账号加入组织
+// @Description  已登录用户接受其他组织的邀请，以成员身份加入该组织而不创建新账号，之后可以通过切换组织进入。账号邮箱必须与受邀邮箱一致，本地账号还需已验证邮箱
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body JoinInvitationRequest true "邀请令牌"
+// @Success      200  {object}  model.Membership
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /auth/invitations/join [post]
+func (i *Invitation) Join(c *gin.Context) {
+	var req JoinInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	membership, err := i.invitationService.Join(currentUser.ID, req.Token)
+	if err != nil {
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, membership)
+}
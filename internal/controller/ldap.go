@@ -0,0 +1,233 @@
+package controller
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SaveLDAPConfigRequest 保存组织LDAP配置请求
+type SaveLDAPConfigRequest struct {
+	URL                string   `json:"url" binding:"required" example:"ldaps://ldap.example.com:636"`
+	InsecureSkipVerify *bool    `json:"insecure_skip_verify"`
+	BindDN             *string  `json:"bind_dn" example:"cn=readonly,dc=example,dc=com"`
+	BindPassword       *string  `json:"bind_password"` // 为空时保持原值
+	BaseDN             string   `json:"base_dn" binding:"required" example:"ou=people,dc=example,dc=com"`
+	UserFilter         string   `json:"user_filter" example:"(&(objectClass=person)(uid={username}))"`
+	UsernameAttribute  string   `json:"username_attribute" example:"uid"`
+	EmailAttribute     string   `json:"email_attribute" example:"mail"`
+	GroupAttribute     string   `json:"group_attribute" example:"memberOf"`
+	AdminGroups        []string `json:"admin_groups" example:"cn=admins,ou=groups,dc=example,dc=com"`
+	Enabled            *bool    `json:"enabled"`
+}
+
+// LinkLDAPUserRequest 关联目录账号请求
+type LinkLDAPUserRequest struct {
+	UserID   uint   `json:"user_id" binding:"required" example:"2"`
+	Username string `json:"username" example:"alice"` // 目录中的用户名，为空时使用本地用户名
+}
+
+// LDAP LDAP配置控制器
+type LDAP struct {
+	ldapService *service.LDAPService
+}
+
+// NewLDAP creates a new LDAP controller
+func NewLDAP() *LDAP {
+	return &LDAP{
+		ldapService: &service.LDAPService{},
+	}
+}
+
+// GetConfig 获取组织LDAP配置
+// @Summary      获取组织LDAP配置
+// @Description  获取组织的LDAP认证配置（不含服务账号密码），组织管理员只能查看自己所在的组织及其下级组织
+// @Tags         ldap
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "组织ID"
+// @Success      200  {object}  model.LDAPConfig
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/ldap [get]
+func (l *LDAP) GetConfig(c *gin.Context) {
+	id := c.Param("id")
+	var orgID uint
+	if _, err := fmt.Sscanf(id, "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	ldapConfig, err := l.ldapService.GetConfig(currentUser, orgID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, ldapConfig)
+}
+
+// SaveConfig 保存组织LDAP配置
+// @Summary      保存组织LDAP配置
+// @Description  创建或更新组织的LDAP认证配置，保存前会校验服务账号能否绑定。启用后组织成员登录时优先通过目录认证
+// @Tags         ldap
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id      path    int                    true  "组织ID"
+// @Param        request body    SaveLDAPConfigRequest  true  "LDAP配置"
+// @Success      200     {object} model.LDAPConfig
+// @Failure      400     {object} response.ErrorResponse
+// @Failure      401     {object} response.ErrorResponse
+// @Failure      403     {object} response.ErrorResponse
+// @Router       /organizations/{id}/ldap [put]
+func (l *LDAP) SaveConfig(c *gin.Context) {
+	id := c.Param("id")
+	var orgID uint
+	if _, err := fmt.Sscanf(id, "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	var req SaveLDAPConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	ldapConfig, err := l.ldapService.SaveConfig(currentUser, orgID, service.LDAPConfigSettings{
+		URL:                req.URL,
+		InsecureSkipVerify: req.InsecureSkipVerify,
+		BindDN:             req.BindDN,
+		BindPassword:       req.BindPassword,
+		BaseDN:             req.BaseDN,
+		UserFilter:         req.UserFilter,
+		UsernameAttribute:  req.UsernameAttribute,
+		EmailAttribute:     req.EmailAttribute,
+		GroupAttribute:     req.GroupAttribute,
+		AdminGroups:        req.AdminGroups,
+		Enabled:            req.Enabled,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, ldapConfig)
+}
+
+// DeleteConfig 删除组织LDAP配置
+// @Summary      删除组织LDAP配置
+// @Description  删除组织的LDAP认证配置，已关联的目录身份保留
+// @Tags         ldap
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "组织ID"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/ldap [delete]
+func (l *LDAP) DeleteConfig(c *gin.Context) {
+	id := c.Param("id")
+	var orgID uint
+	if _, err := fmt.Sscanf(id, "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := l.ldapService.DeleteConfig(currentUser, orgID); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "LDAP配置已删除"})
+}
+
+// LinkUser 关联目录账号
+// @Summary      关联目录账号
+// @Description  将组织中已有的本地用户关联到目录中的账号，之后该用户通过目录认证登录，角色按目录中的组同步。同名的本地用户不会在目录登录时自动关联
+// @Tags         ldap
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id      path    int                  true  "组织ID"
+// @Param        request body    LinkLDAPUserRequest  true  "关联信息"
+// @Success      201     {object} model.UserIdentity
+// @Failure      400     {object} response.ErrorResponse
+// @Failure      401     {object} response.ErrorResponse
+// @Failure      403     {object} response.ErrorResponse
+// @Failure      404     {object} response.ErrorResponse
+// @Router       /organizations/{id}/ldap/links [post]
+func (l *LDAP) LinkUser(c *gin.Context) {
+	id := c.Param("id")
+	var orgID uint
+	if _, err := fmt.Sscanf(id, "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	var req LinkLDAPUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	identity, err := l.ldapService.LinkUser(currentUser, orgID, req.UserID, req.Username)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrLDAPNotConfigured):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, identity)
+}
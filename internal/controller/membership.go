@@ -0,0 +1,231 @@
+package controller
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SwitchOrganizationRequest 切换组织请求
+type SwitchOrganizationRequest struct {
+	OrganizationID uint `json:"organization_id" binding:"required" example:"3"` // 所在组织或加入的组织
+}
+
+// UpdateMemberRequest 修改成员角色请求
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required,max=32" example:"org_member"` // 内置角色或组织的自定义角色
+}
+
+// Membership 多组织成员控制器
+type Membership struct {
+	membershipService *service.MembershipService
+}
+
+// NewMembership creates a new Membership controller
+func NewMembership() *Membership {
+	return &Membership{
+		membershipService: &service.MembershipService{},
+	}
+}
+
+// Organizations 获取当前用户可以进入的组织
+// @Summary      获取我的组织
+// @Description  获取当前用户的所在组织和通过邀请加入的组织，current 标记当前令牌所在的组织
+// @Tags         organizations
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {array}   service.OrganizationMembership
+// @Failure      401  {object}  response.ErrorResponse
+// @Router       /auth/organizations [get]
+func (m *Membership) Organizations(c *gin.Context) {
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	organizations, err := m.membershipService.Organizations(currentUser.ID, currentUser.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, organizations)
+}
+
+// Switch 切换到其他组织
+// @Summary      切换组织
+// @Description  签发进入所在组织或加入的组织的令牌对，开始一个新的登录会话；新令牌按用户在该组织中的角色授权，当前会话不受影响
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body SwitchOrganizationRequest true "目标组织"
+// @Success      200  {object}  LoginResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /auth/switch-organization [post]
+func (m *Membership) Switch(c *gin.Context) {
+	var req SwitchOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	switched, pair, err := m.membershipService.Switch(currentUser.ID, req.OrganizationID, clientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotMember), isAccountStatusError(err):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, newLoginResponse(switched, pair))
+}
+
+// List 获取从其他组织加入本组织的成员
+// @Summary      获取组织成员
+// @Description  获取通过邀请从其他组织加入本组织的成员及其在本组织中的角色，本组织自己的用户见用户列表
+// @Tags         organizations
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "组织ID"
+// @Success      200  {array}   service.MemberInfo
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/members [get]
+func (m *Membership) List(c *gin.Context) {
+	var orgID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	members, err := m.membershipService.List(currentUser, orgID)
+	if err != nil {
+		writeMembershipError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+// Update 修改成员在本组织中的角色
+// @Summary      修改成员角色
+// @Description  修改从其他组织加入的成员在本组织中的角色，不能分配超出操作者权限的角色
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int                  true  "组织ID"
+// @Param        user_id  path      int                  true  "用户ID"
+// @Param        request  body      UpdateMemberRequest  true  "角色"
+// @Success      200  {object}  model.Membership
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/members/{user_id} [put]
+func (m *Membership) Update(c *gin.Context) {
+	var orgID, userID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+	if _, err := fmt.Sscanf(c.Param("user_id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+
+	var req UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	membership, err := m.membershipService.UpdateRole(currentUser, orgID, userID, req.Role)
+	if err != nil {
+		writeMembershipError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, membership)
+}
+
+// Remove 将成员移出本组织
+// @Summary      移除组织成员
+// @Description  将从其他组织加入的成员移出本组织，该成员进入本组织的令牌和会话立即失效，其所在组织的账号不受影响
+// @Tags         organizations
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int  true  "组织ID"
+// @Param        user_id  path      int  true  "用户ID"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/members/{user_id} [delete]
+func (m *Membership) Remove(c *gin.Context) {
+	var orgID, userID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+	if _, err := fmt.Sscanf(c.Param("user_id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := m.membershipService.Remove(currentUser, orgID, userID); err != nil {
+		writeMembershipError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "成员已移除"})
+}
+
+// writeMembershipError 将成员管理错误转换为响应
+func writeMembershipError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrMembershipNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,225 @@
+package controller
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MFAChallengeResponse 需要双因素认证时的登录响应
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required" example:"true"`
+	MFAToken    string `json:"mfa_token"`  // 双因素认证挑战令牌
+	ExpiresIn   int64  `json:"expires_in"` // 挑战有效期（秒）
+}
+
+// MFAVerifyRequest 双因素认证校验请求
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"` // TOTP验证码或恢复码
+}
+
+// MFACodeRequest 验证码请求
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"` // TOTP验证码或恢复码
+}
+
+// MFAEnrollResponse 双因素认证注册响应
+type MFAEnrollResponse struct {
+	Secret     string `json:"secret"`      // TOTP密钥（base32）
+	OtpauthURI string `json:"otpauth_uri"` // 供认证器应用扫码的URI
+}
+
+// MFARecoveryCodesResponse 恢复码响应
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"` // 恢复码，仅展示一次
+}
+
+// MFA 双因素认证控制器
+type MFA struct {
+	authService *service.AuthService
+	mfaService  *service.MFAService
+}
+
+// NewMFA creates a new MFA controller
+func NewMFA() *MFA {
+	return &MFA{
+		authService: &service.AuthService{},
+		mfaService:  &service.MFAService{},
+	}
+}
+
+// Verify 完成双因素认证登录
+// @Summary      双因素认证登录
+// @Description  使用登录返回的挑战令牌和TOTP验证码（或恢复码）换取访问令牌。验证码错误与密码错误计入同一登录失败计数，多次登录累计达到阈值时锁定并返回429
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Param        request body MFAVerifyRequest true "验证信息"
+// @Success      200  {object}  LoginResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      429  {object}  response.ErrorResponse
+// @Router       /auth/mfa/verify [post]
+func (m *MFA) Verify(c *gin.Context) {
+	var req MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, pair, err := m.authService.VerifyMFA(req.MFAToken, req.Code, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidMFAChallenge) || errors.Is(err, service.ErrInvalidMFACode) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		var throttled *service.LoginThrottledError
+		if errors.As(err, &throttled) || isAccountStatusError(err) {
+			writeLoginError(c, err)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newLoginResponse(user, pair))
+}
+
+// Enroll 生成双因素认证密钥
+// @Summary      注册双因素认证
+// @Description  生成TOTP密钥和otpauth URI，需调用确认接口后才会启用
+// @Tags         mfa
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {object}  MFAEnrollResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Router       /auth/mfa/totp/enroll [post]
+func (m *MFA) Enroll(c *gin.Context) {
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	enrollment, err := m.mfaService.Enroll(currentUser)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MFAEnrollResponse{
+		Secret:     enrollment.Secret,
+		OtpauthURI: enrollment.URI,
+	})
+}
+
+// Confirm 确认并启用双因素认证
+// @Summary      确认双因素认证
+// @Description  使用认证器应用生成的验证码确认注册，成功后返回恢复码
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body MFACodeRequest true "验证码"
+// @Success      200  {object}  MFARecoveryCodesResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Router       /auth/mfa/totp/confirm [post]
+func (m *MFA) Confirm(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	codes, err := m.mfaService.Confirm(currentUser, req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MFARecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Disable 关闭双因素认证
+// @Summary      关闭双因素认证
+// @Description  使用验证码或恢复码关闭双因素认证，组织要求启用时不能关闭
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body MFACodeRequest true "验证码"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Router       /auth/mfa/totp/disable [post]
+func (m *MFA) Disable(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := m.mfaService.Disable(currentUser, req.Code); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "双因素认证已关闭"})
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+// @Summary      重新生成恢复码
+// @Description  使用验证码重新生成恢复码，旧恢复码全部失效
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body MFACodeRequest true "验证码"
+// @Success      200  {object}  MFARecoveryCodesResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Router       /auth/mfa/recovery-codes [post]
+func (m *MFA) RegenerateRecoveryCodes(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	codes, err := m.mfaService.RegenerateRecoveryCodes(currentUser, req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MFARecoveryCodesResponse{RecoveryCodes: codes})
+}
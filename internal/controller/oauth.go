@@ -0,0 +1,313 @@
+package controller
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OAuthTokenResponse OAuth2 令牌响应（RFC 6749 第5.1节）
+type OAuthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type" example:"Bearer"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope,omitempty" example:"orgs:read"`
+}
+
+// OAuthErrorResponse OAuth2 错误响应（RFC 6749 第5.2节）
+type OAuthErrorResponse struct {
+	Error            string `json:"error" example:"invalid_client"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// CreateOAuthClientRequest 创建OAuth2客户端请求
+type CreateOAuthClientRequest struct {
+	Name           string   `json:"name" binding:"required,max=64" example:"billing-service"`
+	Scopes         []string `json:"scopes" binding:"required,min=1" example:"orgs:read"`
+	OrganizationID uint     `json:"organization_id"`                           // 为空时为操作者所在的组织，组织管理员只能指定本组织或其下级组织
+	Role           string   `json:"role" binding:"max=32" example:"org_admin"` // 服务账号的角色，默认为组织成员，不能超出操作者的权限
+}
+
+// UpdateOAuthClientRoleRequest 修改OAuth2客户端角色请求
+type UpdateOAuthClientRoleRequest struct {
+	Role string `json:"role" binding:"required,max=32" example:"org_admin"` // 内置角色或组织的自定义角色
+}
+
+// OAuthClientSecretResponse 包含客户端密钥的响应，密钥仅展示一次
+type OAuthClientSecretResponse struct {
+	ClientSecret string             `json:"client_secret"`
+	Client       *model.OAuthClient `json:"client"`
+}
+
+// OAuth OAuth2控制器
+type OAuth struct {
+	oauthClientService *service.OAuthClientService
+}
+
+// NewOAuth creates a new OAuth controller
+func NewOAuth() *OAuth {
+	return &OAuth{
+		oauthClientService: &service.OAuthClientService{},
+	}
+}
+
+// Token 令牌端点
+// @Summary      OAuth2 令牌端点
+// @Description  客户端凭证模式（grant_type=client_credentials），客户端可通过 HTTP Basic 或表单参数认证
+// @Tags         oauth
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Param        grant_type     formData  string  true   "授权类型" Enums(client_credentials)
+// @Param        client_id      formData  string  false  "客户端ID（未使用 HTTP Basic 时必填）"
+// @Param        client_secret  formData  string  false  "客户端密钥（未使用 HTTP Basic 时必填）"
+// @Param        scope          formData  string  false  "申请的访问范围，空格分隔，为空时授予全部"
+// @Success      200  {object}  OAuthTokenResponse
+// @Failure      400  {object}  OAuthErrorResponse
+// @Failure      401  {object}  OAuthErrorResponse
+// @Router       /oauth/token [post]
+func (o *OAuth) Token(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	if grantType := c.PostForm("grant_type"); grantType != "client_credentials" {
+		code := service.OAuthErrUnsupportedGrantType
+		if grantType == "" {
+			code = service.OAuthErrInvalidRequest
+		}
+		c.JSON(http.StatusBadRequest, OAuthErrorResponse{Error: code, ErrorDescription: "仅支持 client_credentials 授权类型"})
+		return
+	}
+
+	clientID, clientSecret, basic := c.Request.BasicAuth()
+	if !basic {
+		clientID = c.PostForm("client_id")
+		clientSecret = c.PostForm("client_secret")
+	}
+	if clientID == "" || clientSecret == "" {
+		c.JSON(http.StatusUnauthorized, OAuthErrorResponse{Error: service.OAuthErrInvalidClient, ErrorDescription: "缺少客户端凭证"})
+		return
+	}
+
+	result, err := o.oauthClientService.IssueToken(clientID, clientSecret, c.PostForm("scope"))
+	if err != nil {
+		var oauthErr *service.OAuthError
+		if !errors.As(err, &oauthErr) {
+			oauthErr = &service.OAuthError{Code: service.OAuthErrServerError, Description: err.Error()}
+		}
+
+		status := http.StatusBadRequest
+		switch oauthErr.Code {
+		case service.OAuthErrInvalidClient:
+			status = http.StatusUnauthorized
+			if basic {
+				c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+			}
+		case service.OAuthErrServerError:
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, OAuthErrorResponse{Error: oauthErr.Code, ErrorDescription: oauthErr.Description})
+		return
+	}
+
+	c.JSON(http.StatusOK, OAuthTokenResponse{
+		AccessToken: result.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   result.ExpiresIn,
+		Scope:       result.Scope,
+	})
+}
+
+// CreateClient 创建OAuth2客户端
+// @Summary      创建OAuth2客户端
+// @Description  在组织内注册客户端凭证模式的客户端，客户端密钥只在响应中返回一次。客户端令牌可以访问的接口同时受服务账号角色的权限和令牌访问范围限制，角色不能超出操作者自己的权限
+// @Tags         oauth
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body CreateOAuthClientRequest true "客户端信息"
+// @Success      201  {object}  OAuthClientSecretResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /oauth/clients [post]
+func (o *OAuth) CreateClient(c *gin.Context) {
+	var req CreateOAuthClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	client, secret, err := o.oauthClientService.Create(currentUser, req.OrganizationID, req.Name, req.Scopes, req.Role)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, OAuthClientSecretResponse{ClientSecret: secret, Client: client})
+}
+
+// ListClients 获取OAuth2客户端列表
+// @Summary      获取OAuth2客户端列表
+// @Description  超级管理员获取全部客户端，组织管理员获取本组织及其下级组织的客户端（不含密钥）
+// @Tags         oauth
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {array}   model.OAuthClient
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      500  {object}  response.ErrorResponse
+// @Router       /oauth/clients [get]
+func (o *OAuth) ListClients(c *gin.Context) {
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	clients, err := o.oauthClientService.List(currentUser)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, clients)
+}
+
+// RotateSecret 轮换OAuth2客户端密钥
+// @Summary      轮换OAuth2客户端密钥
+// @Description  生成新的客户端密钥，旧密钥在配置的宽限期内仍然有效
+// @Tags         oauth
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "客户端记录ID"
+// @Success      200  {object}  OAuthClientSecretResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /oauth/clients/{id}/rotate-secret [post]
+func (o *OAuth) RotateSecret(c *gin.Context) {
+	id := c.Param("id")
+	var clientID uint
+	if _, err := fmt.Sscanf(id, "%d", &clientID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "客户端ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	client, secret, err := o.oauthClientService.RotateSecret(currentUser, clientID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, OAuthClientSecretResponse{ClientSecret: secret, Client: client})
+}
+
+// UpdateClientRole 修改OAuth2客户端的角色
+// @Summary      修改OAuth2客户端角色
+// @Description  修改客户端服务账号的角色，对客户端已签发的令牌立即生效。只能修改权限不超过自己的客户端，也只能分配不超过自己权限的角色
+// @Tags         oauth
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int                           true  "客户端记录ID"
+// @Param        request  body      UpdateOAuthClientRoleRequest  true  "角色"
+// @Success      200  {object}  model.OAuthClient
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /oauth/clients/{id}/role [put]
+func (o *OAuth) UpdateClientRole(c *gin.Context) {
+	var clientID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &clientID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "客户端ID无效"})
+		return
+	}
+
+	var req UpdateOAuthClientRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	client, err := o.oauthClientService.UpdateRole(currentUser, clientID, req.Role)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, client)
+}
+
+// RevokeClient 撤销OAuth2客户端
+// @Summary      撤销OAuth2客户端
+// @Description  撤销客户端，客户端已签发的访问令牌立即失效
+// @Tags         oauth
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "客户端记录ID"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /oauth/clients/{id} [delete]
+func (o *OAuth) RevokeClient(c *gin.Context) {
+	id := c.Param("id")
+	var clientID uint
+	if _, err := fmt.Sscanf(id, "%d", &clientID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "客户端ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := o.oauthClientService.Revoke(currentUser, clientID); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "客户端已撤销"})
+}
@@ -0,0 +1,234 @@
+package controller
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SaveOIDCProviderRequest 保存组织OIDC配置请求
+type SaveOIDCProviderRequest struct {
+	Issuer          string   `json:"issuer" binding:"required,url" example:"https://idp.example.com"`
+	ClientID        string   `json:"client_id" binding:"required" example:"windz"`
+	ClientSecret    *string  `json:"client_secret"` // 为空时保持原值，公共客户端传空字符串
+	Scopes          []string `json:"scopes" example:"openid,profile,email"`
+	UsernameClaim   string   `json:"username_claim" example:"preferred_username"`
+	EmailClaim      string   `json:"email_claim" example:"email"`
+	RoleClaim       *string  `json:"role_claim" example:"groups"`
+	AdminRoleValues []string `json:"admin_role_values" example:"windz-admins"`
+	AutoProvision   *bool    `json:"auto_provision"`
+	Enabled         *bool    `json:"enabled"`
+}
+
+// OIDC OpenID Connect 控制器
+type OIDC struct {
+	authService *service.AuthService
+	oidcService *service.OIDCService
+}
+
+// NewOIDC creates a new OIDC controller
+func NewOIDC() *OIDC {
+	return &OIDC{
+		authService: &service.AuthService{},
+		oidcService: &service.OIDCService{},
+	}
+}
+
+// Authorize 发起OIDC登录
+// @Summary      发起OIDC登录
+// @Description  重定向到组织配置的身份提供方，使用授权码模式和PKCE
+// @Tags         oidc
+// @Param        org_code  path  string  true  "组织代码"
+// @Success      302
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /auth/oidc/{org_code}/authorize [get]
+func (o *OIDC) Authorize(c *gin.Context) {
+	authURL, err := o.oidcService.AuthorizationURL(c.Request.Context(), c.Param("org_code"))
+	if err != nil {
+		if errors.Is(err, service.ErrOIDCNotConfigured) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback OIDC授权回调
+// @Summary      OIDC授权回调
+// @Description  身份提供方认证完成后回调，校验ID令牌后查找或创建组织内的用户并签发令牌
+// @Tags         oidc
+// @Produce      json
+// @Param        code   query  string  true  "授权码"
+// @Param        state  query  string  true  "授权请求state"
+// @Success      200  {object}  LoginResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Router       /auth/oidc/callback [get]
+func (o *OIDC) Callback(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+
+	if errCode := c.Query("error"); errCode != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "身份提供方拒绝了登录请求: " + errCode})
+		return
+	}
+
+	code, state := c.Query("code"), c.Query("state")
+	if code == "" || state == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少code或state参数"})
+		return
+	}
+
+	result, err := o.authService.OIDCLogin(c.Request.Context(), code, state, clientInfo(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	writeLoginResult(c, result)
+}
+
+// GetProvider 获取组织OIDC配置
+// @Summary      获取组织OIDC配置
+// @Description  获取组织的身份提供方配置（不含客户端密钥），组织管理员只能查看自己所在的组织及其下级组织
+// @Tags         oidc
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "组织ID"
+// @Success      200  {object}  model.OIDCProvider
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/oidc [get]
+func (o *OIDC) GetProvider(c *gin.Context) {
+	id := c.Param("id")
+	var orgID uint
+	if _, err := fmt.Sscanf(id, "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	provider, err := o.oidcService.GetProvider(currentUser, orgID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, provider)
+}
+
+// SaveProvider 保存组织OIDC配置
+// @Summary      保存组织OIDC配置
+// @Description  创建或更新组织的身份提供方配置，保存前会校验发现文档是否可访问
+// @Tags         oidc
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id      path    int                      true  "组织ID"
+// @Param        request body    SaveOIDCProviderRequest  true  "OIDC配置"
+// @Success      200     {object} model.OIDCProvider
+// @Failure      400     {object} response.ErrorResponse
+// @Failure      401     {object} response.ErrorResponse
+// @Failure      403     {object} response.ErrorResponse
+// @Router       /organizations/{id}/oidc [put]
+func (o *OIDC) SaveProvider(c *gin.Context) {
+	id := c.Param("id")
+	var orgID uint
+	if _, err := fmt.Sscanf(id, "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	var req SaveOIDCProviderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	provider, err := o.oidcService.SaveProvider(currentUser, orgID, service.OIDCProviderSettings{
+		Issuer:          req.Issuer,
+		ClientID:        req.ClientID,
+		ClientSecret:    req.ClientSecret,
+		Scopes:          req.Scopes,
+		UsernameClaim:   req.UsernameClaim,
+		EmailClaim:      req.EmailClaim,
+		RoleClaim:       req.RoleClaim,
+		AdminRoleValues: req.AdminRoleValues,
+		AutoProvision:   req.AutoProvision,
+		Enabled:         req.Enabled,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, provider)
+}
+
+// DeleteProvider 删除组织OIDC配置
+// @Summary      删除组织OIDC配置
+// @Description  删除组织的身份提供方配置，已关联的外部身份保留
+// @Tags         oidc
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "组织ID"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/oidc [delete]
+func (o *OIDC) DeleteProvider(c *gin.Context) {
+	id := c.Param("id")
+	var orgID uint
+	if _, err := fmt.Sscanf(id, "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := o.oidcService.DeleteProvider(currentUser, orgID); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "OIDC配置已删除"})
+}
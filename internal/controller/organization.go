@@ -1,7 +1,9 @@
 package controller
 
 import (
+	"backend/internal/model"
 	"backend/internal/service"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -12,6 +14,18 @@ import (
 type CreateOrganizationRequest struct {
 	Code        string `json:"code" binding:"required"`
 	Description string `json:"description"`
+	ParentID    *uint  `json:"parent_id" example:"2"` // 上级组织ID，为空表示顶级组织
+}
+
+// CreateChildOrganizationRequest 创建下级组织请求
+type CreateChildOrganizationRequest struct {
+	Code        string `json:"code" binding:"required"`
+	Description string `json:"description"`
+}
+
+// MoveOrganizationRequest 移动组织请求
+type MoveOrganizationRequest struct {
+	ParentID *uint `json:"parent_id" example:"2"` // 新的上级组织ID，为空或0表示成为顶级组织，只有超级管理员可以指定
 }
 
 // UpdateOrganizationRequest 更新组织请求
@@ -20,6 +34,14 @@ type UpdateOrganizationRequest struct {
 	Description string `json:"description"`
 }
 
+// UpdateOrganizationSettingsRequest 更新组织安全设置请求，未提供的字段保持不变
+type UpdateOrganizationSettingsRequest struct {
+	RequireMFA               *bool    `json:"require_mfa"`                                                             // 是否要求成员启用双因素认证
+	RequireEmailVerification *bool    `json:"require_email_verification"`                                              // 是否要求本地账号验证邮箱后才能登录
+	RegistrationMode         *string  `json:"registration_mode" binding:"omitempty,oneof=open closed domain approval"` // 自助注册方式
+	RegistrationDomains      []string `json:"registration_domains" example:"acme.com"`                                 // 注册方式为 domain 时允许的邮箱域名
+}
+
 // Organization 组织控制器
 type Organization struct {
 	orgService *service.OrganizationService
@@ -34,7 +56,7 @@ func NewOrganization() *Organization {
 
 // Create 创建组织
 // @Summary      创建组织
-// @Description  创建新的组织
+// @Description  创建新的组织，指定 parent_id 时创建为该组织的下级组织
 // @Tags         organizations
 // @Accept       json
 // @Produce      json
@@ -43,6 +65,7 @@ func NewOrganization() *Organization {
 // @Success      201  {object}  model.Organization
 // @Failure      400  {object}  response.ErrorResponse
 // @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
 // @Router       /organizations [post]
 func (o *Organization) Create(c *gin.Context) {
 	var req CreateOrganizationRequest
@@ -51,9 +74,16 @@ func (o *Organization) Create(c *gin.Context) {
 		return
 	}
 
-	org, err := o.orgService.Create(req.Code, req.Description)
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	org, err := o.orgService.Create(currentUser, req.Code, req.Description, req.ParentID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeOrganizationError(c, err)
 		return
 	}
 
@@ -139,7 +169,7 @@ func (o *Organization) Update(c *gin.Context) {
 
 	org, err := o.orgService.Update(orgID, req.Code, req.Description)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "未找到该组织"})
+		writeOrganizationError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, org)
@@ -147,13 +177,14 @@ func (o *Organization) Update(c *gin.Context) {
 
 // Delete 删除组织
 // @Summary      删除组织
-// @Description  根据ID删除组织
+// @Description  根据ID删除组织，组织下仍有用户、加入的成员、未撤销的OAuth2客户端或下级组织时不能删除；团队、自定义角色、邀请、OIDC和LDAP配置等一并删除
 // @Tags         organizations
 // @Accept       json
 // @Produce      json
 // @Security     Bearer
 // @Param        id   path      int  true  "组织ID"
 // @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
 // @Failure      401  {object}  response.ErrorResponse
 // @Failure      404  {object}  response.ErrorResponse
 // @Router       /organizations/{id} [delete]
@@ -166,8 +197,195 @@ func (o *Organization) Delete(c *gin.Context) {
 	}
 
 	if err := o.orgService.Delete(orgID); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "未找到该组织"})
+		writeOrganizationError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "组织删除成功"})
 }
+
+// UpdateSettings 更新组织安全设置
+// @Summary      更新组织安全设置
+// @Description  更新组织安全设置，组织管理员只能修改自己所在的组织及其下级组织
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id      path    int                               true  "组织ID"
+// @Param        request body    UpdateOrganizationSettingsRequest true  "组织安全设置"
+// @Success      200     {object} model.Organization
+// @Failure      400     {object} response.ErrorResponse
+// @Failure      401     {object} response.ErrorResponse
+// @Failure      403     {object} response.ErrorResponse
+// @Router       /organizations/{id}/settings [put]
+func (o *Organization) UpdateSettings(c *gin.Context) {
+	id := c.Param("id")
+	var orgID uint
+	if _, err := fmt.Sscanf(id, "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	var req UpdateOrganizationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求数据无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	org, err := o.orgService.UpdateSettings(currentUser, orgID, service.OrganizationSettings{
+		RequireMFA:               req.RequireMFA,
+		RequireEmailVerification: req.RequireEmailVerification,
+		RegistrationMode:         req.RegistrationMode,
+		RegistrationDomains:      req.RegistrationDomains,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, org)
+}
+
+// Descendants 获取下级组织
+// @Summary      获取下级组织
+// @Description  获取组织的全部下级组织，按层级由近到远排列。组织管理员可以查看自己所在组织及其下级组织的子树
+// @Tags         organizations
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "组织ID"
+// @Success      200  {array}   model.Organization
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/descendants [get]
+func (o *Organization) Descendants(c *gin.Context) {
+	var orgID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	orgs, err := o.orgService.Descendants(currentUser, orgID)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+	if orgs == nil {
+		orgs = []model.Organization{}
+	}
+	c.JSON(http.StatusOK, orgs)
+}
+
+// CreateChild 创建下级组织
+// @Summary      创建下级组织
+// @Description  在组织之下创建下级组织，上级组织的管理员可以管理下级组织
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int                             true  "上级组织ID"
+// @Param        request  body      CreateChildOrganizationRequest  true  "组织信息"
+// @Success      201  {object}  model.Organization
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/children [post]
+func (o *Organization) CreateChild(c *gin.Context) {
+	var orgID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	var req CreateChildOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求数据无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	org, err := o.orgService.Create(currentUser, req.Code, req.Description, &orgID)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, org)
+}
+
+// Move 移动组织
+// @Summary      移动组织
+// @Description  将组织连同其下级组织移动到新的上级组织之下，不能移动到自己或下级组织之下。组织管理员只能在自己管理的子树内移动下级组织
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int                      true  "组织ID"
+// @Param        request  body      MoveOrganizationRequest  true  "新的上级组织"
+// @Success      200  {object}  model.Organization
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/move [post]
+func (o *Organization) Move(c *gin.Context) {
+	var orgID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	var req MoveOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求数据无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	org, err := o.orgService.Move(currentUser, orgID, req.ParentID)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, org)
+}
+
+// writeOrganizationError 将组织层级错误转换为响应
+func writeOrganizationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrOrganizationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
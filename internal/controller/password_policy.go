@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SavePasswordPolicyRequest 保存组织密码策略请求，省略或为 null 的字段沿用全局配置
+type SavePasswordPolicyRequest struct {
+	MinLength        *int     `json:"min_length" example:"10"`
+	MaxLength        *int     `json:"max_length" example:"64"`
+	RequireUppercase *bool    `json:"require_uppercase"`
+	RequireLowercase *bool    `json:"require_lowercase"`
+	RequireDigit     *bool    `json:"require_digit"`
+	RequireSymbol    *bool    `json:"require_symbol"`
+	DisallowUsername *bool    `json:"disallow_username"`
+	BannedWords      []string `json:"banned_words" example:"acme"`
+	HistoryDepth     *int     `json:"history_depth" example:"5"`
+	MaxAgeDays       *int     `json:"max_age_days" example:"90"`
+}
+
+// PasswordPolicyResponse 组织密码策略
+type PasswordPolicyResponse struct {
+	Policy    *model.PasswordPolicy `json:"policy"`    // 组织覆盖项
+	Effective service.PasswordRules `json:"effective"` // 合并全局配置后生效的策略
+}
+
+// PasswordPolicy 密码策略控制器
+type PasswordPolicy struct {
+	passwordService *service.PasswordPolicyService
+}
+
+// NewPasswordPolicy creates a new PasswordPolicy controller
+func NewPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		passwordService: &service.PasswordPolicyService{},
+	}
+}
+
+// Get 获取组织密码策略
+// @Summary      获取组织密码策略
+// @Description  获取组织的密码策略覆盖项和合并全局配置后生效的策略，组织管理员只能查看自己所在的组织及其下级组织
+// @Tags         password-policy
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "组织ID"
+// @Success      200  {object}  PasswordPolicyResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/password-policy [get]
+func (p *PasswordPolicy) Get(c *gin.Context) {
+	id := c.Param("id")
+	var orgID uint
+	if _, err := fmt.Sscanf(id, "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	policy, rules, err := p.passwordService.GetPolicy(currentUser, orgID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, PasswordPolicyResponse{Policy: policy, Effective: rules})
+}
+
+// Save 保存组织密码策略
+// @Summary      保存组织密码策略
+// @Description  整体替换组织的密码策略覆盖项，为 null 的字段沿用全局配置，禁用词与全局禁用词同时生效
+// @Tags         password-policy
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id      path    int                        true  "组织ID"
+// @Param        request body    SavePasswordPolicyRequest  true  "密码策略"
+// @Success      200     {object} PasswordPolicyResponse
+// @Failure      400     {object} response.ErrorResponse
+// @Failure      401     {object} response.ErrorResponse
+// @Failure      403     {object} response.ErrorResponse
+// @Router       /organizations/{id}/password-policy [put]
+func (p *PasswordPolicy) Save(c *gin.Context) {
+	id := c.Param("id")
+	var orgID uint
+	if _, err := fmt.Sscanf(id, "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	var req SavePasswordPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	policy, rules, err := p.passwordService.SavePolicy(currentUser, orgID, service.PasswordPolicySettings{
+		MinLength:        req.MinLength,
+		MaxLength:        req.MaxLength,
+		RequireUppercase: req.RequireUppercase,
+		RequireLowercase: req.RequireLowercase,
+		RequireDigit:     req.RequireDigit,
+		RequireSymbol:    req.RequireSymbol,
+		DisallowUsername: req.DisallowUsername,
+		BannedWords:      req.BannedWords,
+		HistoryDepth:     req.HistoryDepth,
+		MaxAgeDays:       req.MaxAgeDays,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, PasswordPolicyResponse{Policy: policy, Effective: rules})
+}
+
+// Delete 删除组织密码策略
+// @Summary      删除组织密码策略
+// @Description  删除组织的密码策略覆盖项，恢复使用全局配置
+// @Tags         password-policy
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "组织ID"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/password-policy [delete]
+func (p *PasswordPolicy) Delete(c *gin.Context) {
+	id := c.Param("id")
+	var orgID uint
+	if _, err := fmt.Sscanf(id, "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := p.passwordService.DeletePolicy(currentUser, orgID); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "密码策略已删除"})
+}
@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RejectRegistrationRequest 拒绝注册请求
+type RejectRegistrationRequest struct {
+	Reason string `json:"reason" binding:"max=256"` // 可选，拒绝原因会发送给用户
+}
+
+// Registration 注册审核控制器
+type Registration struct {
+	registrationService *service.RegistrationService
+}
+
+// NewRegistration creates a new Registration controller
+func NewRegistration() *Registration {
+	return &Registration{
+		registrationService: &service.RegistrationService{},
+	}
+}
+
+// List 获取组织等待审核的注册
+// @Summary      获取待审核注册列表
+// @Description  注册方式为 approval 的组织中，新注册的用户处于 pending 状态，审核通过前不能登录
+// @Tags         registrations
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "组织ID"
+// @Success      200  {array}   model.User
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/registrations [get]
+func (r *Registration) List(c *gin.Context) {
+	var orgID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	users, err := r.registrationService.ListPending(currentUser, orgID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// Approve 通过注册审核
+// @Summary      通过注册审核
+// @Description  通过后用户可以登录，并收到审核结果邮件
+// @Tags         registrations
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int  true  "组织ID"
+// @Param        user_id  path      int  true  "用户ID"
+// @Success      200  {object}  model.User
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/registrations/{user_id}/approve [post]
+func (r *Registration) Approve(c *gin.Context) {
+	var orgID, userID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+	if _, err := fmt.Sscanf(c.Param("user_id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	approved, err := r.registrationService.Approve(currentUser, orgID, userID)
+	if err != nil {
+		writeRegistrationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, approved)
+}
+
+// Reject 拒绝注册
+// @Summary      拒绝注册
+// @Description  删除等待审核的账号，用户名和邮箱可以重新注册；用户会收到包含拒绝原因的邮件
+// @Tags         registrations
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int                        true   "组织ID"
+// @Param        user_id  path      int                        true   "用户ID"
+// @Param        request  body      RejectRegistrationRequest  false  "拒绝原因"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/registrations/{user_id}/reject [post]
+func (r *Registration) Reject(c *gin.Context) {
+	var orgID, userID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+	if _, err := fmt.Sscanf(c.Param("user_id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+
+	var req RejectRegistrationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := r.registrationService.Reject(currentUser, orgID, userID, req.Reason); err != nil {
+		writeRegistrationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "已拒绝注册"})
+}
+
+// writeRegistrationError 写入审核注册失败的响应
+func writeRegistrationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrRegistrationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
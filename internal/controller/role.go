@@ -0,0 +1,264 @@
+package controller
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateRoleRequest 创建自定义角色请求
+type CreateRoleRequest struct {
+	Name        string   `json:"name" binding:"required,max=32" example:"helpdesk"` // 角色名称，创建后不能修改
+	Description string   `json:"description" binding:"max=256" example:"客服，可以查看和撤销用户会话"`
+	Permissions []string `json:"permissions" example:"session.read,session.revoke"` // 只能包含组织级权限，且不能超出操作者自己拥有的权限
+}
+
+// UpdateRoleRequest 修改自定义角色请求，权限整体替换
+type UpdateRoleRequest struct {
+	Description string   `json:"description" binding:"max=256" example:"客服，可以查看和撤销用户会话"`
+	Permissions []string `json:"permissions" example:"session.read,session.revoke"`
+}
+
+// PermissionsResponse 当前用户的权限
+type PermissionsResponse struct {
+	Role        string   `json:"role" example:"org_admin"`
+	Permissions []string `json:"permissions"`
+}
+
+// Role 角色和权限控制器
+type Role struct {
+	roleService *service.RoleService
+}
+
+// NewRole creates a new Role controller
+func NewRole() *Role {
+	return &Role{
+		roleService: &service.RoleService{},
+	}
+}
+
+// ListPermissions 获取权限目录
+// @Summary      获取权限目录
+// @Description  获取全部权限及说明，平台级权限只属于超级管理员，不能加入自定义角色
+// @Tags         roles
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {array}   model.Permission
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /permissions [get]
+func (r *Role) ListPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, model.Permissions)
+}
+
+// Mine 获取当前用户的权限
+// @Summary      获取当前用户的权限
+// @Description  获取当前用户的角色及其拥有的权限，供前端决定展示哪些功能
+// @Tags         roles
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {object}  PermissionsResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Router       /auth/permissions [get]
+func (r *Role) Mine(c *gin.Context) {
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	permissions := r.roleService.Permissions(currentUser)
+	if permissions == nil {
+		permissions = model.StringList{}
+	}
+	c.JSON(http.StatusOK, PermissionsResponse{Role: currentUser.Role, Permissions: permissions})
+}
+
+// List 获取组织可分配的角色
+// @Summary      获取组织角色列表
+// @Description  获取组织可分配的角色，包括内置的组织管理员、组织成员角色和组织的自定义角色
+// @Tags         roles
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "组织ID"
+// @Success      200  {array}   service.RoleInfo
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/roles [get]
+func (r *Role) List(c *gin.Context) {
+	var orgID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	roles, err := r.roleService.List(currentUser, orgID)
+	if err != nil {
+		writeRoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+// Create 创建组织自定义角色
+// @Summary      创建自定义角色
+// @Description  在组织内创建自定义角色。角色只能包含组织级权限，且不能超出操作者自己拥有的权限
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int                true  "组织ID"
+// @Param        request  body      CreateRoleRequest  true  "角色信息"
+// @Success      201  {object}  model.Role
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/roles [post]
+func (r *Role) Create(c *gin.Context) {
+	var orgID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	var req CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	role, err := r.roleService.Create(currentUser, orgID, req.Name, service.RoleSettings{
+		Description: req.Description,
+		Permissions: req.Permissions,
+	})
+	if err != nil {
+		writeRoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, role)
+}
+
+// Update 修改组织自定义角色
+// @Summary      修改自定义角色
+// @Description  修改自定义角色的描述和权限，权限整体替换并对拥有该角色的用户立即生效
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int                true  "组织ID"
+// @Param        role_id  path      int                true  "角色ID"
+// @Param        request  body      UpdateRoleRequest  true  "角色信息"
+// @Success      200  {object}  model.Role
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/roles/{role_id} [put]
+func (r *Role) Update(c *gin.Context) {
+	var orgID, roleID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+	if _, err := fmt.Sscanf(c.Param("role_id"), "%d", &roleID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "角色ID无效"})
+		return
+	}
+
+	var req UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	role, err := r.roleService.Update(currentUser, orgID, roleID, service.RoleSettings{
+		Description: req.Description,
+		Permissions: req.Permissions,
+	})
+	if err != nil {
+		writeRoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, role)
+}
+
+// Delete 删除组织自定义角色
+// @Summary      删除自定义角色
+// @Description  删除自定义角色，仍有用户、成员或未接受的邀请使用该角色时返回409
+// @Tags         roles
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int  true  "组织ID"
+// @Param        role_id  path      int  true  "角色ID"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Failure      409  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/roles/{role_id} [delete]
+func (r *Role) Delete(c *gin.Context) {
+	var orgID, roleID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+	if _, err := fmt.Sscanf(c.Param("role_id"), "%d", &roleID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "角色ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := r.roleService.Delete(currentUser, orgID, roleID); err != nil {
+		writeRoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "角色已删除"})
+}
+
+// writeRoleError 将角色管理错误转换为响应
+func writeRoleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrRoleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrRoleInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
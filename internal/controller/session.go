@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"backend/pkg/jwt"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RevokeSessionsResponse 批量撤销会话响应
+type RevokeSessionsResponse struct {
+	Message string `json:"message" example:"已撤销其他会话"`
+	Revoked int    `json:"revoked" example:"2"` // 撤销的会话数量
+}
+
+// Session 登录会话控制器
+type Session struct {
+	sessionService *service.SessionService
+}
+
+// NewSession creates a new Session controller
+func NewSession() *Session {
+	return &Session{
+		sessionService: &service.SessionService{},
+	}
+}
+
+// List 获取当前用户的登录会话
+// @Summary      获取登录会话列表
+// @Description  获取当前用户未过期的登录会话（设备、User-Agent、IP、登录和最近访问时间），current 标记当前请求所在的会话
+// @Tags         sessions
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {array}   service.SessionInfo
+// @Failure      401  {object}  response.ErrorResponse
+// @Router       /auth/sessions [get]
+func (s *Session) List(c *gin.Context) {
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	sessions, err := s.sessionService.List(currentUser.ID, currentSessionID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// Revoke 撤销当前用户的单个会话
+// @Summary      撤销登录会话
+// @Description  撤销当前用户的指定会话，该会话的访问令牌和刷新令牌立即失效
+// @Tags         sessions
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "会话ID"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /auth/sessions/{id} [delete]
+func (s *Session) Revoke(c *gin.Context) {
+	var sessionID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &sessionID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "会话ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := s.sessionService.Revoke(currentUser.ID, sessionID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "会话已撤销"})
+}
+
+// RevokeOthers 撤销当前用户的其他会话
+// @Summary      撤销其他登录会话
+// @Description  撤销当前用户除当前会话以外的全部会话
+// @Tags         sessions
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {object}  RevokeSessionsResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Router       /auth/sessions [delete]
+func (s *Session) RevokeOthers(c *gin.Context) {
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	revoked, err := s.sessionService.RevokeOthers(currentUser.ID, currentSessionID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, RevokeSessionsResponse{Message: "已撤销其他会话", Revoked: revoked})
+}
+
+// ListForUser 获取用户的登录会话（管理员功能）
+// @Summary      获取用户的登录会话
+// @Description  超级管理员可以查看所有用户的会话，组织管理员只能查看本组织及下级组织用户的会话
+// @Tags         sessions
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "用户ID"
+// @Success      200  {array}   service.SessionInfo
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /users/{id}/sessions [get]
+func (s *Session) ListForUser(c *gin.Context) {
+	var userID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	sessions, err := s.sessionService.ListForUser(currentUser, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeForUser 撤销用户的单个会话（管理员功能）
+// @Summary      撤销用户的登录会话
+// @Description  超级管理员可以撤销所有用户的会话，组织管理员只能撤销本组织及下级组织用户的会话
+// @Tags         sessions
+// @Produce      json
+// @Security     Bearer
+// @Param        id          path      int  true  "用户ID"
+// @Param        session_id  path      int  true  "会话ID"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /users/{id}/sessions/{session_id} [delete]
+func (s *Session) RevokeForUser(c *gin.Context) {
+	var userID, sessionID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+	if _, err := fmt.Sscanf(c.Param("session_id"), "%d", &sessionID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "会话ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := s.sessionService.RevokeForUser(currentUser, userID, sessionID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrSessionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "会话已撤销"})
+}
+
+// RevokeAllForUser 撤销用户的全部会话（管理员功能）
+// @Summary      撤销用户的全部登录会话
+// @Description  撤销用户的全部会话，超级管理员可以操作所有用户，组织管理员只能操作本组织及下级组织的用户
+// @Tags         sessions
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "用户ID"
+// @Success      200  {object}  RevokeSessionsResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /users/{id}/sessions [delete]
+func (s *Session) RevokeAllForUser(c *gin.Context) {
+	var userID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	revoked, err := s.sessionService.RevokeAllForUser(currentUser, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, RevokeSessionsResponse{Message: "已撤销用户的全部会话", Revoked: revoked})
+}
+
+// currentSessionID 获取当前访问令牌所在的会话
+func currentSessionID(c *gin.Context) string {
+	if value, exists := c.Get("claims"); exists {
+		return value.(*jwt.CustomClaims).SessionID
+	}
+	return ""
+}
@@ -0,0 +1,388 @@
+package controller
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TeamRequest 创建或修改团队请求，修改时整体替换
+type TeamRequest struct {
+	Name        string   `json:"name" binding:"required,max=64" example:"support"`
+	Description string   `json:"description" binding:"max=256" example:"客服团队"`
+	ParentID    *uint    `json:"parent_id" example:"1"`    // 上级团队ID，为空或0表示顶级团队
+	Roles       []string `json:"roles" example:"helpdesk"` // 分配给团队的角色，团队及下级团队的成员获得这些角色的权限
+}
+
+// SetTeamMemberRequest 加入团队请求
+type SetTeamMemberRequest struct {
+	Lead bool `json:"lead"` // 是否为团队负责人
+}
+
+// Team 团队控制器
+type Team struct {
+	teamService *service.TeamService
+}
+
+// NewTeam creates a new Team controller
+func NewTeam() *Team {
+	return &Team{
+		teamService: &service.TeamService{},
+	}
+}
+
+// List 获取组织的团队
+// @Summary      获取团队列表
+// @Description  获取组织的全部团队，通过 parent_id 组成树
+// @Tags         teams
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "组织ID"
+// @Success      200  {array}   model.Team
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/teams [get]
+func (t *Team) List(c *gin.Context) {
+	var orgID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	teams, err := t.teamService.List(currentUser, orgID)
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, teams)
+}
+
+// Get 获取团队
+// @Summary      获取团队
+// @Tags         teams
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int  true  "组织ID"
+// @Param        team_id  path      int  true  "团队ID"
+// @Success      200  {object}  model.Team
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/teams/{team_id} [get]
+func (t *Team) Get(c *gin.Context) {
+	orgID, teamID, ok := parseTeamPath(c)
+	if !ok {
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	team, err := t.teamService.Get(currentUser, orgID, teamID)
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, team)
+}
+
+// Create 创建团队
+// @Summary      创建团队
+// @Description  在组织内创建团队，可以指定上级团队和分配给团队的角色。分配的角色和上级团队获得的权限不能超出操作者的权限
+// @Tags         teams
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int          true  "组织ID"
+// @Param        request  body      TeamRequest  true  "团队信息"
+// @Success      201  {object}  model.Team
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/teams [post]
+func (t *Team) Create(c *gin.Context) {
+	var orgID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return
+	}
+
+	var req TeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	team, err := t.teamService.Create(currentUser, orgID, req.settings())
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, team)
+}
+
+// Update 修改团队
+// @Summary      修改团队
+// @Description  修改团队的名称、描述、上级团队和角色，整体替换。不能将团队移动到自己或下级团队之下；修改对团队及下级团队的成员立即生效
+// @Tags         teams
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int          true  "组织ID"
+// @Param        team_id  path      int          true  "团队ID"
+// @Param        request  body      TeamRequest  true  "团队信息"
+// @Success      200  {object}  model.Team
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/teams/{team_id} [put]
+func (t *Team) Update(c *gin.Context) {
+	orgID, teamID, ok := parseTeamPath(c)
+	if !ok {
+		return
+	}
+
+	var req TeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	team, err := t.teamService.Update(currentUser, orgID, teamID, req.settings())
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, team)
+}
+
+// Delete 删除团队
+// @Summary      删除团队
+// @Description  删除团队及其成员关系，仍有下级团队时返回409
+// @Tags         teams
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int  true  "组织ID"
+// @Param        team_id  path      int  true  "团队ID"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Failure      409  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/teams/{team_id} [delete]
+func (t *Team) Delete(c *gin.Context) {
+	orgID, teamID, ok := parseTeamPath(c)
+	if !ok {
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := t.teamService.Delete(currentUser, orgID, teamID); err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "团队已删除"})
+}
+
+// Members 获取团队成员
+// @Summary      获取团队成员
+// @Description  获取团队的成员及其是否为负责人，需要团队查看权限
+// @Tags         teams
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int  true  "组织ID"
+// @Param        team_id  path      int  true  "团队ID"
+// @Success      200  {array}   service.TeamMemberInfo
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/teams/{team_id}/members [get]
+func (t *Team) Members(c *gin.Context) {
+	orgID, teamID, ok := parseTeamPath(c)
+	if !ok {
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	members, err := t.teamService.Members(currentUser, orgID, teamID)
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+// SetMember 将用户加入团队
+// @Summary      加入团队或修改负责人
+// @Description  将组织的用户或加入组织的成员加入团队，已在团队中时修改其是否为负责人。需要团队管理权限，且需要拥有团队获得的全部权限
+// @Tags         teams
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int                   true   "组织ID"
+// @Param        team_id  path      int                   true   "团队ID"
+// @Param        user_id  path      int                   true   "用户ID"
+// @Param        request  body      SetTeamMemberRequest  false  "是否为负责人"
+// @Success      200  {object}  model.TeamMember
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/teams/{team_id}/members/{user_id} [put]
+func (t *Team) SetMember(c *gin.Context) {
+	orgID, teamID, ok := parseTeamPath(c)
+	if !ok {
+		return
+	}
+	var userID uint
+	if _, err := fmt.Sscanf(c.Param("user_id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+
+	var req SetTeamMemberRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	member, err := t.teamService.SetMember(currentUser, orgID, teamID, userID, req.Lead)
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember 将用户移出团队
+// @Summary      移出团队
+// @Description  将用户移出团队，用户随即失去通过该团队获得的权限
+// @Tags         teams
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int  true  "组织ID"
+// @Param        team_id  path      int  true  "团队ID"
+// @Param        user_id  path      int  true  "用户ID"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /organizations/{id}/teams/{team_id}/members/{user_id} [delete]
+func (t *Team) RemoveMember(c *gin.Context) {
+	orgID, teamID, ok := parseTeamPath(c)
+	if !ok {
+		return
+	}
+	var userID uint
+	if _, err := fmt.Sscanf(c.Param("user_id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := t.teamService.RemoveMember(currentUser, orgID, teamID, userID); err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "已移出团队"})
+}
+
+// settings 转换为团队的可修改项
+func (r TeamRequest) settings() service.TeamSettings {
+	return service.TeamSettings{
+		Name:        r.Name,
+		Description: r.Description,
+		ParentID:    r.ParentID,
+		Roles:       r.Roles,
+	}
+}
+
+// parseTeamPath 解析路径中的组织ID和团队ID，无效时写入响应并返回 false
+func parseTeamPath(c *gin.Context) (uint, uint, bool) {
+	var orgID, teamID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "组织ID无效"})
+		return 0, 0, false
+	}
+	if _, err := fmt.Sscanf(c.Param("team_id"), "%d", &teamID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "团队ID无效"})
+		return 0, 0, false
+	}
+	return orgID, teamID, true
+}
+
+// writeTeamError 将团队管理错误转换为响应
+func writeTeamError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTeamNotFound), errors.Is(err, service.ErrTeamMemberNotFound), errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTeamHasChildren):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
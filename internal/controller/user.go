@@ -0,0 +1,484 @@
+package controller
+
+import (
+	"backend/internal/model"
+	"backend/internal/service"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateUserRequest 创建用户请求
+type CreateUserRequest struct {
+	OrganizationID uint   `json:"organization_id" example:"2"` // 所属组织，只有超级管理员需要指定，其他管理员固定为自己所在的组织
+	Username       string `json:"username" binding:"required,min=3,max=32" example:"john_doe"`
+	Password       string `json:"password" binding:"required,max=128"`
+	Email          string `json:"email" binding:"omitempty,email,max=128" example:"john@example.com"`
+	Role           string `json:"role" binding:"omitempty,max=32" example:"org_member"` // 内置角色或组织的自定义角色，默认为组织成员
+}
+
+// ListUsersQuery 用户列表查询参数
+type ListUsersQuery struct {
+	OrganizationID uint   `form:"organization_id"`                                                              // 组织ID
+	Role           string `form:"role" binding:"max=32"`                                                        // 角色
+	Status         string `form:"status" binding:"omitempty,oneof=active pending suspended locked deactivated"` // 账号状态
+	Search         string `form:"search" binding:"max=128"`                                                     // 按用户名或邮箱模糊搜索
+	Page           int    `form:"page" binding:"omitempty,min=1"`                                               // 页码，从1开始
+	PageSize       int    `form:"page_size" binding:"omitempty,min=1,max=100"`                                  // 每页数量，默认20
+}
+
+// UpdateUserRequest 修改用户资料请求，未提供的字段保持不变
+type UpdateUserRequest struct {
+	Username *string `json:"username" binding:"omitempty,min=3,max=32" example:"john_doe"`
+	Email    *string `json:"email" binding:"omitempty,email,max=128" example:"john@example.com"` // 修改后需要重新验证
+}
+
+// ChangeRoleRequest 修改用户角色请求
+type ChangeRoleRequest struct {
+	Role string `json:"role" binding:"required,max=32" example:"org_admin"` // 内置角色或组织的自定义角色
+}
+
+// DisableUserRequest 停用用户请求
+type DisableUserRequest struct {
+	Reason string `json:"reason" binding:"max=256"` // 停用原因，记录在审计日志中
+}
+
+// ChangeStatusRequest 变更账号状态请求
+type ChangeStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active suspended locked deactivated" example:"locked"` // 目标状态
+	Reason string `json:"reason" binding:"max=256" example:"账号疑似被盗用"`                                           // 变更原因，记录在状态变更记录中
+}
+
+// User 用户管理控制器
+type User struct {
+	userService *service.UserService
+}
+
+// NewUser creates a new User controller
+func NewUser() *User {
+	return &User{
+		userService: &service.UserService{},
+	}
+}
+
+// List 获取用户列表
+// @Summary      获取用户列表
+// @Description  分页获取用户，可以按组织、角色、状态筛选并按用户名或邮箱搜索。超级管理员可以查看所有用户，其他管理员默认查看自己所在组织的用户，也可以指定下级组织
+// @Tags         users
+// @Produce      json
+// @Security     Bearer
+// @Param        organization_id  query     int     false  "组织ID"
+// @Param        role             query     string  false  "角色"
+// @Param        status           query     string  false  "账号状态"  Enums(active, pending, suspended)
+// @Param        search           query     string  false  "按用户名或邮箱模糊搜索"
+// @Param        page             query     int     false  "页码，从1开始"
+// @Param        page_size        query     int     false  "每页数量，默认20，最大100"
+// @Success      200  {object}  service.UserPage
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /users [get]
+func (u *User) List(c *gin.Context) {
+	var query ListUsersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	page, err := u.userService.List(currentUser, service.UserQuery{
+		OrganizationID: query.OrganizationID,
+		Role:           query.Role,
+		Status:         query.Status,
+		Search:         query.Search,
+		Page:           query.Page,
+		PageSize:       query.PageSize,
+	})
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// Get 获取用户
+// @Summary      获取用户
+// @Description  超级管理员可以查看所有用户，其他管理员只能查看本组织及下级组织的用户
+// @Tags         users
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "用户ID"
+// @Success      200  {object}  model.User
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /users/{id} [get]
+func (u *User) Get(c *gin.Context) {
+	var userID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	found, err := u.userService.Get(currentUser, userID)
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, found)
+}
+
+// Create 创建用户
+// @Summary      创建用户
+// @Description  在组织内创建用户，密码按组织的密码策略校验。非超级管理员只能在自己所在的组织及其下级组织中创建用户，且不能分配超出自己权限的角色
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body CreateUserRequest true "用户信息"
+// @Success      201  {object}  model.User
+// @Failure      400  {object}  PasswordPolicyErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Router       /users [post]
+func (u *User) Create(c *gin.Context) {
+	var req CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	created, err := u.userService.Create(currentUser, service.CreateUserInput{
+		OrganizationID: req.OrganizationID,
+		Username:       req.Username,
+		Password:       req.Password,
+		Email:          req.Email,
+		Role:           req.Role,
+	})
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// Update 修改用户资料
+// @Summary      修改用户资料
+// @Description  修改用户的用户名或邮箱，组织内用户名和邮箱不能重复。修改邮箱后需要重新验证
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int                true  "用户ID"
+// @Param        request  body      UpdateUserRequest  true  "用户信息"
+// @Success      200  {object}  model.User
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /users/{id} [put]
+func (u *User) Update(c *gin.Context) {
+	var userID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+
+	var req UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	updated, err := u.userService.Update(currentUser, userID, service.UpdateUserInput{
+		Username: req.Username,
+		Email:    req.Email,
+	})
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// ChangeRole 修改用户角色
+// @Summary      修改用户角色
+// @Description  修改用户的角色，在下一个请求时即按新角色授权。不能修改自己或超级管理员的角色，不能分配超出自己权限的角色
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int                true  "用户ID"
+// @Param        request  body      ChangeRoleRequest  true  "角色"
+// @Success      200  {object}  model.User
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /users/{id}/role [put]
+func (u *User) ChangeRole(c *gin.Context) {
+	var userID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+
+	var req ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	updated, err := u.userService.ChangeRole(currentUser, userID, req.Role)
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// Disable 停用用户
+// @Summary      停用用户
+// @Description  停用后用户无法登录，已签发的令牌、会话和 API Key 立即失效
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int                 true   "用户ID"
+// @Param        request  body      DisableUserRequest  false  "停用原因"
+// @Success      200  {object}  model.User
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Failure      409  {object}  response.ErrorResponse
+// @Router       /users/{id}/disable [post]
+func (u *User) Disable(c *gin.Context) {
+	var userID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+
+	var req DisableUserRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	disabled, err := u.userService.Disable(currentUser, userID, req.Reason)
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, disabled)
+}
+
+// Enable 启用用户
+// @Summary      启用用户
+// @Description  重新启用被停用、锁定或注销的用户
+// @Tags         users
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "用户ID"
+// @Success      200  {object}  model.User
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Failure      409  {object}  response.ErrorResponse
+// @Router       /users/{id}/enable [post]
+func (u *User) Enable(c *gin.Context) {
+	var userID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	enabled, err := u.userService.Enable(currentUser, userID)
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, enabled)
+}
+
+// ChangeStatus 变更用户的账号状态
+// @Summary      变更账号状态
+// @Description  按允许的状态变更修改账号状态：active 可以变为 suspended、locked 或 deactivated，suspended 和 locked 可以恢复为 active 或变为 deactivated，locked 也可以变为 suspended，deactivated 只能重新启用为 active；待审核的注册通过注册审核处理。变为 active 以外的状态后用户无法登录，已签发的令牌、会话和 API Key 立即失效。原因和操作者记入状态变更记录
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path      int                  true  "用户ID"
+// @Param        request  body      ChangeStatusRequest  true  "目标状态和原因"
+// @Success      200  {object}  model.User
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Failure      409  {object}  response.ErrorResponse
+// @Router       /users/{id}/status [put]
+func (u *User) ChangeStatus(c *gin.Context) {
+	var userID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+
+	var req ChangeStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	changed, err := u.userService.ChangeStatus(currentUser, userID, req.Status, req.Reason)
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, changed)
+}
+
+// StatusHistory 获取用户的账号状态变更记录
+// @Summary      获取账号状态变更记录
+// @Description  获取用户账号状态的变更记录，包括变更前后的状态、原因和操作者，最近的在前
+// @Tags         users
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "用户ID"
+// @Success      200  {array}   model.UserStatusChange
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /users/{id}/status-history [get]
+func (u *User) StatusHistory(c *gin.Context) {
+	var userID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	changes, err := u.userService.StatusHistory(currentUser, userID)
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, changes)
+}
+
+// Delete 删除用户
+// @Summary      删除用户
+// @Description  删除用户，已签发的令牌和会话立即失效，同时移除其组织成员关系、团队成员关系、外部身份关联和 API Key。不能删除自己
+// @Tags         users
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      int  true  "用户ID"
+// @Success      200  {object}  response.SuccessResponse
+// @Failure      400  {object}  response.ErrorResponse
+// @Failure      401  {object}  response.ErrorResponse
+// @Failure      403  {object}  response.ErrorResponse
+// @Failure      404  {object}  response.ErrorResponse
+// @Router       /users/{id} [delete]
+func (u *User) Delete(c *gin.Context) {
+	var userID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID无效"})
+		return
+	}
+
+	user, exists := c.Get("currentUser")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权"})
+		return
+	}
+	currentUser := user.(*model.User)
+
+	if err := u.userService.Delete(currentUser, userID); err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "用户已删除"})
+}
+
+// writeUserError 将用户管理错误转换为响应，越过组织边界返回 403，用户不存在返回 404
+func writeUserError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrStatusTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		writePasswordError(c, err)
+	}
+}
@@ -0,0 +1,23 @@
+package controller
+
+import (
+	"backend/pkg/jwt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WellKnown 公开元数据控制器
+type WellKnown struct{}
+
+// NewWellKnown creates a new WellKnown controller
+func NewWellKnown() *WellKnown {
+	return &WellKnown{}
+}
+
+// JWKS 获取令牌验证公钥集合
+// 路由挂载在 /.well-known/jwks.json，不在 /api/v1 之下，供其他服务验证 windz 签发的令牌
+func (w *WellKnown) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, jwt.PublicJWKS())
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"backend/internal/model"
+	"backend/internal/service"
 	"backend/pkg/database"
 	"backend/pkg/jwt"
 	"net/http"
@@ -11,7 +12,15 @@ import (
 )
 
 // RequireAuth 验证用户是否已登录
+// 同时接受访问令牌和 API Key，API Key 与 OAuth2 客户端令牌的访问范围保存在上下文的 authScopes 中
 func RequireAuth() gin.HandlerFunc {
+	revocationService := &service.RevocationService{}
+	apiKeyService := &service.APIKeyService{}
+	oauthClientService := &service.OAuthClientService{}
+	sessionService := &service.SessionService{}
+	impersonationService := &service.ImpersonationService{}
+	membershipService := &service.MembershipService{}
+
 	return func(c *gin.Context) {
 		// 从请求头获取 token
 		token := c.GetHeader("Authorization")
@@ -24,6 +33,22 @@ func RequireAuth() gin.HandlerFunc {
 		// 移除 Bearer 前缀
 		token = strings.TrimPrefix(token, "Bearer ")
 
+		// API Key 认证
+		if service.IsAPIKey(token) {
+			apiKey, user, err := apiKeyService.Authenticate(token)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				c.Abort()
+				return
+			}
+
+			c.Set("currentUser", user)
+			c.Set("apiKey", apiKey)
+			c.Set("authScopes", []string(apiKey.Scopes))
+			c.Next()
+			return
+		}
+
 		// 解析 token
 		claims, err := jwt.ParseToken(token)
 		if err != nil {
@@ -32,22 +57,90 @@ func RequireAuth() gin.HandlerFunc {
 			return
 		}
 
+		// 检查令牌是否已被撤销
+		if revocationService.IsRevoked(claims.ID) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "令牌已失效"})
+			c.Abort()
+			return
+		}
+
 		// 从数据库获取用户信息
 		var user model.User
-		if err := database.DB.First(&user, claims.UserID).Error; err != nil {
+		if err := database.DB.Preload("Organization").First(&user, claims.UserID).Error; err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "未授权访问"})
 			c.Abort()
 			return
 		}
 
-		// 将用户信息存储到上下文中
+		// 账号被停用、锁定或注销后，未过期的令牌也立即失效
+		if err := service.CheckUserStatus(&user); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		// 检查用户的令牌是否已被整体撤销（强制下线、修改密码等）
+		if revocationService.IsUserTokenRevoked(&user, claims) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "令牌已失效"})
+			c.Abort()
+			return
+		}
+
+		// 切换组织后签发的令牌：使用用户在加入的组织中的角色，成员关系被移除后立即失效
+		if err := membershipService.Apply(&user, claims.OrganizationID); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "令牌已失效"})
+			c.Abort()
+			return
+		}
+
+		// 用户登录签发的令牌：所在会话被撤销后立即失效
+		if claims.SessionID != "" {
+			if err := sessionService.Check(claims.SessionID, c.ClientIP()); err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "令牌已失效"})
+				c.Abort()
+				return
+			}
+		}
+
+		// 客户端凭证模式签发的令牌：客户端被撤销后立即失效，并受令牌中的访问范围限制
+		if claims.ClientID != "" {
+			if !oauthClientService.IsActive(claims.ClientID) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "令牌已失效"})
+				c.Abort()
+				return
+			}
+			c.Set("oauthClientID", claims.ClientID)
+			c.Set("authScopes", strings.Fields(claims.Scope))
+		}
+
+		// 将用户信息和令牌声明存储到上下文中
 		c.Set("currentUser", &user)
+		c.Set("claims", claims)
+
+		// 模拟登录令牌：操作者必须仍为超级管理员，响应中标明模拟者并记录每个请求
+		if claims.Actor != nil {
+			if err := impersonationService.Verify(claims); err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				c.Abort()
+				return
+			}
+			c.Set("impersonator", claims.Actor)
+			c.Header("X-Impersonated-By", claims.Actor.Username)
+			c.Next()
+			impersonationService.Audit(claims, c.Request.Method, c.Request.URL.Path, c.Writer.Status(), c.ClientIP())
+			return
+		}
+
 		c.Next()
 	}
 }
 
-// RequireSuperAdmin 验证用户是否为超级管理员
-func RequireSuperAdmin() gin.HandlerFunc {
+// EnforceAccountPolicy 验证用户是否满足所在组织的安全策略
+// 未满足时只能访问双因素认证注册、修改密码等不受此中间件保护的路由
+func EnforceAccountPolicy() gin.HandlerFunc {
+	mfaService := &service.MFAService{}
+	passwordService := &service.PasswordPolicyService{}
+
 	return func(c *gin.Context) {
 		// 获取当前用户
 		user, exists := c.Get("currentUser")
@@ -57,10 +150,32 @@ func RequireSuperAdmin() gin.HandlerFunc {
 			return
 		}
 
-		// 检查用户角色
+		// 组织要求双因素认证而用户尚未启用
 		currentUser := user.(*model.User)
-		if currentUser.Role != model.RoleSuperAdmin {
-			c.JSON(http.StatusForbidden, gin.H{"error": "需要超级管理员权限"})
+		if mfaService.EnrollmentRequired(currentUser) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "组织要求启用双因素认证，请先完成注册"})
+			c.Abort()
+			return
+		}
+
+		// 密码超过组织规定的有效期
+		if passwordService.Expired(currentUser) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "密码已过期，请先修改密码"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireScope 验证受限凭证（如 API Key）是否具有指定的访问范围
+// 用户登录获得的访问令牌不受访问范围限制
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, restricted := c.Get("authScopes")
+		if restricted && !model.StringList(scopes.([]string)).Contains(scope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "凭证缺少访问范围: " + scope})
 			c.Abort()
 			return
 		}
@@ -69,8 +184,39 @@ func RequireSuperAdmin() gin.HandlerFunc {
 	}
 }
 
-// RequireOrgAdmin 验证用户是否为组织管理员或超级管理员
-func RequireOrgAdmin() gin.HandlerFunc {
+// RequireInteractive 仅允许用户登录获得的访问令牌访问，拒绝 API Key 等受限凭证
+// 用于修改密码、管理凭证等只应由用户本人操作的路由
+func RequireInteractive() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, restricted := c.Get("authScopes"); restricted {
+			c.JSON(http.StatusForbidden, gin.H{"error": "此操作需要用户登录令牌"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// DenyImpersonation 拒绝模拟登录令牌访问
+// 用于修改密码、管理凭证和安全设置等模拟者不应代替用户执行的操作
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, impersonating := c.Get("impersonator"); impersonating {
+			c.JSON(http.StatusForbidden, gin.H{"error": service.ErrImpersonationDenied.Error()})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequirePermission 验证用户的角色是否拥有指定权限
+// 内置角色的权限在代码中定义，自定义角色的权限在每个请求时从数据库读取，修改后立即生效
+func RequirePermission(permission string) gin.HandlerFunc {
+	roleService := &service.RoleService{}
+
 	return func(c *gin.Context) {
 		// 获取当前用户
 		user, exists := c.Get("currentUser")
@@ -80,10 +226,10 @@ func RequireOrgAdmin() gin.HandlerFunc {
 			return
 		}
 
-		// 检查用户角色
+		// 检查角色权限
 		currentUser := user.(*model.User)
-		if currentUser.Role != model.RoleSuperAdmin && currentUser.Role != model.RoleOrgAdmin {
-			c.JSON(http.StatusForbidden, gin.H{"error": "需要组织管理员权限"})
+		if !roleService.HasPermission(currentUser, permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "缺少权限: " + permission})
 			c.Abort()
 			return
 		}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// APIKey 个人访问令牌
+// 完整的密钥只在创建时返回一次，数据库中仅保存其哈希和用于识别的前缀
+type APIKey struct {
+	BaseModel
+	UserID     uint       `gorm:"index;not null" json:"user_id"`                     // 所属用户ID
+	Name       string     `gorm:"size:64;not null" json:"name" example:"ci-deploy"`  // 名称
+	Prefix     string     `gorm:"size:16;uniqueIndex;not null" json:"prefix"`        // 密钥前缀，用于识别密钥
+	KeyHash    string     `gorm:"size:64;not null" json:"-"`                         // 密钥哈希
+	Scopes     StringList `gorm:"size:512" json:"scopes" swaggertype:"array,string"` // 访问范围
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`                              // 过期时间，为空表示永不过期
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`                            // 最近使用时间
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`                              // 撤销时间
+}
+
+// TableName 指定表名
+func (APIKey) TableName() string {
+	return "api_keys"
+}
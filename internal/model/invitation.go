@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Invitation 组织邀请，通过邮件中的一次性链接加入组织
+// 数据库中只保存令牌哈希，接受或撤销后失效
+type Invitation struct {
+	BaseModel
+	OrganizationID uint       `gorm:"index;not null" json:"organization_id"`                      // 组织ID
+	Email          string     `gorm:"size:128;index;not null" json:"email" example:"new@acme.io"` // 受邀邮箱
+	Role           string     `gorm:"size:32;not null" json:"role" example:"org_member"`          // 加入后的角色
+	TokenHash      string     `gorm:"size:64;uniqueIndex;not null" json:"-"`                      // 令牌哈希
+	InvitedByID    uint       `gorm:"not null" json:"invited_by_id"`                              // 邀请人ID
+	ExpiresAt      time.Time  `gorm:"not null" json:"expires_at"`                                 // 过期时间
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`                                      // 接受时间
+	AcceptedByID   *uint      `json:"accepted_by_id,omitempty"`                                   // 接受邀请的用户ID
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`                                       // 撤销时间
+}
+
+// TableName 指定表名
+func (Invitation) TableName() string {
+	return "invitations"
+}
@@ -0,0 +1,23 @@
+package model
+
+// LDAPConfig 组织的LDAP/Active Directory认证配置
+type LDAPConfig struct {
+	BaseModel
+	OrganizationID     uint     `gorm:"uniqueIndex;not null" json:"organization_id"`                                                              // 所属组织ID
+	URL                string   `gorm:"size:256;not null" json:"url" example:"ldaps://ldap.example.com:636"`                                      // 服务器地址，支持 ldap:// 和 ldaps://
+	InsecureSkipVerify bool     `gorm:"not null" json:"insecure_skip_verify"`                                                                     // 是否跳过TLS证书校验，仅用于测试环境
+	BindDN             string   `gorm:"size:256" json:"bind_dn" example:"cn=readonly,dc=example,dc=com"`                                          // 搜索用户时使用的服务账号，为空时匿名绑定
+	BindPassword       string   `gorm:"size:256" json:"-"`                                                                                        // 服务账号密码
+	BaseDN             string   `gorm:"size:256;not null" json:"base_dn" example:"ou=people,dc=example,dc=com"`                                   // 搜索用户的基准DN
+	UserFilter         string   `gorm:"size:256;not null" json:"user_filter" example:"(&(objectClass=person)(uid={username}))"`                   // 搜索用户的过滤器，{username} 会被替换为转义后的登录用户名
+	UsernameAttribute  string   `gorm:"size:64;not null" json:"username_attribute" example:"uid"`                                                 // 映射为用户名的属性
+	EmailAttribute     string   `gorm:"size:64;not null" json:"email_attribute" example:"mail"`                                                   // 映射为邮箱的属性
+	GroupAttribute     string   `gorm:"size:64;not null" json:"group_attribute" example:"memberOf"`                                               // 用户所属组的属性
+	AdminGroups        LineList `gorm:"size:1024" json:"admin_groups" swaggertype:"array,string" example:"cn=admins,ou=groups,dc=example,dc=com"` // 属于其中任一组的用户映射为组织管理员
+	Enabled            bool     `gorm:"not null" json:"enabled"`                                                                                  // 是否启用
+}
+
+// TableName 指定表名
+func (LDAPConfig) TableName() string {
+	return "ldap_configs"
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+const (
+	LoginThrottleUser = "user" // 按组织内用户名统计
+	LoginThrottleIP   = "ip"   // 按客户端IP统计
+)
+
+// LoginThrottle 登录失败计数
+// 按用户统计时不区分用户是否存在，避免通过锁定状态枚举账号
+type LoginThrottle struct {
+	BaseModel
+	Kind          string     `gorm:"size:8;uniqueIndex:idx_login_throttle_target;not null" json:"kind"`     // 统计维度（user/ip）
+	Target        string     `gorm:"size:160;uniqueIndex:idx_login_throttle_target;not null" json:"target"` // 组织ID:用户名 或 IP
+	Failures      int        `gorm:"not null" json:"failures"`                                              // 窗口内连续失败次数
+	LastFailureAt time.Time  `gorm:"index;not null" json:"last_failure_at"`                                 // 最近一次失败时间
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`                                             // 渐进延迟：此时间之前拒绝登录
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`                                                // 锁定截止时间
+}
+
+// TableName 指定表名
+func (LoginThrottle) TableName() string {
+	return "login_throttles"
+}
@@ -0,0 +1,18 @@
+package model
+
+// Membership 用户加入的所在组织以外的组织及其在该组织中的角色
+// 用户本身是跨组织的全局身份：所在组织和角色仍保存在 User 中，按组织代码登录所在组织后可以切换到加入的组织
+type Membership struct {
+	BaseModel
+	UserID         uint         `gorm:"uniqueIndex:idx_memberships_user_org;not null" json:"user_id"`               // 用户ID
+	OrganizationID uint         `gorm:"uniqueIndex:idx_memberships_user_org;index;not null" json:"organization_id"` // 加入的组织ID
+	Role           string       `gorm:"size:32;not null" json:"role" example:"org_member"`                          // 在该组织中的角色
+	InvitedByID    *uint        `json:"invited_by_id,omitempty"`                                                    // 邀请人ID
+	User           User         `gorm:"foreignKey:UserID" json:"-"`
+	Organization   Organization `gorm:"foreignKey:OrganizationID" json:"-"`
+}
+
+// TableName 指定表名
+func (Membership) TableName() string {
+	return "memberships"
+}
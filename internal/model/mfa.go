@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// UserMFA 用户双因素认证（TOTP）配置
+// EnabledAt 为空表示已生成密钥但尚未通过验证码确认
+type UserMFA struct {
+	BaseModel
+	UserID       uint       `gorm:"uniqueIndex;not null" json:"user_id"` // 用户ID
+	Secret       string     `gorm:"size:64;not null" json:"-"`           // TOTP密钥（base32）
+	EnabledAt    *time.Time `json:"enabled_at,omitempty"`                // 启用时间
+	LastUsedStep int64      `json:"-"`                                   // 最近一次使用的时间步，用于防止验证码重放
+}
+
+// TableName 指定表名
+func (UserMFA) TableName() string {
+	return "user_mfa"
+}
+
+// MFARecoveryCode 双因素认证恢复码
+type MFARecoveryCode struct {
+	BaseModel
+	UserID   uint       `gorm:"index;not null" json:"user_id"` // 用户ID
+	CodeHash string     `gorm:"size:128;not null" json:"-"`    // 恢复码哈希，与密码使用相同的哈希算法
+	UsedAt   *time.Time `json:"used_at,omitempty"`             // 使用时间
+}
+
+// TableName 指定表名
+func (MFARecoveryCode) TableName() string {
+	return "mfa_recovery_codes"
+}
+
+// MFAChallenge 登录时的双因素认证挑战
+// 密码验证通过后签发，凭挑战令牌和验证码换取正式令牌
+type MFAChallenge struct {
+	BaseModel
+	UserID         uint       `gorm:"index;not null" json:"user_id"`         // 用户ID
+	TokenHash      string     `gorm:"size:64;uniqueIndex;not null" json:"-"` // 挑战令牌哈希
+	ExpiresAt      time.Time  `gorm:"not null" json:"expires_at"`            // 过期时间
+	Attempts       int        `gorm:"default:0" json:"attempts"`             // 已尝试次数
+	UsedAt         *time.Time `json:"used_at,omitempty"`                     // 使用时间
+	ThrottleTarget string     `gorm:"size:160" json:"-"`                     // 登录失败计数对象，验证码错误计入同一计数
+}
+
+// TableName 指定表名
+func (MFAChallenge) TableName() string {
+	return "mfa_challenges"
+}
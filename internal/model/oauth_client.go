@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// OAuthClient OAuth2 客户端（客户端凭证模式）
+// 每个客户端对应组织内的一个服务账号用户，客户端签发的令牌以该用户身份访问接口
+// 令牌可以访问的接口同时受服务账号的角色权限和令牌的访问范围限制
+type OAuthClient struct {
+	BaseModel
+	OrganizationID          uint       `gorm:"index;not null" json:"organization_id"`                  // 所属组织ID
+	UserID                  uint       `gorm:"index;not null" json:"user_id"`                          // 服务账号用户ID
+	ServiceAccount          *User      `gorm:"foreignKey:UserID" json:"service_account,omitempty"`     // 服务账号，包括其角色
+	Name                    string     `gorm:"size:64;not null" json:"name" example:"billing-service"` // 名称
+	ClientID                string     `gorm:"size:64;uniqueIndex;not null" json:"client_id"`          // 客户端ID
+	SecretHash              string     `gorm:"size:64;not null" json:"-"`                              // 客户端密钥哈希
+	PreviousSecretHash      string     `gorm:"size:64" json:"-"`                                       // 轮换前的密钥哈希
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at,omitempty"`                   // 旧密钥失效时间
+	SecretRotatedAt         *time.Time `json:"secret_rotated_at,omitempty"`                            // 最近一次轮换时间
+	Scopes                  StringList `gorm:"size:512" json:"scopes" swaggertype:"array,string"`      // 允许的访问范围
+	RevokedAt               *time.Time `json:"revoked_at,omitempty"`                                   // 撤销时间
+}
+
+// TableName 指定表名
+func (OAuthClient) TableName() string {
+	return "oauth_clients"
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// OIDCProvider 组织的OpenID Connect身份提供方配置
+type OIDCProvider struct {
+	BaseModel
+	OrganizationID  uint       `gorm:"uniqueIndex;not null" json:"organization_id"`                                  // 所属组织ID
+	Issuer          string     `gorm:"size:256;not null" json:"issuer" example:"https://idp.example.com"`            // 签发者地址
+	ClientID        string     `gorm:"size:256;not null" json:"client_id" example:"windz"`                           // 在身份提供方注册的客户端ID
+	ClientSecret    string     `gorm:"size:512" json:"-"`                                                            // 客户端密钥，公共客户端为空
+	Scopes          StringList `gorm:"size:256" json:"scopes" swaggertype:"array,string"`                            // 申请的scope，为空时使用 openid profile email
+	UsernameClaim   string     `gorm:"size:64;not null" json:"username_claim" example:"preferred_username"`          // 映射为用户名的声明
+	EmailClaim      string     `gorm:"size:64;not null" json:"email_claim" example:"email"`                          // 映射为邮箱的声明
+	RoleClaim       string     `gorm:"size:64" json:"role_claim" example:"groups"`                                   // 映射角色的声明，为空时不同步角色
+	AdminRoleValues StringList `gorm:"size:512" json:"admin_role_values" swaggertype:"array,string" example:"admin"` // 角色声明包含其中任一值时映射为组织管理员
+	AutoProvision   bool       `gorm:"not null" json:"auto_provision"`                                               // 首次登录时是否自动创建用户
+	Enabled         bool       `gorm:"not null" json:"enabled"`                                                      // 是否启用
+}
+
+// TableName 指定表名
+func (OIDCProvider) TableName() string {
+	return "oidc_providers"
+}
+
+// UserIdentity 用户关联的外部身份
+type UserIdentity struct {
+	BaseModel
+	UserID         uint       `gorm:"index;not null" json:"user_id"`                                         // 用户ID
+	OrganizationID uint       `gorm:"uniqueIndex:idx_identity_org_subject;not null" json:"organization_id"`  // 所属组织ID，同一外部身份在不同组织中各自关联
+	Issuer         string     `gorm:"size:256;uniqueIndex:idx_identity_org_subject;not null" json:"issuer"`  // 身份提供方签发者
+	Subject        string     `gorm:"size:256;uniqueIndex:idx_identity_org_subject;not null" json:"subject"` // 身份提供方中的用户标识（sub）
+	LastLoginAt    *time.Time `json:"last_login_at,omitempty"`                                               // 最近一次登录时间
+}
+
+// TableName 指定表名
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+// OIDCLoginState 进行中的OIDC授权请求
+// 回调时凭 state 取回 nonce 和 PKCE 校验码，只能使用一次
+type OIDCLoginState struct {
+	BaseModel
+	OrganizationID uint       `gorm:"not null" json:"organization_id"`       // 组织ID
+	StateHash      string     `gorm:"size:64;uniqueIndex;not null" json:"-"` // state 哈希
+	Nonce          string     `gorm:"size:64;not null" json:"-"`             // ID令牌中应携带的nonce
+	CodeVerifier   string     `gorm:"size:128;not null" json:"-"`            // PKCE校验码
+	ExpiresAt      time.Time  `gorm:"index;not null" json:"expires_at"`      // 过期时间
+	UsedAt         *time.Time `json:"used_at,omitempty"`                     // 使用时间
+}
+
+// TableName 指定表名
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
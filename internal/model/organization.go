@@ -1,11 +1,24 @@
 package model
 
+// 组织的自助注册方式
+const (
+	RegistrationOpen     = "open"     // 开放注册
+	RegistrationClosed   = "closed"   // 关闭注册，只能通过邀请加入
+	RegistrationDomain   = "domain"   // 仅允许指定域名的邮箱注册，验证邮箱后才能登录
+	RegistrationApproval = "approval" // 开放注册，组织管理员审核通过后才能登录
+)
+
 // Organization 组织模型
 type Organization struct {
 	BaseModel
-	Code        string `gorm:"size:32;unique;not null" json:"code" example:"company_a"`     // 组织代码
-	Description string `gorm:"size:256" json:"description" example:"A sample organization"` // 组织描述
-	Users       []User `gorm:"foreignKey:OrganizationID" json:"users,omitempty"`            // 组织成员
+	Code                     string     `gorm:"size:32;unique;not null" json:"code" example:"company_a"`               // 组织代码
+	ParentID                 *uint      `gorm:"index" json:"parent_id,omitempty"`                                      // 上级组织ID，为空表示顶级组织；上级组织的管理员可以管理下级组织
+	Description              string     `gorm:"size:256" json:"description" example:"A sample organization"`           // 组织描述
+	RequireMFA               bool       `gorm:"default:false" json:"require_mfa"`                                      // 是否要求成员启用双因素认证
+	RequireEmailVerification bool       `gorm:"default:false" json:"require_email_verification"`                       // 是否要求本地账号验证邮箱后才能登录
+	RegistrationMode         string     `gorm:"size:16;default:open;not null" json:"registration_mode" example:"open"` // 自助注册方式
+	RegistrationDomains      StringList `gorm:"size:512" json:"registration_domains" swaggertype:"array,string"`       // 注册方式为 domain 时允许的邮箱域名
+	Users                    []User     `gorm:"foreignKey:OrganizationID" json:"users,omitempty"`                      // 组织成员
 }
 
 // TableName 指定表名
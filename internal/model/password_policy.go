@@ -0,0 +1,34 @@
+package model
+
+// PasswordPolicy 组织的密码策略，为空的字段沿用全局配置 security.password
+type PasswordPolicy struct {
+	BaseModel
+	OrganizationID   uint     `gorm:"uniqueIndex;not null" json:"organization_id"`                             // 所属组织ID
+	MinLength        *int     `json:"min_length" example:"10"`                                                 // 最小长度
+	MaxLength        *int     `json:"max_length" example:"64"`                                                 // 最大长度
+	RequireUppercase *bool    `json:"require_uppercase"`                                                       // 是否必须包含大写字母
+	RequireLowercase *bool    `json:"require_lowercase"`                                                       // 是否必须包含小写字母
+	RequireDigit     *bool    `json:"require_digit"`                                                           // 是否必须包含数字
+	RequireSymbol    *bool    `json:"require_symbol"`                                                          // 是否必须包含特殊字符
+	DisallowUsername *bool    `json:"disallow_username"`                                                       // 是否禁止密码包含用户名
+	BannedWords      LineList `gorm:"size:2048" json:"banned_words" swaggertype:"array,string" example:"acme"` // 组织禁用词，与全局禁用词同时生效
+	HistoryDepth     *int     `json:"history_depth" example:"5"`                                               // 不能与最近多少次使用过的密码相同
+	MaxAgeDays       *int     `json:"max_age_days" example:"90"`                                               // 密码有效天数，0 表示不过期
+}
+
+// TableName 指定表名
+func (PasswordPolicy) TableName() string {
+	return "password_policies"
+}
+
+// PasswordHistory 用户使用过的密码哈希，用于禁止重复使用近期密码
+type PasswordHistory struct {
+	BaseModel
+	UserID       uint   `gorm:"index;not null" json:"user_id"` // 用户ID
+	PasswordHash string `gorm:"size:128;not null" json:"-"`    // 密码哈希
+}
+
+// TableName 指定表名
+func (PasswordHistory) TableName() string {
+	return "password_histories"
+}
@@ -0,0 +1,111 @@
+package model
+
+// 权限，路由通过 RequirePermission 中间件按权限授权
+const (
+	PermissionOrgCreate         = "org.create"          // 创建组织
+	PermissionOrgRead           = "org.read"            // 查看所有组织
+	PermissionOrgUpdate         = "org.update"          // 修改组织
+	PermissionOrgDelete         = "org.delete"          // 删除组织
+	PermissionOrgSettingsRead   = "org.settings.read"   // 查看组织的OIDC、LDAP和密码策略配置
+	PermissionOrgSettingsUpdate = "org.settings.update" // 修改组织安全设置、OIDC、LDAP和密码策略配置
+	PermissionOrgChildrenRead   = "org.children.read"   // 查看下级组织
+	PermissionOrgChildrenManage = "org.children.manage" // 创建和移动下级组织
+	PermissionAdminCreate       = "admin.create"        // 创建超级管理员
+	PermissionUserRead          = "user.read"           // 查看用户
+	PermissionUserCreate        = "user.create"         // 创建用户
+	PermissionUserUpdate        = "user.update"         // 修改用户信息和角色
+	PermissionUserDisable       = "user.disable"        // 停用、启用用户和变更账号状态
+	PermissionUserDelete        = "user.delete"         // 删除用户
+	PermissionUserResetPassword = "user.reset_password" // 重置用户密码
+	PermissionUserRevokeTokens  = "user.revoke_tokens"  // 强制用户下线
+	PermissionUserUnlock        = "user.unlock"         // 解除登录锁定
+	PermissionUserImpersonate   = "user.impersonate"    // 模拟登录
+	PermissionUserInvite        = "user.invite"         // 邀请用户加入组织
+	PermissionUserApprove       = "user.approve"        // 审核自助注册
+	PermissionSessionRead       = "session.read"        // 查看其他用户的登录会话
+	PermissionSessionRevoke     = "session.revoke"      // 撤销其他用户的登录会话
+	PermissionOAuthClientManage = "oauth_client.manage" // 管理OAuth2客户端
+	PermissionAPIKeyManage      = "api_key.manage"      // 管理自己的API Key
+	PermissionRoleRead          = "role.read"           // 查看权限目录和组织角色
+	PermissionRoleManage        = "role.manage"         // 管理组织自定义角色
+	PermissionTeamRead          = "team.read"           // 查看组织团队
+	PermissionTeamManage        = "team.manage"         // 管理组织团队、团队成员和团队的角色
+)
+
+// Permission 权限目录中的权限
+type Permission struct {
+	Name        string `json:"name" example:"user.invite"`
+	Description string `json:"description" example:"邀请用户加入组织"`
+	Global      bool   `json:"global"` // 平台级权限，只授予超级管理员，不能加入自定义角色
+}
+
+// Permissions 权限目录
+var Permissions = []Permission{
+	{Name: PermissionOrgCreate, Description: "创建组织", Global: true},
+	{Name: PermissionOrgRead, Description: "查看所有组织", Global: true},
+	{Name: PermissionOrgUpdate, Description: "修改组织", Global: true},
+	{Name: PermissionOrgDelete, Description: "删除组织", Global: true},
+	{Name: PermissionAdminCreate, Description: "创建超级管理员", Global: true},
+	{Name: PermissionUserImpersonate, Description: "模拟登录", Global: true},
+	{Name: PermissionOrgSettingsRead, Description: "查看组织的OIDC、LDAP和密码策略配置"},
+	{Name: PermissionOrgSettingsUpdate, Description: "修改组织安全设置、OIDC、LDAP和密码策略配置"},
+	{Name: PermissionOrgChildrenRead, Description: "查看下级组织"},
+	{Name: PermissionOrgChildrenManage, Description: "创建和移动下级组织"},
+	{Name: PermissionUserRead, Description: "查看用户"},
+	{Name: PermissionUserCreate, Description: "创建用户"},
+	{Name: PermissionUserUpdate, Description: "修改用户信息和角色"},
+	{Name: PermissionUserDisable, Description: "停用、启用用户和变更账号状态"},
+	{Name: PermissionUserDelete, Description: "删除用户"},
+	{Name: PermissionUserResetPassword, Description: "重置用户密码"},
+	{Name: PermissionUserRevokeTokens, Description: "强制用户下线"},
+	{Name: PermissionUserUnlock, Description: "解除登录锁定"},
+	{Name: PermissionUserInvite, Description: "邀请用户加入组织"},
+	{Name: PermissionUserApprove, Description: "审核自助注册"},
+	{Name: PermissionSessionRead, Description: "查看其他用户的登录会话"},
+	{Name: PermissionSessionRevoke, Description: "撤销其他用户的登录会话"},
+	{Name: PermissionOAuthClientManage, Description: "管理OAuth2客户端"},
+	{Name: PermissionAPIKeyManage, Description: "管理自己的API Key"},
+	{Name: PermissionRoleRead, Description: "查看权限目录和组织角色"},
+	{Name: PermissionRoleManage, Description: "管理组织自定义角色"},
+	{Name: PermissionTeamRead, Description: "查看组织团队"},
+	{Name: PermissionTeamManage, Description: "管理组织团队、团队成员和团队的角色"},
+}
+
+// FindPermission 在权限目录中查找权限
+func FindPermission(name string) (Permission, bool) {
+	for _, permission := range Permissions {
+		if permission.Name == name {
+			return permission, true
+		}
+	}
+	return Permission{}, false
+}
+
+// BuiltinRolePermissions 获取内置角色的权限，不是内置角色时返回 false
+// 超级管理员拥有全部权限，组织管理员拥有全部组织级权限
+func BuiltinRolePermissions(role string) (StringList, bool) {
+	var permissions StringList
+	switch role {
+	case RoleSuperAdmin:
+		for _, permission := range Permissions {
+			permissions = append(permissions, permission.Name)
+		}
+	case RoleOrgAdmin:
+		for _, permission := range Permissions {
+			if !permission.Global {
+				permissions = append(permissions, permission.Name)
+			}
+		}
+	case RoleOrgMember:
+		permissions = StringList{PermissionAPIKeyManage}
+	default:
+		return nil, false
+	}
+	return permissions, true
+}
+
+// IsBuiltinRole 检查是否为内置角色
+func IsBuiltinRole(role string) bool {
+	_, ok := BuiltinRolePermissions(role)
+	return ok
+}
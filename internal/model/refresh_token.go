@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// RefreshToken 刷新令牌模型
+// 同一次登录产生的刷新令牌共享一个 FamilyID，每次刷新都会轮换出新的令牌
+type RefreshToken struct {
+	BaseModel
+	UserID       uint       `gorm:"index;not null" json:"user_id"`           // 用户ID
+	FamilyID     string     `gorm:"size:64;index;not null" json:"family_id"` // 令牌族ID
+	TokenHash    string     `gorm:"size:64;uniqueIndex;not null" json:"-"`   // 令牌哈希
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"`              // 过期时间
+	UsedAt       *time.Time `json:"used_at,omitempty"`                       // 轮换使用时间
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`                    // 撤销时间
+	ReplacedByID *uint      `json:"replaced_by_id,omitempty"`                // 轮换后的新令牌ID
+}
+
+// TableName 指定表名
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// RevokedToken 已撤销的访问令牌（按 jti 记录）
+type RevokedToken struct {
+	BaseModel
+	JTI       string    `gorm:"column:jti;size:64;uniqueIndex;not null" json:"jti"` // 令牌ID
+	UserID    uint      `gorm:"index;not null" json:"user_id"`                      // 用户ID
+	ExpiresAt time.Time `gorm:"index;not null" json:"expires_at"`                   // 令牌原过期时间，过期后记录可清理
+	Reason    string    `gorm:"size:64" json:"reason"`                              // 撤销原因
+}
+
+// TableName 指定表名
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
@@ -0,0 +1,16 @@
+package model
+
+// Role 组织自定义角色，用户的 Role 字段可以是内置角色或所在组织的自定义角色名称
+// 内置角色的权限见 BuiltinRolePermissions
+type Role struct {
+	BaseModel
+	OrganizationID uint       `gorm:"uniqueIndex:idx_roles_org_name;not null" json:"organization_id"`                                // 所属组织ID
+	Name           string     `gorm:"size:32;uniqueIndex:idx_roles_org_name;not null" json:"name" example:"helpdesk"`                // 角色名称，在组织内唯一
+	Description    string     `gorm:"size:256" json:"description" example:"客服，可以查看和撤销用户会话"`                                          // 描述
+	Permissions    StringList `gorm:"size:1024" json:"permissions" swaggertype:"array,string" example:"session.read,session.revoke"` // 角色拥有的权限
+}
+
+// TableName 指定表名
+func (Role) TableName() string {
+	return "roles"
+}
@@ -0,0 +1,23 @@
+package model
+
+// 访问范围，用于限制 API Key 等凭证可以访问的接口
+// 用户登录获得的访问令牌不受访问范围限制
+const (
+	ScopeOrgsRead   = "orgs:read"   // 读取组织
+	ScopeOrgsWrite  = "orgs:write"  // 管理组织
+	ScopeUsersRead  = "users:read"  // 读取用户
+	ScopeUsersWrite = "users:write" // 管理用户
+)
+
+// Scopes 全部可授予的访问范围
+var Scopes = []string{
+	ScopeOrgsRead,
+	ScopeOrgsWrite,
+	ScopeUsersRead,
+	ScopeUsersWrite,
+}
+
+// IsValidScope 检查访问范围是否存在
+func IsValidScope(scope string) bool {
+	return StringList(Scopes).Contains(scope)
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Session 登录会话，对应一个刷新令牌族
+// 访问令牌通过 sid 声明关联到会话，会话被撤销后其访问令牌和刷新令牌立即失效
+type Session struct {
+	BaseModel
+	UserID         uint       `gorm:"index;not null" json:"user_id"`                     // 用户ID
+	OrganizationID uint       `gorm:"default:0" json:"organization_id"`                  // 会话所在的组织，切换组织后为加入的组织
+	FamilyID       string     `gorm:"size:64;uniqueIndex;not null" json:"-"`             // 刷新令牌族ID
+	Device         string     `gorm:"size:64" json:"device" example:"Chrome / macOS"`    // 根据 User-Agent 识别的设备
+	UserAgent      string     `gorm:"size:256" json:"user_agent"`                        // 登录时的 User-Agent
+	IP             string     `gorm:"size:64" json:"ip" example:"203.0.113.7"`           // 登录时的IP
+	LastSeenIP     string     `gorm:"size:64" json:"last_seen_ip" example:"203.0.113.7"` // 最近一次访问的IP
+	LastSeenAt     time.Time  `gorm:"not null" json:"last_seen_at"`                      // 最近一次访问时间
+	ExpiresAt      time.Time  `gorm:"index;not null" json:"expires_at"`                  // 过期时间，随刷新令牌轮换延长
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`                              // 撤销时间
+}
+
+// TableName 指定表名
+func (Session) TableName() string {
+	return "sessions"
+}
@@ -0,0 +1,32 @@
+package model
+
+// Team 组织内的团队，可以嵌套
+// 团队的成员额外获得团队及其上级团队被分配的角色的权限
+type Team struct {
+	BaseModel
+	OrganizationID uint       `gorm:"uniqueIndex:idx_teams_org_name;not null" json:"organization_id"`                // 所属组织ID
+	ParentID       *uint      `gorm:"index" json:"parent_id,omitempty"`                                              // 上级团队ID，为空表示顶级团队
+	Name           string     `gorm:"size:64;uniqueIndex:idx_teams_org_name;not null" json:"name" example:"support"` // 团队名称，在组织内唯一
+	Description    string     `gorm:"size:256" json:"description" example:"客服团队"`                                    // 描述
+	Roles          StringList `gorm:"size:512" json:"roles" swaggertype:"array,string" example:"helpdesk"`           // 分配给团队的角色，内置角色或组织的自定义角色
+}
+
+// TableName 指定表名
+func (Team) TableName() string {
+	return "teams"
+}
+
+// TeamMember 团队成员，成员是团队所属组织的用户或加入该组织的成员
+type TeamMember struct {
+	BaseModel
+	TeamID uint `gorm:"uniqueIndex:idx_team_members_team_user;not null" json:"team_id"`       // 团队ID
+	UserID uint `gorm:"uniqueIndex:idx_team_members_team_user;index;not null" json:"user_id"` // 用户ID
+	Lead   bool `gorm:"default:false" json:"lead"`                                            // 是否为团队负责人
+	User   User `gorm:"foreignKey:UserID" json:"-"`
+	Team   Team `gorm:"foreignKey:TeamID" json:"-"`
+}
+
+// TableName 指定表名
+func (TeamMember) TableName() string {
+	return "team_members"
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// StringList 以逗号分隔存储在单个字段中的字符串列表
+type StringList []string
+
+// GormDataType 指定数据库字段类型
+func (StringList) GormDataType() string {
+	return "string"
+}
+
+// Value 实现 driver.Valuer 接口
+func (l StringList) Value() (driver.Value, error) {
+	return strings.Join(l, ","), nil
+}
+
+// Scan 实现 sql.Scanner 接口
+func (l *StringList) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+		raw = ""
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("无法将 %T 转换为 StringList", value)
+	}
+
+	*l = StringList{}
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// Contains 检查列表中是否包含指定值
+func (l StringList) Contains(value string) bool {
+	for _, item := range l {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// LineList 按行存储在单个字段中的字符串列表，用于本身可能包含逗号的值（如LDAP DN）
+type LineList []string
+
+// GormDataType 指定数据库字段类型
+func (LineList) GormDataType() string {
+	return "string"
+}
+
+// Value 实现 driver.Valuer 接口
+func (l LineList) Value() (driver.Value, error) {
+	return strings.Join(l, "\n"), nil
+}
+
+// Scan 实现 sql.Scanner 接口
+func (l *LineList) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+		raw = ""
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("无法将 %T 转换为 LineList", value)
+	}
+
+	*l = LineList{}
+	for _, item := range strings.Split(raw, "\n") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
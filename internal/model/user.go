@@ -1,6 +1,7 @@
 package model
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,6 +14,17 @@ const (
 	RoleOrgMember  = "org_member"  // 组织成员
 )
 
+const (
+	UserStatusActive      = "active"      // 正常
+	UserStatusPending     = "pending"     // 等待组织管理员审核注册
+	UserStatusSuspended   = "suspended"   // 已被管理员停用
+	UserStatusLocked      = "locked"      // 因安全原因被锁定，如账号疑似被盗用，需要管理员解锁
+	UserStatusDeactivated = "deactivated" // 已注销，如人员离职，保留账号以便审计
+)
+
+// ErrUserExists 同一组织下用户名或邮箱已被其他用户使用
+var ErrUserExists = errors.New("用户名或邮箱已存在于此组织")
+
 // BaseModel 基础模型
 type BaseModel struct {
 	ID        uint           `gorm:"primarykey" json:"id"`
@@ -24,12 +36,17 @@ type BaseModel struct {
 // User 用户模型
 type User struct {
 	BaseModel
-	Username       string       `gorm:"size:32;not null" json:"username" example:"john_doe"`   // 用户名
-	Password       string       `gorm:"size:128;not null" json:"-"`                           // 密码
-	Email          string       `gorm:"size:128" json:"email" example:"john@example.com"`     // 邮箱
-	Role           string       `gorm:"size:32;not null" json:"role" example:"org_member"`    // 角色
-	OrganizationID uint         `gorm:"default:0" json:"organization_id" example:"1"`         // 组织ID
-	Organization   Organization `gorm:"foreignKey:OrganizationID" json:"-"`                   // 所属组织
+	Username          string       `gorm:"size:32;not null" json:"username" example:"john_doe"` // 用户名
+	Password          string       `gorm:"size:128;not null" json:"-"`                          // 密码
+	Email             string       `gorm:"size:128" json:"email" example:"john@example.com"`    // 邮箱
+	EmailVerifiedAt   *time.Time   `json:"email_verified_at,omitempty"`                         // 邮箱验证时间，为空表示尚未验证
+	Role              string       `gorm:"size:32;not null" json:"role" example:"org_member"`   // 角色
+	OrganizationID    uint         `gorm:"default:0" json:"organization_id" example:"1"`        // 组织ID
+	Organization      Organization `gorm:"foreignKey:OrganizationID" json:"-"`                  // 所属组织
+	TokensRevokedAt   *time.Time   `json:"-"`                                                   // 此时间之前签发的令牌全部失效
+	PasswordChangedAt *time.Time   `json:"password_changed_at,omitempty"`                       // 最近一次设置密码的时间，为空时按创建时间计算密码有效期
+	IsServiceAccount  bool         `gorm:"default:false" json:"is_service_account"`             // 是否为OAuth2客户端的服务账号
+	Status            string       `gorm:"size:16;default:active;not null" json:"status"`       // 账号状态，变更规则见 CanTransitionUserStatus
 }
 
 // TableName 指定表名
@@ -53,12 +70,22 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 		return fmt.Errorf("organization_id is required for non-super-admin users")
 	}
 
-	// 检查同一组织下用户名和邮箱是否唯一
+	return u.CheckUnique(tx)
+}
+
+// CheckUnique 检查同一组织下用户名和邮箱是否唯一（服务账号等没有邮箱的用户只检查用户名）
+// 创建时由 BeforeCreate 调用，修改用户名、邮箱或所属组织前也需要调用
+func (u *User) CheckUnique(tx *gorm.DB) error {
 	var count int64
-	tx.Model(&User{}).Where("(username = ? OR email = ?) AND organization_id = ?",
-		u.Username, u.Email, u.OrganizationID).Count(&count)
+	query := tx.Model(&User{}).Where("organization_id = ? AND id <> ?", u.OrganizationID, u.ID)
+	if u.Email != "" {
+		query = query.Where("username = ? OR email = ?", u.Username, u.Email)
+	} else {
+		query = query.Where("username = ?", u.Username)
+	}
+	query.Count(&count)
 	if count > 0 {
-		return fmt.Errorf("username or email already exists in this organization")
+		return ErrUserExists
 	}
 
 	return nil
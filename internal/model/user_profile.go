@@ -0,0 +1,16 @@
+package model
+
+// UserProfile 用户的个人资料，由用户本人维护
+// 与登录和授权相关的字段仍保存在 User 中
+type UserProfile struct {
+	BaseModel
+	UserID      uint   `gorm:"uniqueIndex;not null" json:"user_id"`             // 用户ID
+	DisplayName string `gorm:"size:64" json:"display_name" example:"张三"`        // 显示名称
+	Locale      string `gorm:"size:16" json:"locale" example:"zh-CN"`           // 语言区域，BCP 47 格式
+	Timezone    string `gorm:"size:64" json:"timezone" example:"Asia/Shanghai"` // 时区，IANA 时区名称
+}
+
+// TableName 指定表名
+func (UserProfile) TableName() string {
+	return "user_profiles"
+}
@@ -0,0 +1,42 @@
+package model
+
+// userStatusTransitions 账号状态允许的变更
+// 待审核的注册只能通过审核变为正常（拒绝时直接删除）；已注销的账号可以重新启用
+var userStatusTransitions = map[string][]string{
+	UserStatusPending:     {UserStatusActive},
+	UserStatusActive:      {UserStatusSuspended, UserStatusLocked, UserStatusDeactivated},
+	UserStatusSuspended:   {UserStatusActive, UserStatusDeactivated},
+	UserStatusLocked:      {UserStatusActive, UserStatusSuspended, UserStatusDeactivated},
+	UserStatusDeactivated: {UserStatusActive},
+}
+
+// IsUserStatus 检查是否为有效的账号状态
+func IsUserStatus(status string) bool {
+	_, ok := userStatusTransitions[status]
+	return ok
+}
+
+// CanTransitionUserStatus 检查账号状态能否从 from 变更为 to
+func CanTransitionUserStatus(from, to string) bool {
+	for _, next := range userStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// UserStatusChange 账号状态变更记录，用于审计
+type UserStatusChange struct {
+	BaseModel
+	UserID     uint   `gorm:"index;not null" json:"user_id"`                         // 用户ID
+	FromStatus string `gorm:"size:16;not null" json:"from_status" example:"active"`  // 变更前的状态
+	ToStatus   string `gorm:"size:16;not null" json:"to_status" example:"suspended"` // 变更后的状态
+	Reason     string `gorm:"size:256" json:"reason"`                                // 变更原因
+	ActorID    uint   `gorm:"not null" json:"actor_id"`                              // 操作者ID
+}
+
+// TableName 指定表名
+func (UserStatusChange) TableName() string {
+	return "user_status_changes"
+}
@@ -0,0 +1,62 @@
+package model
+
+import "testing"
+
+func TestCanTransitionUserStatus(t *testing.T) {
+	statuses := []string{
+		UserStatusPending, UserStatusActive, UserStatusSuspended, UserStatusLocked, UserStatusDeactivated,
+	}
+	allowed := map[[2]string]bool{
+		{UserStatusPending, UserStatusActive}:        true,
+		{UserStatusActive, UserStatusSuspended}:      true,
+		{UserStatusActive, UserStatusLocked}:         true,
+		{UserStatusActive, UserStatusDeactivated}:    true,
+		{UserStatusSuspended, UserStatusActive}:      true,
+		{UserStatusSuspended, UserStatusDeactivated}: true,
+		{UserStatusLocked, UserStatusActive}:         true,
+		{UserStatusLocked, UserStatusSuspended}:      true,
+		{UserStatusLocked, UserStatusDeactivated}:    true,
+		{UserStatusDeactivated, UserStatusActive}:    true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := allowed[[2]string{from, to}]
+			if got := CanTransitionUserStatus(from, to); got != want {
+				t.Errorf("CanTransitionUserStatus(%q, %q) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestCanTransitionUserStatusUnknown(t *testing.T) {
+	tests := []struct{ from, to string }{
+		{"", UserStatusActive},
+		{"deleted", UserStatusActive},
+		{UserStatusActive, ""},
+		{UserStatusActive, "deleted"},
+		// 待审核只能通过审核进入，不能从其他状态回到待审核
+		{UserStatusActive, UserStatusPending},
+		{UserStatusSuspended, UserStatusPending},
+	}
+	for _, tt := range tests {
+		if CanTransitionUserStatus(tt.from, tt.to) {
+			t.Errorf("CanTransitionUserStatus(%q, %q) = true", tt.from, tt.to)
+		}
+	}
+}
+
+func TestIsUserStatus(t *testing.T) {
+	for _, status := range []string{
+		UserStatusPending, UserStatusActive, UserStatusSuspended, UserStatusLocked, UserStatusDeactivated,
+	} {
+		if !IsUserStatus(status) {
+			t.Errorf("IsUserStatus(%q) = false", status)
+		}
+	}
+	for _, status := range []string{"", "deleted", "Active"} {
+		if IsUserStatus(status) {
+			t.Errorf("IsUserStatus(%q) = true", status)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+const (
+	TokenPurposePasswordReset     = "password_reset"     // 找回密码
+	TokenPurposeEmailVerification = "email_verification" // 验证邮箱
+)
+
+// UserToken 通过邮件等渠道发给用户的一次性令牌
+// 数据库中只保存令牌哈希，使用后记录使用时间
+type UserToken struct {
+	BaseModel
+	UserID    uint       `gorm:"index;not null" json:"user_id"`         // 用户ID
+	Purpose   string     `gorm:"size:32;index;not null" json:"purpose"` // 用途
+	TokenHash string     `gorm:"size:64;uniqueIndex;not null" json:"-"` // 令牌哈希
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`            // 过期时间
+	UsedAt    *time.Time `json:"used_at,omitempty"`                     // 使用时间
+}
+
+// TableName 指定表名
+func (UserToken) TableName() string {
+	return "user_tokens"
+}
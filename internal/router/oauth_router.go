@@ -0,0 +1,30 @@
+package router
+
+import (
+	"backend/internal/controller"
+	"backend/internal/middleware"
+	"backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerOAuthRoutes 注册OAuth2相关路由
+func registerOAuthRoutes(api *gin.RouterGroup) {
+	oauthController := controller.NewOAuth()
+
+	oauth := api.Group("/oauth")
+	{
+		oauth.POST("/token", oauthController.Token) // 令牌端点（客户端凭证模式）
+	}
+
+	// 客户端管理，仅限具有 oauth_client.manage 权限的用户使用登录令牌操作，模拟登录时不可用
+	clients := oauth.Group("/clients")
+	clients.Use(middleware.RequireAuth(), middleware.EnforceAccountPolicy(), middleware.RequireInteractive(), middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionOAuthClientManage))
+	{
+		clients.POST("", oauthController.CreateClient)                   // 创建客户端
+		clients.GET("", oauthController.ListClients)                     // 获取客户端列表
+		clients.POST("/:id/rotate-secret", oauthController.RotateSecret) // 轮换客户端密钥
+		clients.PUT("/:id/role", oauthController.UpdateClientRole)       // 修改客户端角色
+		clients.DELETE("/:id", oauthController.RevokeClient)             // 撤销客户端
+	}
+}
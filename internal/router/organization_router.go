@@ -3,6 +3,7 @@ package router
 import (
 	"backend/internal/controller"
 	"backend/internal/middleware"
+	"backend/internal/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,15 +11,69 @@ import (
 // registerOrganizationRoutes 注册组织相关路由
 func registerOrganizationRoutes(api *gin.RouterGroup) {
 	orgController := controller.NewOrganization()
+	oidcController := controller.NewOIDC()
+	ldapController := controller.NewLDAP()
+	passwordPolicyController := controller.NewPasswordPolicy()
+	invitationController := controller.NewInvitation()
+	registrationController := controller.NewRegistration()
+	membershipController := controller.NewMembership()
 
-	// 组织相关路由组
+	// API Key 访问组织接口所需的访问范围，邀请和注册审核属于用户管理
+	orgRead := middleware.RequireScope(model.ScopeOrgsRead)
+	orgWrite := middleware.RequireScope(model.ScopeOrgsWrite)
+	usersRead := middleware.RequireScope(model.ScopeUsersRead)
+	usersWrite := middleware.RequireScope(model.ScopeUsersWrite)
+
+	// 角色需要具有的权限
+	orgCreate := middleware.RequirePermission(model.PermissionOrgCreate)
+	orgList := middleware.RequirePermission(model.PermissionOrgRead)
+	orgUpdate := middleware.RequirePermission(model.PermissionOrgUpdate)
+	orgDelete := middleware.RequirePermission(model.PermissionOrgDelete)
+	settingsRead := middleware.RequirePermission(model.PermissionOrgSettingsRead)
+	settingsUpdate := middleware.RequirePermission(model.PermissionOrgSettingsUpdate)
+	childrenRead := middleware.RequirePermission(model.PermissionOrgChildrenRead)
+	childrenManage := middleware.RequirePermission(model.PermissionOrgChildrenManage)
+	userInvite := middleware.RequirePermission(model.PermissionUserInvite)
+	userApprove := middleware.RequirePermission(model.PermissionUserApprove)
+	userRead := middleware.RequirePermission(model.PermissionUserRead)
+	userUpdate := middleware.RequirePermission(model.PermissionUserUpdate)
+
+	// 模拟登录令牌不能修改组织的安全设置
+	noImpersonation := middleware.DenyImpersonation()
+
+	// 组织相关路由组，服务层限制非超级管理员只能访问自己所在的组织及其下级组织
 	orgGroup := api.Group("/organizations")
-	orgGroup.Use(middleware.RequireAuth(), middleware.RequireSuperAdmin())
+	orgGroup.Use(middleware.RequireAuth(), middleware.EnforceAccountPolicy())
 	{
-		orgGroup.POST("", orgController.Create)       // 创建组织
-		orgGroup.GET("", orgController.List)          // 获取组织列表
-		orgGroup.GET("/:id", orgController.Get)       // 获取单个组织
-		orgGroup.PUT("/:id", orgController.Update)    // 更新组织
-		orgGroup.DELETE("/:id", orgController.Delete) // 删除组织
+		orgGroup.POST("", orgWrite, orgCreate, orgController.Create)       // 创建组织
+		orgGroup.GET("", orgRead, orgList, orgController.List)             // 获取组织列表
+		orgGroup.GET("/:id", orgRead, orgList, orgController.Get)          // 获取单个组织
+		orgGroup.PUT("/:id", orgWrite, orgUpdate, orgController.Update)    // 更新组织
+		orgGroup.DELETE("/:id", orgWrite, orgDelete, orgController.Delete) // 删除组织
+
+		orgGroup.GET("/:id/descendants", orgRead, childrenRead, orgController.Descendants)                   // 获取下级组织
+		orgGroup.POST("/:id/children", orgWrite, childrenManage, noImpersonation, orgController.CreateChild) // 创建下级组织
+		orgGroup.POST("/:id/move", orgWrite, childrenManage, noImpersonation, orgController.Move)            // 移动组织
+
+		orgGroup.PUT("/:id/settings", orgWrite, settingsUpdate, noImpersonation, orgController.UpdateSettings)                         // 更新组织安全设置
+		orgGroup.GET("/:id/oidc", orgRead, settingsRead, oidcController.GetProvider)                                                   // 获取OIDC配置
+		orgGroup.PUT("/:id/oidc", orgWrite, settingsUpdate, noImpersonation, oidcController.SaveProvider)                              // 保存OIDC配置
+		orgGroup.DELETE("/:id/oidc", orgWrite, settingsUpdate, noImpersonation, oidcController.DeleteProvider)                         // 删除OIDC配置
+		orgGroup.GET("/:id/ldap", orgRead, settingsRead, ldapController.GetConfig)                                                     // 获取LDAP配置
+		orgGroup.PUT("/:id/ldap", orgWrite, settingsUpdate, noImpersonation, ldapController.SaveConfig)                                // 保存LDAP配置
+		orgGroup.DELETE("/:id/ldap", orgWrite, settingsUpdate, noImpersonation, ldapController.DeleteConfig)                           // 删除LDAP配置
+		orgGroup.POST("/:id/ldap/links", usersWrite, userUpdate, noImpersonation, ldapController.LinkUser)                             // 关联目录账号
+		orgGroup.GET("/:id/password-policy", orgRead, settingsRead, passwordPolicyController.Get)                                      // 获取密码策略
+		orgGroup.PUT("/:id/password-policy", orgWrite, settingsUpdate, noImpersonation, passwordPolicyController.Save)                 // 保存密码策略
+		orgGroup.DELETE("/:id/password-policy", orgWrite, settingsUpdate, noImpersonation, passwordPolicyController.Delete)            // 删除密码策略
+		orgGroup.POST("/:id/invitations", usersWrite, userInvite, noImpersonation, invitationController.Create)                        // 邀请用户加入组织
+		orgGroup.GET("/:id/invitations", usersRead, userInvite, invitationController.List)                                             // 获取待接受的邀请
+		orgGroup.DELETE("/:id/invitations/:invitation_id", usersWrite, userInvite, noImpersonation, invitationController.Revoke)       // 撤销邀请
+		orgGroup.GET("/:id/registrations", usersRead, userApprove, registrationController.List)                                        // 获取待审核的注册
+		orgGroup.POST("/:id/registrations/:user_id/approve", usersWrite, userApprove, noImpersonation, registrationController.Approve) // 通过注册审核
+		orgGroup.POST("/:id/registrations/:user_id/reject", usersWrite, userApprove, noImpersonation, registrationController.Reject)   // 拒绝注册
+		orgGroup.GET("/:id/members", usersRead, userRead, membershipController.List)                                                   // 获取从其他组织加入的成员
+		orgGroup.PUT("/:id/members/:user_id", usersWrite, userUpdate, noImpersonation, membershipController.Update)                    // 修改成员角色
+		orgGroup.DELETE("/:id/members/:user_id", usersWrite, userUpdate, noImpersonation, membershipController.Remove)                 // 移除成员
 	}
 }
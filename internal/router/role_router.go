@@ -0,0 +1,41 @@
+package router
+
+import (
+	"backend/internal/controller"
+	"backend/internal/middleware"
+	"backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerRoleRoutes 注册权限目录和组织角色相关路由
+func registerRoleRoutes(api *gin.RouterGroup) {
+	roleController := controller.NewRole()
+
+	// API Key 访问角色接口所需的访问范围，角色管理属于用户管理
+	usersRead := middleware.RequireScope(model.ScopeUsersRead)
+	usersWrite := middleware.RequireScope(model.ScopeUsersWrite)
+
+	// 角色需要具有的权限
+	roleRead := middleware.RequirePermission(model.PermissionRoleRead)
+	roleManage := middleware.RequirePermission(model.PermissionRoleManage)
+
+	// 模拟登录令牌不能修改组织角色
+	noImpersonation := middleware.DenyImpersonation()
+
+	permissions := api.Group("/permissions")
+	permissions.Use(middleware.RequireAuth(), middleware.EnforceAccountPolicy())
+	{
+		permissions.GET("", roleRead, roleController.ListPermissions) // 获取权限目录
+	}
+
+	// 服务层限制非超级管理员只能管理自己所在组织及其下级组织的角色
+	roles := api.Group("/organizations/:id/roles")
+	roles.Use(middleware.RequireAuth(), middleware.EnforceAccountPolicy())
+	{
+		roles.GET("", usersRead, roleRead, roleController.List)                                   // 获取组织角色列表
+		roles.POST("", usersWrite, roleManage, noImpersonation, roleController.Create)            // 创建自定义角色
+		roles.PUT("/:role_id", usersWrite, roleManage, noImpersonation, roleController.Update)    // 修改自定义角色
+		roles.DELETE("/:role_id", usersWrite, roleManage, noImpersonation, roleController.Delete) // 删除自定义角色
+	}
+}
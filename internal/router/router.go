@@ -13,10 +13,16 @@ func RegisterRoutes(r *gin.Engine) {
 	r.Use(middleware.Recovery())
 	r.Use(middleware.Cors())
 
+	// 公开元数据路由
+	registerWellKnownRoutes(r)
+
 	// API 路由组
 	api := r.Group("/api/v1")
 
 	// 注册各个模块的路由
 	registerUserRoutes(api)
 	registerOrganizationRoutes(api)
+	registerOAuthRoutes(api)
+	registerRoleRoutes(api)
+	registerTeamRoutes(api)
 }
@@ -0,0 +1,40 @@
+package router
+
+import (
+	"backend/internal/controller"
+	"backend/internal/middleware"
+	"backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerTeamRoutes 注册组织团队相关路由
+func registerTeamRoutes(api *gin.RouterGroup) {
+	teamController := controller.NewTeam()
+
+	// API Key 访问团队接口所需的访问范围，团队管理属于用户管理
+	usersRead := middleware.RequireScope(model.ScopeUsersRead)
+	usersWrite := middleware.RequireScope(model.ScopeUsersWrite)
+
+	// 角色需要具有的权限
+	teamRead := middleware.RequirePermission(model.PermissionTeamRead)
+	teamManage := middleware.RequirePermission(model.PermissionTeamManage)
+
+	// 模拟登录令牌不能修改团队
+	noImpersonation := middleware.DenyImpersonation()
+
+	// 服务层限制非超级管理员只能管理自己所在组织及其下级组织的团队
+	teams := api.Group("/organizations/:id/teams")
+	teams.Use(middleware.RequireAuth(), middleware.EnforceAccountPolicy())
+	{
+		teams.GET("", usersRead, teamRead, teamController.List)                                   // 获取团队列表
+		teams.POST("", usersWrite, teamManage, noImpersonation, teamController.Create)            // 创建团队
+		teams.GET("/:team_id", usersRead, teamRead, teamController.Get)                           // 获取团队
+		teams.PUT("/:team_id", usersWrite, teamManage, noImpersonation, teamController.Update)    // 修改团队
+		teams.DELETE("/:team_id", usersWrite, teamManage, noImpersonation, teamController.Delete) // 删除团队
+
+		teams.GET("/:team_id/members", usersRead, teamRead, teamController.Members)                                      // 获取团队成员
+		teams.PUT("/:team_id/members/:user_id", usersWrite, teamManage, noImpersonation, teamController.SetMember)       // 加入团队或修改负责人
+		teams.DELETE("/:team_id/members/:user_id", usersWrite, teamManage, noImpersonation, teamController.RemoveMember) // 移出团队
+	}
+}
@@ -3,6 +3,7 @@ package router
 import (
 	"backend/internal/controller"
 	"backend/internal/middleware"
+	"backend/internal/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,19 +11,132 @@ import (
 // registerUserRoutes 注册用户相关路由
 func registerUserRoutes(api *gin.RouterGroup) {
 	authController := controller.NewAuth()
+	mfaController := controller.NewMFA()
+	apiKeyController := controller.NewAPIKey()
+	oidcController := controller.NewOIDC()
+	sessionController := controller.NewSession()
+	impersonationController := controller.NewImpersonation()
+	invitationController := controller.NewInvitation()
+
+	roleController := controller.NewRole()
+	userController := controller.NewUser()
+	membershipController := controller.NewMembership()
+
+	// 模拟登录令牌不能修改用户的凭证和安全设置
+	noImpersonation := middleware.DenyImpersonation()
+
+	// 角色需要具有的权限
+	apiKeyManage := middleware.RequirePermission(model.PermissionAPIKeyManage)
+	sessionRead := middleware.RequirePermission(model.PermissionSessionRead)
+	sessionRevoke := middleware.RequirePermission(model.PermissionSessionRevoke)
+	userRead := middleware.RequirePermission(model.PermissionUserRead)
+	userCreate := middleware.RequirePermission(model.PermissionUserCreate)
+	userUpdate := middleware.RequirePermission(model.PermissionUserUpdate)
+	userDisable := middleware.RequirePermission(model.PermissionUserDisable)
+	userDelete := middleware.RequirePermission(model.PermissionUserDelete)
+	userResetPassword := middleware.RequirePermission(model.PermissionUserResetPassword)
+	userRevokeTokens := middleware.RequirePermission(model.PermissionUserRevokeTokens)
+	userUnlock := middleware.RequirePermission(model.PermissionUserUnlock)
+	userImpersonate := middleware.RequirePermission(model.PermissionUserImpersonate)
+	adminCreate := middleware.RequirePermission(model.PermissionAdminCreate)
 
 	// 认证相关路由
 	auth := api.Group("/auth")
 	{
-		auth.POST("/login", authController.Login)       // 用户登录
-		auth.POST("/register", authController.Register) // 用户注册
+		auth.POST("/login", authController.Login)            // 用户登录
+		auth.POST("/admin/login", authController.AdminLogin) // 管理员登录
+		auth.POST("/register", authController.Register)      // 用户注册
+		auth.POST("/refresh", authController.Refresh)        // 刷新令牌
+		auth.POST("/mfa/verify", mfaController.Verify)       // 双因素认证登录
+
+		// 找回密码
+		auth.POST("/forgot-password", authController.ForgotPassword)              // 发送重置密码邮件
+		auth.POST("/reset-password/confirm", authController.ConfirmPasswordReset) // 通过邮件令牌重置密码
+
+		// 邮箱验证
+		auth.POST("/verify-email", authController.VerifyEmail)               // 验证邮箱
+		auth.POST("/verify-email/resend", authController.ResendVerification) // 重新发送验证邮件
+
+		// 组织邀请
+		auth.POST("/invitations/accept", invitationController.Accept) // 接受组织邀请
+
+		// OIDC 联合登录
+		auth.GET("/oidc/:org_code/authorize", oidcController.Authorize) // 跳转到组织的身份提供方
+		auth.GET("/oidc/callback", oidcController.Callback)             // 身份提供方授权回调
 
 		// 需要认证的路由
-		authRequired := auth.Use(middleware.RequireAuth())
+		authRequired := auth.Group("", middleware.RequireAuth())
+		{
+			authRequired.GET("/me", authController.GetCurrentUser) // 获取当前用户
+			authRequired.GET("/permissions", roleController.Mine)  // 获取当前用户的权限
+		}
+
+		// 仅限用户登录令牌，未满足组织安全策略时也可访问
+		interactive := authRequired.Group("", middleware.RequireInteractive())
+		{
+			interactive.POST("/logout", authController.Logout)                                   // 注销登录
+			interactive.POST("/change-password", noImpersonation, authController.ChangePassword) // 修改密码，密码过期时也可访问
+
+			// 双因素认证管理
+			interactive.POST("/mfa/totp/enroll", noImpersonation, mfaController.Enroll)                     // 生成双因素认证密钥
+			interactive.POST("/mfa/totp/confirm", noImpersonation, mfaController.Confirm)                   // 确认并启用双因素认证
+			interactive.POST("/mfa/totp/disable", noImpersonation, mfaController.Disable)                   // 关闭双因素认证
+			interactive.POST("/mfa/recovery-codes", noImpersonation, mfaController.RegenerateRecoveryCodes) // 重新生成恢复码
+
+			// 登录会话管理
+			interactive.GET("/sessions", sessionController.List)                             // 获取登录会话列表
+			interactive.DELETE("/sessions", noImpersonation, sessionController.RevokeOthers) // 撤销其他会话
+			interactive.DELETE("/sessions/:id", noImpersonation, sessionController.Revoke)   // 撤销指定会话
+
+			// 多组织
+			interactive.GET("/organizations", membershipController.Organizations)                  // 获取我的组织
+			interactive.POST("/switch-organization", noImpersonation, membershipController.Switch) // 切换组织
+		}
+
+		// 需要满足组织安全策略的路由
+		policyRequired := authRequired.Group("", middleware.EnforceAccountPolicy())
+
+		// 仅限用户本人通过登录令牌操作
+		selfService := policyRequired.Group("", middleware.RequireInteractive())
+		{
+			// API Key 管理
+			selfService.POST("/api-keys", apiKeyManage, noImpersonation, apiKeyController.Create)       // 创建API Key
+			selfService.GET("/api-keys", apiKeyManage, apiKeyController.List)                           // 获取API Key列表
+			selfService.DELETE("/api-keys/:id", apiKeyManage, noImpersonation, apiKeyController.Revoke) // 撤销API Key
+
+			selfService.PATCH("/me", noImpersonation, authController.UpdateProfile)           // 修改个人资料
+			selfService.POST("/invitations/join", noImpersonation, invitationController.Join) // 以现有账号接受其他组织的邀请
+		}
+
+		// 用户管理，API Key 需要 users:write 访问范围
+		userAdmin := policyRequired.Group("", middleware.RequireScope(model.ScopeUsersWrite), noImpersonation)
 		{
-			authRequired.POST("/change-password", authController.ChangePassword) // 修改密码
-			authRequired.POST("/reset-password", authController.ResetPassword)   // 重置密码
-			authRequired.POST("/create-admin", authController.CreateAdmin)       // 创建管理员
+			userAdmin.POST("/reset-password", userResetPassword, authController.ResetPassword)  // 重置密码
+			userAdmin.POST("/create-admin", adminCreate, authController.CreateAdmin)            // 创建管理员
+			userAdmin.POST("/revoke-tokens", userRevokeTokens, authController.RevokeUserTokens) // 强制用户下线
+			userAdmin.POST("/unlock", userUnlock, authController.UnlockLogin)                   // 解除登录锁定
+			userAdmin.POST("/impersonate", userImpersonate, impersonationController.Start)      // 模拟登录
 		}
 	}
+
+	// 管理其他用户，服务层限制非超级管理员只能管理本组织及下级组织的用户
+	usersRead := middleware.RequireScope(model.ScopeUsersRead)
+	usersWrite := middleware.RequireScope(model.ScopeUsersWrite)
+	users := api.Group("/users")
+	users.Use(middleware.RequireAuth(), middleware.EnforceAccountPolicy())
+	{
+		users.GET("", usersRead, userRead, userController.List)                                                                // 获取用户列表
+		users.POST("", usersWrite, userCreate, noImpersonation, userController.Create)                                         // 创建用户
+		users.GET("/:id", usersRead, userRead, userController.Get)                                                             // 获取用户
+		users.PUT("/:id", usersWrite, userUpdate, noImpersonation, userController.Update)                                      // 修改用户资料
+		users.PUT("/:id/role", usersWrite, userUpdate, noImpersonation, userController.ChangeRole)                             // 修改用户角色
+		users.DELETE("/:id", usersWrite, userDelete, noImpersonation, userController.Delete)                                   // 删除用户
+		users.POST("/:id/disable", usersWrite, userDisable, noImpersonation, userController.Disable)                           // 停用用户
+		users.POST("/:id/enable", usersWrite, userDisable, noImpersonation, userController.Enable)                             // 启用用户
+		users.PUT("/:id/status", usersWrite, userDisable, noImpersonation, userController.ChangeStatus)                        // 变更账号状态
+		users.GET("/:id/status-history", usersRead, userRead, userController.StatusHistory)                                    // 获取账号状态变更记录
+		users.GET("/:id/sessions", usersRead, sessionRead, sessionController.ListForUser)                                      // 获取用户的登录会话
+		users.DELETE("/:id/sessions", usersWrite, sessionRevoke, noImpersonation, sessionController.RevokeAllForUser)          // 撤销用户的全部会话
+		users.DELETE("/:id/sessions/:session_id", usersWrite, sessionRevoke, noImpersonation, sessionController.RevokeForUser) // 撤销用户的指定会话
+	}
 }
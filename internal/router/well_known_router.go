@@ -0,0 +1,17 @@
+package router
+
+import (
+	"backend/internal/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerWellKnownRoutes 注册公开元数据路由
+func registerWellKnownRoutes(r *gin.Engine) {
+	wellKnownController := controller.NewWellKnown()
+
+	wellKnown := r.Group("/.well-known")
+	{
+		wellKnown.GET("/jwks.json", wellKnownController.JWKS) // 令牌验证公钥集合
+	}
+}
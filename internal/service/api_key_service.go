@@ -0,0 +1,129 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/database"
+	"backend/pkg/token"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// apiKeyPrefix API Key 的固定前缀，便于在日志和代码仓库中识别
+	apiKeyPrefix = "wz_"
+	// apiKeyIDLength 密钥中用于识别的随机前缀长度
+	apiKeyIDLength = 8
+	// apiKeyTouchInterval 最近使用时间的最小更新间隔，避免每次请求都写库
+	apiKeyTouchInterval = time.Minute
+)
+
+var ErrInvalidAPIKey = errors.New("无效的API Key")
+
+type APIKeyService struct{}
+
+// IsAPIKey 判断凭证是否为 API Key 格式
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, apiKeyPrefix)
+}
+
+// Create 为用户创建 API Key，返回的完整密钥只展示一次
+func (s *APIKeyService) Create(userID uint, name string, scopes []string, expiresAt *time.Time) (*model.APIKey, string, error) {
+	for _, scope := range scopes {
+		if !model.IsValidScope(scope) {
+			return nil, "", fmt.Errorf("无效的访问范围: %s", scope)
+		}
+	}
+	if expiresAt != nil && expiresAt.Before(time.Now()) {
+		return nil, "", errors.New("过期时间不能早于当前时间")
+	}
+
+	idBytes := make([]byte, apiKeyIDLength/2)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, "", errors.New("生成API Key失败")
+	}
+	secret, err := token.Generate(32)
+	if err != nil {
+		return nil, "", errors.New("生成API Key失败")
+	}
+
+	prefix := hex.EncodeToString(idBytes)
+	rawKey := apiKeyPrefix + prefix + "_" + secret
+
+	apiKey := model.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   token.Hash(rawKey),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	if err := database.DB.Create(&apiKey).Error; err != nil {
+		return nil, "", errors.New("创建API Key失败")
+	}
+
+	return &apiKey, rawKey, nil
+}
+
+// List 获取用户的 API Key 列表
+func (s *APIKeyService) List(userID uint) ([]model.APIKey, error) {
+	var keys []model.APIKey
+	if err := database.DB.Where("user_id = ?", userID).Order("id DESC").Find(&keys).Error; err != nil {
+		return nil, errors.New("获取API Key列表失败")
+	}
+	return keys, nil
+}
+
+// Revoke 撤销用户的 API Key
+func (s *APIKeyService) Revoke(userID, id uint) error {
+	result := database.DB.Model(&model.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return errors.New("撤销API Key失败")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("API Key不存在")
+	}
+	return nil
+}
+
+// Authenticate 校验 API Key 并返回密钥及其所属用户
+func (s *APIKeyService) Authenticate(rawKey string) (*model.APIKey, *model.User, error) {
+	// 格式：wz_<前缀>_<密钥>
+	if !IsAPIKey(rawKey) || len(rawKey) <= len(apiKeyPrefix)+apiKeyIDLength+1 {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	prefix := rawKey[len(apiKeyPrefix) : len(apiKeyPrefix)+apiKeyIDLength]
+
+	var apiKey model.APIKey
+	if err := database.DB.Where("prefix = ?", prefix).First(&apiKey).Error; err != nil {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(token.Hash(rawKey))) != 1 {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt)) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	var user model.User
+	if err := database.DB.Preload("Organization").First(&user, apiKey.UserID).Error; err != nil {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	if err := CheckUserStatus(&user); err != nil {
+		return nil, nil, err
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > apiKeyTouchInterval {
+		database.DB.Model(&apiKey).UpdateColumn("last_used_at", now)
+	}
+
+	return &apiKey, &user, nil
+}
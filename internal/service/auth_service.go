@@ -2,51 +2,187 @@ package service
 
 import (
 	"backend/internal/model"
+	"backend/pkg/config"
 	"backend/pkg/database"
+	"backend/pkg/hasher"
 	"backend/pkg/jwt"
+	"backend/pkg/logger"
+	"backend/pkg/mailer"
+	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
-	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
-type AuthService struct{}
+// LoginResult 登录结果
+// 用户启用双因素认证时不签发令牌，只返回 MFA 挑战令牌
+type LoginResult struct {
+	User         *model.User
+	Tokens       *TokenPair
+	MFAChallenge string
+	MFAExpiresIn int64
+}
+
+type AuthService struct {
+	tokenService      TokenService
+	revocationService RevocationService
+	mfaService        MFAService
+	oidcService       OIDCService
+	throttleService   LoginThrottleService
+	passwordService   PasswordPolicyService
+	userTokenService  UserTokenService
+	emailService      EmailVerificationService
+}
 
 // Login 处理用户登录
-func (s *AuthService) Login(username string, password string, organizationCode string) (*model.User, string, error) {
+// client.IP 用于按IP统计失败次数，连续失败时渐进延迟并临时锁定
+func (s *AuthService) Login(username string, password string, organizationCode string, client ClientInfo) (*LoginResult, error) {
 	// 先查找组织
 	var org model.Organization
 	if err := database.DB.Where("code = ?", organizationCode).First(&org).Error; err != nil {
-		return nil, "", errors.New("组织不存在")
+		return nil, errors.New("组织不存在")
+	}
+
+	target := s.throttleService.UserTarget(org.ID, username)
+	if err := s.throttleService.Check(target, client.IP); err != nil {
+		return nil, err
+	}
+
+	// 依次尝试组织启用的认证后端
+	for _, authenticator := range authenticators(&org) {
+		user, err := authenticator.Authenticate(&org, username, password)
+		if errors.Is(err, errUnknownUser) {
+			continue
+		}
+		if err != nil {
+			if errors.Is(err, ErrInvalidCredentials) {
+				s.throttleService.RecordFailure(target, client.IP)
+			}
+			return nil, err
+		}
+		return s.completeLogin(user, target, client)
+	}
+
+	s.throttleService.RecordFailure(target, client.IP)
+	return nil, ErrInvalidCredentials
+}
+
+// AdminLogin 处理超级管理员登录
+func (s *AuthService) AdminLogin(username string, password string, client ClientInfo) (*LoginResult, error) {
+	var systemOrg model.Organization
+	if err := database.DB.Where("code = ?", "system").First(&systemOrg).Error; err != nil {
+		return nil, errors.New("system 组织未找到")
+	}
+
+	target := s.throttleService.UserTarget(systemOrg.ID, username)
+	if err := s.throttleService.Check(target, client.IP); err != nil {
+		return nil, err
 	}
 
 	var user model.User
 	if err := database.DB.Preload("Organization").
-		Where("username = ? AND organization_id = ? AND (role = ? OR role = ? OR role = ?)",
-			username, org.ID, model.RoleOrgMember, model.RoleOrgAdmin, model.RoleSuperAdmin).
+		Where("username = ? AND role = ? AND organization_id = ?",
+			username, model.RoleSuperAdmin, systemOrg.ID).
 		First(&user).Error; err != nil {
-		return nil, "", errors.New("错误的用户名/密码")
+		s.throttleService.RecordFailure(target, client.IP)
+		return nil, ErrInvalidCredentials
 	}
 
-	// 打印用户信息以帮助调试
-	fmt.Printf("User found: %+v\n", user)
-
 	// 验证密码
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return nil, "", errors.New("错误的用户名/密码")
+	if !hasher.Matches(password, user.Password) {
+		s.throttleService.RecordFailure(target, client.IP)
+		return nil, ErrInvalidCredentials
+	}
+	upgradePasswordHash(&user, password)
+
+	return s.completeLogin(&user, target, client)
+}
+
+// UnlockLogin 解除用户的登录锁定（管理员功能）
+func (s *AuthService) UnlockLogin(actor *model.User, userID uint, ip string) error {
+	return s.throttleService.Unlock(actor, userID, ip)
+}
+
+// OIDCLogin 处理OIDC授权回调，身份提供方认证通过后按普通登录流程签发令牌
+func (s *AuthService) OIDCLogin(ctx context.Context, code, state string, client ClientInfo) (*LoginResult, error) {
+	user, err := s.oidcService.Authenticate(ctx, code, state)
+	if err != nil {
+		return nil, err
+	}
+	return s.completeLogin(user, s.throttleService.UserTarget(user.OrganizationID, user.Username), client)
+}
+
+// VerifyMFA 校验双因素认证挑战，通过后签发令牌对
+func (s *AuthService) VerifyMFA(challenge, code string, client ClientInfo) (*model.User, *TokenPair, error) {
+	user, err := s.mfaService.VerifyChallenge(challenge, code, client.IP)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 发出挑战后账号可能已被停用
+	if err := CheckUserStatus(user); err != nil {
+		return nil, nil, err
+	}
+
+	pair, err := s.tokenService.IssueTokenPair(user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, pair, nil
+}
+
+// completeLogin 密码验证通过后完成登录：启用双因素认证的用户返回挑战，否则签发令牌对
+// 账号不是正常状态（如注册尚未通过审核、被停用或锁定）或组织要求验证邮箱而用户尚未验证时拒绝登录
+// target 为登录失败计数对象，启用双因素认证时在验证码通过后才清除失败计数，避免反复登录获得无限次猜测机会
+func (s *AuthService) completeLogin(user *model.User, target string, client ClientInfo) (*LoginResult, error) {
+	if err := CheckUserStatus(user); err != nil {
+		return nil, err
+	}
+	if s.emailService.Required(user) {
+		return nil, ErrEmailNotVerified
+	}
+
+	if s.mfaService.IsEnabled(user.ID) {
+		challenge, ttl, err := s.mfaService.CreateChallenge(user.ID, target)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{
+			User:         user,
+			MFAChallenge: challenge,
+			MFAExpiresIn: int64(ttl.Seconds()),
+		}, nil
 	}
 
-	// 生成 token
-	token, err := jwt.GenerateToken(user.ID, user.Username, user.Role, user.OrganizationID)
+	s.throttleService.RecordSuccess(target)
+	pair, err := s.tokenService.IssueTokenPair(user, client)
 	if err != nil {
-		return nil, "", errors.New("生成token失败")
+		return nil, err
 	}
 
-	return &user, token, nil
+	return &LoginResult{User: user, Tokens: pair}, nil
+}
+
+// Refresh 使用刷新令牌换取新的令牌对
+func (s *AuthService) Refresh(refreshToken string, client ClientInfo) (*model.User, *TokenPair, error) {
+	return s.tokenService.Refresh(refreshToken, client)
 }
 
 // Register 处理用户注册
+// 注册成功后向用户发送验证邮件
 func (s *AuthService) Register(username, password, email string, organizationID uint) (*model.User, error) {
+	var org model.Organization
+	if err := database.DB.First(&org, organizationID).Error; err != nil {
+		return nil, errors.New("组织不存在")
+	}
+	status, err := registrationStatus(&org, email)
+	if err != nil {
+		return nil, err
+	}
+
 	// 检查用户名是否已存在
 	var count int64
 	database.DB.Model(&model.User{}).Where("username = ? AND organization_id = ?", username, organizationID).Count(&count)
@@ -54,24 +190,39 @@ func (s *AuthService) Register(username, password, email string, organizationID
 		return nil, errors.New("用户名已存在于此组织")
 	}
 
-	// 创建用户
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return nil, errors.New("密码哈希失败")
-	}
-
 	user := model.User{
 		Username:       username,
-		Password:       string(hashedPassword),
 		Email:          email,
 		Role:           model.RoleOrgMember,
 		OrganizationID: organizationID,
+		Status:         status,
+	}
+	if err := s.passwordService.Validate(&user, password); err != nil {
+		return nil, err
 	}
 
-	if err := database.DB.Create(&user).Error; err != nil {
+	// 创建用户
+	hashedPassword, err := hasher.Hash(password)
+	if err != nil {
+		return nil, errors.New("密码哈希失败")
+	}
+	now := time.Now()
+	user.Password = hashedPassword
+	user.PasswordChangedAt = &now
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return s.passwordService.Record(tx, user.ID, user.Password)
+	})
+	if err != nil {
 		return nil, errors.New("创建用户失败")
 	}
 
+	user.Organization = org
+	s.emailService.Send(&user)
+
 	return &user, nil
 }
 
@@ -83,7 +234,7 @@ func (s *AuthService) ChangePassword(userID uint, oldPassword, newPassword strin
 	}
 
 	// 验证旧密码
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)); err != nil {
+	if !hasher.Matches(oldPassword, user.Password) {
 		return errors.New("旧密码错误")
 	}
 
@@ -92,43 +243,56 @@ func (s *AuthService) ChangePassword(userID uint, oldPassword, newPassword strin
 		return errors.New("新密码不能与旧密码相同")
 	}
 
-	// 生成新密码哈希
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err := s.setPassword(&user, newPassword); err != nil {
+		return err
+	}
+
+	// 使已签发的令牌全部失效
+	return s.revocationService.RevokeUserTokens(user.ID, "change_password")
+}
+
+// ResetPassword 重置用户密码（管理员功能），只能重置操作者可以管理的用户
+func (s *AuthService) ResetPassword(actor *model.User, userID uint, newPassword string) error {
+	user, err := findManagedUser(actor, userID)
 	if err != nil {
-		return errors.New("密码哈希失败")
+		return err
 	}
 
-	// 更新密码
-	user.Password = string(hashedPassword)
-	if err := database.DB.Save(&user).Error; err != nil {
-		return errors.New("密码更新失败")
+	if err := s.setPassword(user, newPassword); err != nil {
+		return err
 	}
 
-	return nil
+	// 使已签发的令牌全部失效
+	return s.revocationService.RevokeUserTokens(user.ID, "reset_password")
 }
 
-// ResetPassword 重置用户密码（管理员功能）
-func (s *AuthService) ResetPassword(userID uint, newPassword string) error {
-	var user model.User
-	if err := database.DB.First(&user, userID).Error; err != nil {
-		return errors.New("用户不存在")
+// Logout 注销当前登录：撤销当前访问令牌和所在的会话，并在提供刷新令牌时撤销其令牌族
+func (s *AuthService) Logout(claims *jwt.CustomClaims, refreshToken string) error {
+	if err := s.revocationService.RevokeToken(claims, "logout"); err != nil {
+		return err
 	}
-
-	// 生成新密码哈希
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
-	if err != nil {
-		return errors.New("密码哈希失败")
+	if claims.SessionID != "" {
+		if err := s.tokenService.RevokeFamily(claims.SessionID); err != nil {
+			return err
+		}
 	}
-
-	// 更新密码
-	user.Password = string(hashedPassword)
-	if err := database.DB.Save(&user).Error; err != nil {
-		return errors.New("密码更新失败")
+	if refreshToken != "" {
+		if err := s.tokenService.RevokeByRefreshToken(claims.UserID, refreshToken); err != nil {
+			return err
+		}
 	}
-
 	return nil
 }
 
+// RevokeUserTokens 强制用户下线（管理员功能），只能操作操作者可以管理的用户
+func (s *AuthService) RevokeUserTokens(actor *model.User, userID uint) error {
+	user, err := findManagedUser(actor, userID)
+	if err != nil {
+		return err
+	}
+	return s.revocationService.RevokeUserTokens(user.ID, "admin_revoke")
+}
+
 // CreateAdmin 创建超级管理员
 func (s *AuthService) CreateAdmin(username, password, email string) (*model.User, error) {
 	// 检查用户名是否已存在
@@ -138,22 +302,162 @@ func (s *AuthService) CreateAdmin(username, password, email string) (*model.User
 		return nil, errors.New("管理员用户名已存在")
 	}
 
+	// 超级管理员属于系统组织，按系统组织的密码策略校验
+	var systemOrg model.Organization
+	if err := database.DB.Where("code = ?", "system").First(&systemOrg).Error; err != nil {
+		return nil, errors.New("系统组织不存在")
+	}
+	admin := model.User{
+		Username:       username,
+		Email:          email,
+		Role:           model.RoleSuperAdmin,
+		OrganizationID: systemOrg.ID,
+	}
+	if err := s.passwordService.Validate(&admin, password); err != nil {
+		return nil, err
+	}
+
 	// 创建管理员用户
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := hasher.Hash(password)
 	if err != nil {
 		return nil, errors.New("密码哈希失败")
 	}
+	now := time.Now()
+	admin.Password = hashedPassword
+	admin.PasswordChangedAt = &now
 
-	admin := model.User{
-		Username: username,
-		Password: string(hashedPassword),
-		Email:    email,
-		Role:     model.RoleSuperAdmin,
-	}
-
-	if err := database.DB.Create(&admin).Error; err != nil {
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&admin).Error; err != nil {
+			return err
+		}
+		return s.passwordService.Record(tx, admin.ID, admin.Password)
+	})
+	if err != nil {
 		return nil, errors.New("管理员创建失败")
 	}
 
 	return &admin, nil
 }
+
+// ForgotPassword 向组织内使用该邮箱的用户发送重置密码邮件
+// 无论账号是否存在都不返回错误，避免泄露账号信息
+func (s *AuthService) ForgotPassword(organizationCode, email string) {
+	var user model.User
+	err := database.DB.Preload("Organization").
+		Joins("JOIN organizations ON organizations.id = users.organization_id AND organizations.deleted_at IS NULL").
+		Where("organizations.code = ? AND users.email = ? AND users.is_service_account = ?", organizationCode, email, false).
+		First(&user).Error
+	// 目录用户和联合登录用户没有本地密码，不能通过邮件重置
+	if err != nil || user.Password == "" {
+		return
+	}
+
+	// 签发令牌和发送邮件在后台进行，避免响应时间暴露账号是否存在
+	go s.sendPasswordReset(user)
+}
+
+// ConfirmPasswordReset 使用重置密码邮件中的令牌设置新密码
+func (s *AuthService) ConfirmPasswordReset(rawToken, newPassword string) error {
+	record, err := s.userTokenService.Lookup(rawToken, model.TokenPurposePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	var user model.User
+	if err := database.DB.First(&user, record.UserID).Error; err != nil {
+		return ErrInvalidUserToken
+	}
+
+	// 先校验密码策略，不符合时令牌仍可继续使用
+	if err := s.passwordService.Validate(&user, newPassword); err != nil {
+		return err
+	}
+	if err := s.userTokenService.Consume(record); err != nil {
+		return err
+	}
+	if err := s.setPassword(&user, newPassword); err != nil {
+		return err
+	}
+
+	// 能收到邮件说明是本人，同时解除登录锁定
+	s.throttleService.RecordSuccess(s.throttleService.UserTarget(user.OrganizationID, user.Username))
+
+	logger.WithFields(map[string]interface{}{
+		"event":   "password_reset",
+		"user_id": user.ID,
+	}).Info("用户通过邮件重置了密码")
+
+	// 使已签发的令牌全部失效
+	return s.revocationService.RevokeUserTokens(user.ID, "password_reset")
+}
+
+// sendPasswordReset 签发重置密码令牌并发送邮件
+func (s *AuthService) sendPasswordReset(user model.User) {
+	ttl := config.GetDuration("security.password_reset.ttl")
+	if ttl <= 0 {
+		ttl = 30 * time.Minute
+	}
+	interval := config.GetDuration("security.password_reset.resend_interval")
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	fields := map[string]interface{}{"user_id": user.ID}
+
+	// 限制发送频率，避免被用来向用户邮箱大量发信
+	if s.userTokenService.RecentlyIssued(user.ID, model.TokenPurposePasswordReset, interval) {
+		logger.WithFields(fields).Info("重置密码邮件发送过于频繁，已忽略")
+		return
+	}
+
+	rawToken, err := s.userTokenService.Issue(user.ID, model.TokenPurposePasswordReset, ttl)
+	if err != nil {
+		logger.WithFields(fields).Error("签发重置密码令牌失败: " + err.Error())
+		return
+	}
+
+	link := strings.ReplaceAll(config.GetString("security.password_reset.url"), "{token}", rawToken)
+	err = mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "重置密码",
+		Body: fmt.Sprintf("%s，您好：\n\n我们收到了重置您在组织 %s 的账号密码的请求。请在%d分钟内打开以下链接设置新密码：\n\n%s\n\n如果这不是您本人的操作，请忽略此邮件，您的密码不会被修改。",
+			user.Username, user.Organization.Code, int(ttl.Minutes()), link),
+	})
+	if err != nil {
+		logger.WithFields(fields).Error("发送重置密码邮件失败: " + err.Error())
+		return
+	}
+	logger.WithFields(map[string]interface{}{
+		"event":   "password_reset_requested",
+		"user_id": user.ID,
+	}).Info("已发送重置密码邮件")
+}
+
+// setPassword 按组织密码策略校验新密码，保存哈希并记录密码历史
+func (s *AuthService) setPassword(user *model.User, password string) error {
+	if err := s.passwordService.Validate(user, password); err != nil {
+		return err
+	}
+
+	hashedPassword, err := hasher.Hash(password)
+	if err != nil {
+		return errors.New("密码哈希失败")
+	}
+
+	now := time.Now()
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Updates(map[string]interface{}{
+			"password":            hashedPassword,
+			"password_changed_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		return s.passwordService.Record(tx, user.ID, hashedPassword)
+	})
+	if err != nil {
+		return errors.New("密码更新失败")
+	}
+
+	// 密码已更新，之前发出的重置密码链接全部作废
+	s.userTokenService.Revoke(user.ID, model.TokenPurposePasswordReset)
+	return nil
+}
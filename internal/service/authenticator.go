@@ -0,0 +1,85 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/database"
+	"backend/pkg/hasher"
+	"backend/pkg/logger"
+	"errors"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("错误的用户名/密码")
+
+	// errUnknownUser 认证后端中不存在该用户，交由下一个认证后端处理
+	errUnknownUser = errors.New("unknown user")
+)
+
+// Authenticator 用户名密码认证后端
+type Authenticator interface {
+	// Authenticate 校验组织内用户的凭证，成功时返回对应的用户（已预加载组织）
+	// 后端中不存在该用户时返回 errUnknownUser
+	Authenticate(org *model.Organization, username, password string) (*model.User, error)
+}
+
+// PasswordAuthenticator 使用本地密码认证
+type PasswordAuthenticator struct{}
+
+// Authenticate 校验本地密码
+func (a *PasswordAuthenticator) Authenticate(org *model.Organization, username, password string) (*model.User, error) {
+	var user model.User
+	if err := database.DB.Preload("Organization").
+		Where("username = ? AND organization_id = ?", username, org.ID).
+		Where("is_service_account = ?", false).
+		First(&user).Error; err != nil {
+		return nil, errUnknownUser
+	}
+
+	// 验证密码
+	if !hasher.Matches(password, user.Password) {
+		return nil, ErrInvalidCredentials
+	}
+	upgradePasswordHash(&user, password)
+
+	return &user, nil
+}
+
+// upgradePasswordHash 密码校验通过后，使用当前配置的算法和参数重新计算过时的哈希
+// 只替换哈希本身，不影响密码修改时间和历史记录
+func upgradePasswordHash(user *model.User, password string) {
+	if !hasher.NeedsRehash(user.Password) {
+		return
+	}
+
+	fields := map[string]interface{}{"user_id": user.ID}
+	hashed, err := hasher.Hash(password)
+	if err != nil {
+		logger.WithFields(fields).Warn("重新计算密码哈希失败: " + err.Error())
+		return
+	}
+	// 仅在密码未被同时修改时替换
+	result := database.DB.Model(&model.User{}).
+		Where("id = ? AND password = ?", user.ID, user.Password).
+		Update("password", hashed)
+	if result.Error != nil {
+		logger.WithFields(fields).Warn("更新密码哈希失败: " + result.Error.Error())
+		return
+	}
+	if result.RowsAffected == 1 {
+		user.Password = hashed
+		logger.WithFields(fields).Info("已升级用户的密码哈希")
+	}
+}
+
+// authenticators 获取组织启用的认证后端，按顺序尝试
+func authenticators(org *model.Organization) []Authenticator {
+	var chain []Authenticator
+
+	var ldapConfig model.LDAPConfig
+	if err := database.DB.Where("organization_id = ? AND enabled = ?", org.ID, true).
+		First(&ldapConfig).Error; err == nil {
+		chain = append(chain, &LDAPAuthenticator{config: &ldapConfig})
+	}
+
+	return append(chain, &PasswordAuthenticator{})
+}
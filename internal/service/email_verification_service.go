@@ -0,0 +1,132 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/config"
+	"backend/pkg/database"
+	"backend/pkg/logger"
+	"backend/pkg/mailer"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrEmailNotVerified 组织要求验证邮箱而用户尚未验证
+var ErrEmailNotVerified = errors.New("邮箱尚未验证，请先完成验证")
+
+// EmailVerificationService 邮箱验证
+type EmailVerificationService struct {
+	userTokenService UserTokenService
+}
+
+// Required 检查用户所在组织是否要求验证邮箱而用户尚未验证
+// 按邮箱域名开放注册的组织始终要求验证；目录用户和联合登录用户的邮箱由身份提供方维护，不需要验证
+func (s *EmailVerificationService) Required(user *model.User) bool {
+	org := user.Organization
+	if !org.RequireEmailVerification && org.RegistrationMode != model.RegistrationDomain {
+		return false
+	}
+	return user.EmailVerifiedAt == nil && user.Password != "" && !user.IsServiceAccount
+}
+
+// Send 在后台向用户发送验证邮件，发送过于频繁时忽略
+func (s *EmailVerificationService) Send(user *model.User) {
+	if user.Email == "" || user.EmailVerifiedAt != nil {
+		return
+	}
+	go s.send(*user)
+}
+
+// Restart 邮箱变更后作废之前发出的验证链接，并向新邮箱发送验证邮件
+func (s *EmailVerificationService) Restart(user *model.User) {
+	if err := s.userTokenService.Revoke(user.ID, model.TokenPurposeEmailVerification); err != nil {
+		logger.WithFields(map[string]interface{}{"user_id": user.ID}).Error("作废邮箱验证令牌失败: " + err.Error())
+	}
+	s.Send(user)
+}
+
+// Resend 重新发送验证邮件
+// 无论账号是否存在都不返回错误，避免泄露账号信息
+func (s *EmailVerificationService) Resend(organizationCode, email string) {
+	var user model.User
+	err := database.DB.Preload("Organization").
+		Joins("JOIN organizations ON organizations.id = users.organization_id AND organizations.deleted_at IS NULL").
+		Where("organizations.code = ? AND users.email = ? AND users.is_service_account = ?", organizationCode, email, false).
+		First(&user).Error
+	if err != nil {
+		return
+	}
+	s.Send(&user)
+}
+
+// Confirm 使用验证邮件中的令牌确认邮箱
+func (s *EmailVerificationService) Confirm(rawToken string) (*model.User, error) {
+	record, err := s.userTokenService.Lookup(rawToken, model.TokenPurposeEmailVerification)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userTokenService.Consume(record); err != nil {
+		return nil, err
+	}
+
+	var user model.User
+	if err := database.DB.First(&user, record.UserID).Error; err != nil {
+		return nil, ErrInvalidUserToken
+	}
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		if err := database.DB.Model(&user).Update("email_verified_at", now).Error; err != nil {
+			return nil, errors.New("验证邮箱失败")
+		}
+		user.EmailVerifiedAt = &now
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"event":   "email_verified",
+		"user_id": user.ID,
+	}).Info("用户完成了邮箱验证")
+	return &user, nil
+}
+
+// send 签发验证令牌并发送邮件
+func (s *EmailVerificationService) send(user model.User) {
+	ttl := config.GetDuration("security.email_verification.ttl")
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	interval := config.GetDuration("security.email_verification.resend_interval")
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	fields := map[string]interface{}{"user_id": user.ID}
+
+	// 限制发送频率，避免被用来向用户邮箱大量发信
+	if s.userTokenService.RecentlyIssued(user.ID, model.TokenPurposeEmailVerification, interval) {
+		logger.WithFields(fields).Info("验证邮件发送过于频繁，已忽略")
+		return
+	}
+
+	rawToken, err := s.userTokenService.Issue(user.ID, model.TokenPurposeEmailVerification, ttl)
+	if err != nil {
+		logger.WithFields(fields).Error("签发邮箱验证令牌失败: " + err.Error())
+		return
+	}
+
+	link := strings.ReplaceAll(config.GetString("security.email_verification.url"), "{token}", rawToken)
+	err = mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "验证您的邮箱",
+		Body: fmt.Sprintf("%s，您好：\n\n请在%d小时内打开以下链接，确认 %s 是您在组织 %s 使用的邮箱：\n\n%s\n\n如果您没有注册账号，请忽略此邮件。",
+			user.Username, int(math.Ceil(ttl.Hours())), user.Email, user.Organization.Code, link),
+	})
+	if err != nil {
+		logger.WithFields(fields).Error("发送验证邮件失败: " + err.Error())
+		return
+	}
+	logger.WithFields(map[string]interface{}{
+		"event":   "email_verification_sent",
+		"user_id": user.ID,
+	}).Info("已发送邮箱验证邮件")
+}
@@ -0,0 +1,7 @@
+package service
+
+import "errors"
+
+var (
+	ErrForbidden = errors.New("无权操作该资源")
+)
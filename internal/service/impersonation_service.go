@@ -0,0 +1,108 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/config"
+	"backend/pkg/database"
+	"backend/pkg/jwt"
+	"backend/pkg/logger"
+	"errors"
+	"time"
+)
+
+var (
+	ErrImpersonationDenied  = errors.New("模拟登录时不允许此操作")
+	ErrImpersonationInvalid = errors.New("模拟登录已失效")
+)
+
+// ImpersonationResult 模拟登录结果
+type ImpersonationResult struct {
+	User      *model.User
+	Token     string
+	ExpiresIn int64 // 访问令牌有效期（秒）
+	ExpiresAt time.Time
+}
+
+type ImpersonationService struct {
+	revocationService RevocationService
+}
+
+// Start 超级管理员以目标用户的身份签发模拟登录令牌
+// 模拟登录令牌不附带刷新令牌，到期后需要重新发起
+func (s *ImpersonationService) Start(actor *model.User, userID uint, reason string, client ClientInfo) (*ImpersonationResult, error) {
+	if actor.Role != model.RoleSuperAdmin {
+		return nil, ErrForbidden
+	}
+
+	var user model.User
+	if err := database.DB.Preload("Organization").First(&user, userID).Error; err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	if user.ID == actor.ID {
+		return nil, errors.New("不能模拟登录自己")
+	}
+	if user.Role == model.RoleSuperAdmin {
+		return nil, errors.New("不能模拟登录超级管理员")
+	}
+	if user.IsServiceAccount {
+		return nil, errors.New("不能模拟登录服务账号")
+	}
+
+	ttl := config.GetDuration("security.impersonation.ttl")
+	if ttl <= 0 {
+		ttl = 30 * time.Minute
+	}
+	expiresAt := time.Now().Add(ttl)
+
+	claims := &jwt.CustomClaims{
+		UserID:         user.ID,
+		Username:       user.Username,
+		Role:           user.Role,
+		OrganizationID: user.OrganizationID,
+		Actor:          &jwt.Actor{UserID: actor.ID, Username: actor.Username},
+	}
+	claims.ExpiresAt = jwt.NewNumericDate(expiresAt)
+	accessToken, err := jwt.Sign(claims)
+	if err != nil {
+		return nil, errors.New("生成token失败")
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"event":           "impersonation_started",
+		"impersonator_id": actor.ID,
+		"user_id":         user.ID,
+		"organization_id": user.OrganizationID,
+		"reason":          reason,
+		"jti":             claims.ID,
+		"ip":              client.IP,
+		"expires_at":      expiresAt,
+	}).Warn("超级管理员开始模拟登录")
+
+	return &ImpersonationResult{User: &user, Token: accessToken, ExpiresIn: int64(ttl.Seconds()), ExpiresAt: expiresAt}, nil
+}
+
+// Verify 校验模拟登录令牌的操作者仍为超级管理员且其令牌未被整体撤销
+func (s *ImpersonationService) Verify(claims *jwt.CustomClaims) error {
+	var actor model.User
+	if err := database.DB.First(&actor, claims.Actor.UserID).Error; err != nil {
+		return ErrImpersonationInvalid
+	}
+	if actor.Role != model.RoleSuperAdmin || s.revocationService.IsUserTokenRevoked(&actor, claims) {
+		return ErrImpersonationInvalid
+	}
+	return nil
+}
+
+// Audit 记录模拟登录期间的每个请求
+func (s *ImpersonationService) Audit(claims *jwt.CustomClaims, method, path string, status int, ip string) {
+	logger.WithFields(map[string]interface{}{
+		"event":           "impersonation_request",
+		"impersonator_id": claims.Actor.UserID,
+		"user_id":         claims.UserID,
+		"jti":             claims.ID,
+		"method":          method,
+		"path":            path,
+		"status":          status,
+		"ip":              ip,
+	}).Info("模拟登录请求")
+}
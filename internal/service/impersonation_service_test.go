@@ -0,0 +1,132 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/config"
+	"backend/pkg/database"
+	"backend/pkg/jwt"
+	"errors"
+	"testing"
+	"time"
+)
+
+// createSuperAdmin 创建系统组织中的超级管理员
+func createSuperAdmin(t *testing.T, username string) *model.User {
+	t.Helper()
+	var system model.Organization
+	if err := database.DB.Where(model.Organization{Code: "system"}).FirstOrCreate(&system).Error; err != nil {
+		t.Fatal(err)
+	}
+	admin := model.User{
+		Username: username,
+		Email:    username + "@example.com",
+		Role:     model.RoleSuperAdmin,
+		Status:   model.UserStatusActive,
+	}
+	if err := database.DB.Create(&admin).Error; err != nil {
+		t.Fatal(err)
+	}
+	return &admin
+}
+
+func TestImpersonationIssuesTimeLimitedToken(t *testing.T) {
+	setupTestDB(t)
+	config.Config.Set("security.impersonation.ttl", "10m")
+	t.Cleanup(func() { config.Config.Set("security.impersonation.ttl", "") })
+	user := createTestUser(t, "acme", "alice")
+	admin := createSuperAdmin(t, "root")
+	service := &ImpersonationService{}
+
+	result, err := service.Start(admin, user.ID, "ticket #42", ClientInfo{IP: "192.0.2.1"})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if result.User.ID != user.ID || result.ExpiresIn != 600 {
+		t.Errorf("unexpected result: user %d, expires in %d", result.User.ID, result.ExpiresIn)
+	}
+
+	claims, err := jwt.ParseToken(result.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != user.ID || claims.Role != model.RoleOrgMember || claims.SessionID != "" {
+		t.Errorf("token not issued for the impersonated user: %+v", claims)
+	}
+	if claims.Actor == nil || claims.Actor.UserID != admin.ID || claims.Actor.Username != admin.Username {
+		t.Errorf("act claim = %+v, want the super admin", claims.Actor)
+	}
+	if ttl := time.Until(claims.ExpiresAt.Time); ttl <= 9*time.Minute || ttl > 10*time.Minute {
+		t.Errorf("token expires in %v, want 10m", ttl)
+	}
+	if err := service.Verify(claims); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+}
+
+func TestImpersonationStartRejected(t *testing.T) {
+	setupTestDB(t)
+	user := createTestUser(t, "acme", "alice")
+	admin := createSuperAdmin(t, "root")
+	orgAdmin := &model.User{Role: model.RoleOrgAdmin, OrganizationID: user.OrganizationID}
+
+	serviceAccount := model.User{Username: "ci-bot", Email: "ci-bot@example.com", Role: model.RoleOrgMember,
+		OrganizationID: user.OrganizationID, IsServiceAccount: true}
+	if err := database.DB.Create(&serviceAccount).Error; err != nil {
+		t.Fatal(err)
+	}
+	otherAdmin := createSuperAdmin(t, "root2")
+
+	service := &ImpersonationService{}
+	if _, err := service.Start(orgAdmin, user.ID, "", ClientInfo{}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("organization admin: err = %v, want ErrForbidden", err)
+	}
+	for name, target := range map[string]uint{
+		"self":            admin.ID,
+		"super admin":     otherAdmin.ID,
+		"service account": serviceAccount.ID,
+		"missing user":    9999,
+	} {
+		if _, err := service.Start(admin, target, "", ClientInfo{}); err == nil {
+			t.Errorf("impersonated %s", name)
+		}
+	}
+}
+
+// 操作者不再是超级管理员或其令牌被整体撤销后，模拟登录令牌立即失效
+func TestImpersonationVerifyActor(t *testing.T) {
+	setupTestDB(t)
+	user := createTestUser(t, "acme", "alice")
+	admin := createSuperAdmin(t, "root")
+	service := &ImpersonationService{}
+
+	start := func() *jwt.CustomClaims {
+		t.Helper()
+		result, err := service.Start(admin, user.ID, "", ClientInfo{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		claims, err := jwt.ParseToken(result.Token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return claims
+	}
+
+	claims := start()
+	database.DB.Model(admin).Update("tokens_revoked_at", time.Now().Add(time.Second))
+	if err := service.Verify(claims); !errors.Is(err, ErrImpersonationInvalid) {
+		t.Errorf("actor tokens revoked: err = %v, want ErrImpersonationInvalid", err)
+	}
+
+	database.DB.Model(admin).Update("tokens_revoked_at", nil)
+	claims = start()
+	database.DB.Model(admin).Update("role", model.RoleOrgAdmin)
+	if err := service.Verify(claims); !errors.Is(err, ErrImpersonationInvalid) {
+		t.Errorf("actor demoted: err = %v, want ErrImpersonationInvalid", err)
+	}
+
+	database.DB.Delete(admin)
+	if err := service.Verify(claims); !errors.Is(err, ErrImpersonationInvalid) {
+		t.Errorf("actor deleted: err = %v, want ErrImpersonationInvalid", err)
+	}
+}
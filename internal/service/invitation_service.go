@@ -0,0 +1,341 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/config"
+	"backend/pkg/database"
+	"backend/pkg/hasher"
+	"backend/pkg/logger"
+	"backend/pkg/mailer"
+	"backend/pkg/token"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidInvitation    = errors.New("邀请链接无效或已过期")
+	ErrInvitationNotFound   = errors.New("邀请不存在")
+	ErrInvitationIncomplete = errors.New("请提供用户名和密码以创建账号")
+)
+
+type InvitationService struct {
+	passwordService PasswordPolicyService
+	roleService     RoleService
+}
+
+// Create 邀请邮箱加入组织，并向该邮箱发送邀请链接
+// 同一邮箱之前未接受的邀请全部作废
+func (s *InvitationService) Create(actor *model.User, organizationID uint, email, role string) (*model.Invitation, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+	if role == "" {
+		role = model.RoleOrgMember
+	}
+	if err := s.roleService.Assignable(actor, organizationID, role); err != nil {
+		return nil, err
+	}
+
+	var org model.Organization
+	if err := database.DB.First(&org, organizationID).Error; err != nil {
+		return nil, errors.New("组织不存在")
+	}
+	if org.Code == "system" {
+		return nil, errors.New("不能邀请用户加入系统组织")
+	}
+
+	ttl := config.GetDuration("security.invitation.ttl")
+	if ttl <= 0 {
+		ttl = 7 * 24 * time.Hour
+	}
+	raw, err := token.Generate(32)
+	if err != nil {
+		return nil, errors.New("生成令牌失败")
+	}
+
+	invitation := model.Invitation{
+		OrganizationID: org.ID,
+		Email:          email,
+		Role:           role,
+		TokenHash:      token.Hash(raw),
+		InvitedByID:    actor.ID,
+		ExpiresAt:      time.Now().Add(ttl),
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Invitation{}).
+			Where("organization_id = ? AND email = ? AND accepted_at IS NULL AND revoked_at IS NULL", org.ID, email).
+			Update("revoked_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(&invitation).Error
+	})
+	if err != nil {
+		return nil, errors.New("创建邀请失败")
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"event":           "invitation_created",
+		"invitation_id":   invitation.ID,
+		"organization_id": org.ID,
+		"invited_by":      actor.ID,
+		"role":            role,
+	}).Info("已创建组织邀请")
+
+	go s.send(invitation, org, actor.Username, raw, ttl)
+	return &invitation, nil
+}
+
+// List 获取组织未接受、未撤销且未过期的邀请
+func (s *InvitationService) List(actor *model.User, organizationID uint) ([]model.Invitation, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+
+	var invitations []model.Invitation
+	if err := database.DB.Where("organization_id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?",
+		organizationID, time.Now()).Order("id DESC").Find(&invitations).Error; err != nil {
+		return nil, errors.New("获取邀请列表失败")
+	}
+	return invitations, nil
+}
+
+// Revoke 撤销组织未接受的邀请
+func (s *InvitationService) Revoke(actor *model.User, organizationID, invitationID uint) error {
+	if !canAdministerOrganization(actor, organizationID) {
+		return ErrForbidden
+	}
+
+	result := database.DB.Model(&model.Invitation{}).
+		Where("id = ? AND organization_id = ? AND accepted_at IS NULL AND revoked_at IS NULL", invitationID, organizationID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return errors.New("撤销邀请失败")
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
+
+// Accept 接受邀请
+// 受邀邮箱已是组织成员时关联到该用户，否则使用提供的用户名和密码创建账号
+// 返回加入组织的用户以及是否新建了账号
+func (s *InvitationService) Accept(rawToken, username, password string) (*model.User, bool, error) {
+	var invitation model.Invitation
+	if err := database.DB.Where("token_hash = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?",
+		token.Hash(rawToken), time.Now()).First(&invitation).Error; err != nil {
+		return nil, false, ErrInvalidInvitation
+	}
+
+	var org model.Organization
+	if err := database.DB.First(&org, invitation.OrganizationID).Error; err != nil {
+		return nil, false, ErrInvalidInvitation
+	}
+
+	var user model.User
+	created := false
+	err := database.DB.Where("organization_id = ? AND email = ? AND is_service_account = ?",
+		org.ID, invitation.Email, false).First(&user).Error
+	switch {
+	case err == nil:
+		err = s.link(&invitation, &user)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		created = true
+		err = s.create(&invitation, &user, username, password)
+	default:
+		err = errors.New("查询用户失败")
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"event":           "invitation_accepted",
+		"invitation_id":   invitation.ID,
+		"organization_id": org.ID,
+		"user_id":         user.ID,
+		"created":         created,
+	}).Info("用户接受了组织邀请")
+
+	user.Organization = org
+	return &user, created, nil
+}
+
+// Join 已登录的其他组织用户接受邀请，以成员身份加入邀请的组织，之后可以切换到该组织
+// 用户的邮箱必须与受邀邮箱一致，本地账号还需已验证邮箱
+func (s *InvitationService) Join(userID uint, rawToken string) (*model.Membership, error) {
+	var invitation model.Invitation
+	if err := database.DB.Where("token_hash = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?",
+		token.Hash(rawToken), time.Now()).First(&invitation).Error; err != nil {
+		return nil, ErrInvalidInvitation
+	}
+
+	var user model.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+	switch {
+	case user.Role == model.RoleSuperAdmin || user.IsServiceAccount:
+		return nil, errors.New("超级管理员和服务账号不能加入其他组织")
+	case !strings.EqualFold(user.Email, invitation.Email):
+		return nil, errors.New("邀请的邮箱与当前账号的邮箱不一致")
+	case user.EmailVerifiedAt == nil && user.Password != "":
+		return nil, ErrEmailNotVerified
+	case user.OrganizationID == invitation.OrganizationID:
+		return nil, errors.New("您已属于该组织")
+	}
+
+	var count int64
+	database.DB.Model(&model.Membership{}).Where("user_id = ? AND organization_id = ?", user.ID, invitation.OrganizationID).Count(&count)
+	if count > 0 {
+		return nil, errors.New("您已是该组织的成员")
+	}
+
+	membership := model.Membership{
+		UserID:         user.ID,
+		OrganizationID: invitation.OrganizationID,
+		Role:           invitation.Role,
+		InvitedByID:    &invitation.InvitedByID,
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.consume(tx, &invitation, user.ID); err != nil {
+			return err
+		}
+		return tx.Create(&membership).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidInvitation) {
+			return nil, err
+		}
+		return nil, errors.New("加入组织失败")
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"event":           "invitation_joined",
+		"invitation_id":   invitation.ID,
+		"organization_id": invitation.OrganizationID,
+		"user_id":         user.ID,
+		"role":            membership.Role,
+	}).Info("其他组织的用户通过邀请加入了组织")
+	return &membership, nil
+}
+
+// link 将邀请关联到组织中已有的用户：邮箱视为已验证，用户仍为组织成员时改为邀请的角色
+func (s *InvitationService) link(invitation *model.Invitation, user *model.User) error {
+	updates := map[string]interface{}{}
+	if user.EmailVerifiedAt == nil {
+		updates["email_verified_at"] = time.Now()
+	}
+	if user.Role == model.RoleOrgMember && invitation.Role != model.RoleOrgMember {
+		updates["role"] = invitation.Role
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.consume(tx, invitation, user.ID); err != nil {
+			return err
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(user).Updates(updates).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidInvitation) {
+			return err
+		}
+		return errors.New("接受邀请失败")
+	}
+	return nil
+}
+
+// create 为受邀邮箱创建账号，邮箱视为已验证
+func (s *InvitationService) create(invitation *model.Invitation, user *model.User, username, password string) error {
+	if username == "" || password == "" {
+		return ErrInvitationIncomplete
+	}
+
+	var count int64
+	database.DB.Model(&model.User{}).Where("username = ? AND organization_id = ?", username, invitation.OrganizationID).Count(&count)
+	if count > 0 {
+		return errors.New("用户名已存在于此组织")
+	}
+
+	now := time.Now()
+	*user = model.User{
+		Username:          username,
+		Email:             invitation.Email,
+		EmailVerifiedAt:   &now,
+		Role:              invitation.Role,
+		OrganizationID:    invitation.OrganizationID,
+		PasswordChangedAt: &now,
+	}
+	if err := s.passwordService.Validate(user, password); err != nil {
+		return err
+	}
+	hashedPassword, err := hasher.Hash(password)
+	if err != nil {
+		return errors.New("密码哈希失败")
+	}
+	user.Password = hashedPassword
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		if err := s.passwordService.Record(tx, user.ID, user.Password); err != nil {
+			return err
+		}
+		return s.consume(tx, invitation, user.ID)
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidInvitation) {
+			return err
+		}
+		return errors.New("创建用户失败")
+	}
+	return nil
+}
+
+// consume 将邀请标记为已接受，并发请求中只有一个能成功
+func (s *InvitationService) consume(tx *gorm.DB, invitation *model.Invitation, userID uint) error {
+	now := time.Now()
+	result := tx.Model(&model.Invitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", invitation.ID, now).
+		Updates(map[string]interface{}{"accepted_at": now, "accepted_by_id": userID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidInvitation
+	}
+	invitation.AcceptedAt = &now
+	invitation.AcceptedByID = &userID
+	return nil
+}
+
+// send 发送邀请邮件
+func (s *InvitationService) send(invitation model.Invitation, org model.Organization, inviter, rawToken string, ttl time.Duration) {
+	roleName := invitation.Role
+	switch invitation.Role {
+	case model.RoleOrgMember:
+		roleName = "成员"
+	case model.RoleOrgAdmin:
+		roleName = "管理员"
+	}
+
+	link := strings.ReplaceAll(config.GetString("security.invitation.url"), "{token}", rawToken)
+	err := mailer.Send(mailer.Message{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("邀请您加入组织 %s", org.Code),
+		Body: fmt.Sprintf("您好：\n\n%s 邀请您以%s身份加入组织 %s。请在%d天内打开以下链接接受邀请：\n\n%s\n\n如果您不认识邀请人，请忽略此邮件。",
+			inviter, roleName, org.Code, int(math.Ceil(ttl.Hours()/24)), link),
+	})
+	if err != nil {
+		logger.WithFields(map[string]interface{}{"invitation_id": invitation.ID}).Error("发送邀请邮件失败: " + err.Error())
+	}
+}
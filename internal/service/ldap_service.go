@@ -0,0 +1,465 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/config"
+	"backend/pkg/database"
+	"backend/pkg/logger"
+	"backend/pkg/netguard"
+	"crypto/tls"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrLDAPNotConfigured = errors.New("该组织未配置LDAP认证")
+	ErrLDAPUnavailable   = errors.New("目录服务暂时不可用")
+)
+
+// LDAPConfigSettings LDAP配置，nil 字段保持原值或使用默认值
+type LDAPConfigSettings struct {
+	URL                string
+	InsecureSkipVerify *bool
+	BindDN             *string
+	BindPassword       *string
+	BaseDN             string
+	UserFilter         string
+	UsernameAttribute  string
+	EmailAttribute     string
+	GroupAttribute     string
+	AdminGroups        []string
+	Enabled            *bool
+}
+
+// LDAPAuthenticator 使用组织配置的LDAP目录认证，认证通过的用户自动创建并同步角色
+type LDAPAuthenticator struct {
+	config *model.LDAPConfig
+}
+
+// Authenticate 使用服务账号搜索用户，再以用户DN和密码绑定校验
+func (a *LDAPAuthenticator) Authenticate(org *model.Organization, username, password string) (*model.User, error) {
+	conn, err := dialLDAP(a.config)
+	if err != nil {
+		a.logError("连接LDAP服务器失败", err)
+		return nil, ErrLDAPUnavailable
+	}
+	defer conn.Close()
+
+	entry, err := a.findEntry(conn, username)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		// 已关联目录身份的用户从目录中删除后不再回退到本地密码
+		if a.hasLinkedUser(org.ID, username) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, errUnknownUser
+	}
+	// 同名的本地用户不自动关联目录身份，交由本地密码认证，需要由管理员显式关联
+	if a.hasUnlinkedLocalUser(org.ID, entry, username) {
+		return nil, errUnknownUser
+	}
+
+	// 客户端拒绝空密码，避免被服务器当作未认证绑定而误判为认证成功
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) || ldap.IsErrorWithCode(err, ldap.ErrorEmptyPassword) {
+			return nil, ErrInvalidCredentials
+		}
+		a.logError("LDAP用户绑定失败", err)
+		return nil, ErrLDAPUnavailable
+	}
+
+	return a.provision(org, username, entry)
+}
+
+// findEntry 搜索登录用户对应的目录条目，不存在时返回 nil
+func (a *LDAPAuthenticator) findEntry(conn *ldap.Conn, username string) (*ldap.Entry, error) {
+	var err error
+	if a.config.BindDN != "" {
+		err = conn.Bind(a.config.BindDN, a.config.BindPassword)
+	} else {
+		err = conn.UnauthenticatedBind("")
+	}
+	if err != nil {
+		a.logError("LDAP服务账号绑定失败", err)
+		return nil, ErrLDAPUnavailable
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		a.config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false,
+		strings.ReplaceAll(a.config.UserFilter, "{username}", ldap.EscapeFilter(username)),
+		[]string{a.config.UsernameAttribute, a.config.EmailAttribute, a.config.GroupAttribute},
+		nil,
+	))
+	var entries []*ldap.Entry
+	switch {
+	case err == nil, ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded):
+		// 超出数量限制说明匹配到多个用户，按多个结果处理
+		entries = result.Entries
+	case ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject):
+	default:
+		a.logError("搜索LDAP用户失败", err)
+		return nil, ErrLDAPUnavailable
+	}
+
+	switch len(entries) {
+	case 0:
+		return nil, nil
+	case 1:
+		return entries[0], nil
+	default:
+		logger.WithFields(map[string]interface{}{
+			"organization_id": a.config.OrganizationID,
+			"username":        username,
+		}).Warn("LDAP过滤器匹配到多个用户，拒绝登录")
+		return nil, ErrInvalidCredentials
+	}
+}
+
+// provision 查找或创建目录用户对应的本地用户，并按所属组同步角色
+func (a *LDAPAuthenticator) provision(org *model.Organization, username string, entry *ldap.Entry) (*model.User, error) {
+	role := a.mapRole(entry)
+
+	var user model.User
+	var identity model.UserIdentity
+	err := database.DB.Where("organization_id = ? AND issuer = ? AND subject = ?", org.ID, a.config.URL, entry.DN).First(&identity).Error
+	switch {
+	case err == nil:
+		if err := database.DB.First(&user, identity.UserID).Error; err != nil {
+			return nil, errors.New("关联的用户不存在")
+		}
+		if user.OrganizationID != org.ID {
+			return nil, errors.New("关联的用户不存在")
+		}
+
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		username = a.directoryUsername(entry, username)
+		if len(username) > 32 {
+			return nil, errors.New("目录中的用户名过长")
+		}
+
+		user = model.User{
+			Username:       username,
+			Password:       "", // 目录用户没有本地密码
+			Email:          entry.GetEqualFoldAttributeValue(a.config.EmailAttribute),
+			Role:           role,
+			OrganizationID: org.ID,
+		}
+		// 目录中的邮箱由目录管理员维护，视为已验证
+		if user.Email != "" {
+			now := time.Now()
+			user.EmailVerifiedAt = &now
+		}
+		identity = model.UserIdentity{OrganizationID: org.ID, Issuer: a.config.URL, Subject: entry.DN}
+		err = database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+			identity.UserID = user.ID
+			return tx.Create(&identity).Error
+		})
+		if err != nil {
+			return nil, errors.New("用户名或邮箱已被占用，请联系组织管理员")
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"user_id":         user.ID,
+			"organization_id": org.ID,
+			"dn":              entry.DN,
+		}).Info("已创建LDAP目录用户")
+
+	default:
+		return nil, errors.New("查询外部身份失败")
+	}
+
+	database.DB.Model(&identity).Update("last_login_at", time.Now())
+
+	// 每次登录按目录中的组同步角色，只在内置的组织管理员和组织成员之间切换，不覆盖自定义角色
+	if user.Role != role && (user.Role == model.RoleOrgAdmin || user.Role == model.RoleOrgMember) {
+		if err := database.DB.Model(&user).Update("role", role).Error; err != nil {
+			return nil, errors.New("同步用户角色失败")
+		}
+	}
+
+	if err := database.DB.Preload("Organization").First(&user, user.ID).Error; err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	return &user, nil
+}
+
+// mapRole 属于管理员组的用户映射为组织管理员，其余为组织成员
+func (a *LDAPAuthenticator) mapRole(entry *ldap.Entry) string {
+	for _, group := range entry.GetEqualFoldAttributeValues(a.config.GroupAttribute) {
+		for _, adminGroup := range a.config.AdminGroups {
+			if strings.EqualFold(strings.TrimSpace(group), strings.TrimSpace(adminGroup)) {
+				return model.RoleOrgAdmin
+			}
+		}
+	}
+	return model.RoleOrgMember
+}
+
+// directoryUsername 目录中的用户名，未返回用户名属性时使用登录名
+func (a *LDAPAuthenticator) directoryUsername(entry *ldap.Entry, username string) string {
+	if directoryName := entry.GetEqualFoldAttributeValue(a.config.UsernameAttribute); directoryName != "" {
+		return directoryName
+	}
+	return username
+}
+
+// hasUnlinkedLocalUser 检查目录条目尚未关联用户，而组织中已有同名的本地用户
+func (a *LDAPAuthenticator) hasUnlinkedLocalUser(organizationID uint, entry *ldap.Entry, username string) bool {
+	var count int64
+	database.DB.Model(&model.UserIdentity{}).
+		Where("organization_id = ? AND issuer = ? AND subject = ?", organizationID, a.config.URL, entry.DN).
+		Count(&count)
+	if count > 0 {
+		return false
+	}
+
+	database.DB.Model(&model.User{}).
+		Where("organization_id = ? AND is_service_account = ?", organizationID, false).
+		Where("username IN ?", []string{username, a.directoryUsername(entry, username)}).
+		Count(&count)
+	return count > 0
+}
+
+// hasLinkedUser 检查用户名是否对应已关联本目录的用户
+func (a *LDAPAuthenticator) hasLinkedUser(organizationID uint, username string) bool {
+	var count int64
+	database.DB.Model(&model.UserIdentity{}).
+		Joins("JOIN users ON users.id = user_identities.user_id AND users.deleted_at IS NULL").
+		Where("user_identities.organization_id = ? AND user_identities.issuer = ? AND users.username = ?",
+			organizationID, a.config.URL, username).
+		Count(&count)
+	return count > 0
+}
+
+func (a *LDAPAuthenticator) logError(msg string, err error) {
+	logger.WithFields(map[string]interface{}{
+		"organization_id": a.config.OrganizationID,
+		"url":             a.config.URL,
+	}).Warn(msg + ": " + err.Error())
+}
+
+type LDAPService struct{}
+
+// GetConfig 获取组织的LDAP配置
+func (s *LDAPService) GetConfig(actor *model.User, organizationID uint) (*model.LDAPConfig, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+
+	var ldapConfig model.LDAPConfig
+	if err := database.DB.Where("organization_id = ?", organizationID).First(&ldapConfig).Error; err != nil {
+		return nil, ErrLDAPNotConfigured
+	}
+	return &ldapConfig, nil
+}
+
+// SaveConfig 创建或更新组织的LDAP配置，保存前校验服务账号能否绑定
+func (s *LDAPService) SaveConfig(actor *model.User, organizationID uint, settings LDAPConfigSettings) (*model.LDAPConfig, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+
+	var org model.Organization
+	if err := database.DB.First(&org, organizationID).Error; err != nil {
+		return nil, errors.New("组织不存在")
+	}
+
+	var ldapConfig model.LDAPConfig
+	err := database.DB.Where("organization_id = ?", organizationID).First(&ldapConfig).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("获取LDAP配置失败")
+		}
+		ldapConfig = model.LDAPConfig{
+			OrganizationID:    organizationID,
+			UserFilter:        "(&(objectClass=person)(uid={username}))",
+			UsernameAttribute: "uid",
+			EmailAttribute:    "mail",
+			GroupAttribute:    "memberOf",
+			Enabled:           true,
+		}
+	}
+
+	ldapConfig.URL = settings.URL
+	ldapConfig.BaseDN = settings.BaseDN
+	ldapConfig.AdminGroups = settings.AdminGroups
+	if settings.InsecureSkipVerify != nil {
+		ldapConfig.InsecureSkipVerify = *settings.InsecureSkipVerify
+	}
+	if settings.BindDN != nil {
+		ldapConfig.BindDN = *settings.BindDN
+	}
+	if settings.BindPassword != nil {
+		ldapConfig.BindPassword = *settings.BindPassword
+	}
+	if settings.UserFilter != "" {
+		ldapConfig.UserFilter = settings.UserFilter
+	}
+	if settings.UsernameAttribute != "" {
+		ldapConfig.UsernameAttribute = settings.UsernameAttribute
+	}
+	if settings.EmailAttribute != "" {
+		ldapConfig.EmailAttribute = settings.EmailAttribute
+	}
+	if settings.GroupAttribute != "" {
+		ldapConfig.GroupAttribute = settings.GroupAttribute
+	}
+	if settings.Enabled != nil {
+		ldapConfig.Enabled = *settings.Enabled
+	}
+
+	// 管理员组中的目录用户登录时成为组织管理员，配置者需要能够分配该角色
+	if len(ldapConfig.AdminGroups) > 0 {
+		if err := (&RoleService{}).Assignable(actor, organizationID, model.RoleOrgAdmin); err != nil {
+			return nil, err
+		}
+	}
+	if !strings.Contains(ldapConfig.UserFilter, "{username}") {
+		return nil, errors.New("用户过滤器必须包含 {username} 占位符")
+	}
+	if err := s.testConnection(&ldapConfig); err != nil {
+		return nil, err
+	}
+
+	if err := database.DB.Save(&ldapConfig).Error; err != nil {
+		return nil, errors.New("保存LDAP配置失败")
+	}
+	return &ldapConfig, nil
+}
+
+// DeleteConfig 删除组织的LDAP配置，已关联的目录身份保留
+func (s *LDAPService) DeleteConfig(actor *model.User, organizationID uint) error {
+	ldapConfig, err := s.GetConfig(actor, organizationID)
+	if err != nil {
+		return err
+	}
+	if err := database.DB.Unscoped().Delete(ldapConfig).Error; err != nil {
+		return errors.New("删除LDAP配置失败")
+	}
+	return nil
+}
+
+// LinkUser 将组织中已有的本地用户关联到目录账号，之后该用户通过目录认证登录
+// directoryUsername 为空时使用用户的用户名搜索目录
+func (s *LDAPService) LinkUser(actor *model.User, organizationID, userID uint, directoryUsername string) (*model.UserIdentity, error) {
+	ldapConfig, err := s.GetConfig(actor, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := findManagedUser(actor, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.OrganizationID != organizationID || user.IsServiceAccount {
+		return nil, ErrUserNotFound
+	}
+	if directoryUsername == "" {
+		directoryUsername = user.Username
+	}
+
+	var count int64
+	database.DB.Model(&model.UserIdentity{}).
+		Where("user_id = ? AND organization_id = ? AND issuer = ?", user.ID, organizationID, ldapConfig.URL).
+		Count(&count)
+	if count > 0 {
+		return nil, errors.New("该用户已关联目录账号")
+	}
+
+	authenticator := &LDAPAuthenticator{config: ldapConfig}
+	conn, err := dialLDAP(ldapConfig)
+	if err != nil {
+		authenticator.logError("连接LDAP服务器失败", err)
+		return nil, ErrLDAPUnavailable
+	}
+	defer conn.Close()
+
+	entry, err := authenticator.findEntry(conn, directoryUsername)
+	if errors.Is(err, ErrInvalidCredentials) {
+		return nil, errors.New("目录中匹配到多个用户")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, errors.New("目录中不存在该用户")
+	}
+
+	// 关联后每次登录按目录中的组同步角色，不能借此授予操作者自己无法分配的角色
+	if role := authenticator.mapRole(entry); role != user.Role && user.Role == model.RoleOrgMember {
+		if err := (&RoleService{}).Assignable(actor, organizationID, role); err != nil {
+			return nil, err
+		}
+	}
+
+	identity := model.UserIdentity{
+		UserID:         user.ID,
+		OrganizationID: organizationID,
+		Issuer:         ldapConfig.URL,
+		Subject:        entry.DN,
+	}
+	if err := database.DB.Create(&identity).Error; err != nil {
+		return nil, errors.New("该目录账号已关联其他用户")
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"actor_id":        actor.ID,
+		"user_id":         user.ID,
+		"organization_id": organizationID,
+		"dn":              entry.DN,
+	}).Info("已关联LDAP目录用户")
+	return &identity, nil
+}
+
+// testConnection 连接服务器并以服务账号绑定
+func (s *LDAPService) testConnection(ldapConfig *model.LDAPConfig) error {
+	conn, err := dialLDAP(ldapConfig)
+	if err != nil {
+		if errors.Is(err, netguard.ErrForbiddenAddress) {
+			return errors.New("LDAP服务器不能使用内网地址")
+		}
+		return err
+	}
+	defer conn.Close()
+
+	if ldapConfig.BindDN != "" {
+		err = conn.Bind(ldapConfig.BindDN, ldapConfig.BindPassword)
+	} else {
+		err = conn.UnauthenticatedBind("")
+	}
+	if err != nil {
+		return errors.New("LDAP服务账号绑定失败: " + err.Error())
+	}
+	return nil
+}
+
+// dialLDAP 按配置连接LDAP服务器
+// 只允许 ldap:// 和 ldaps://，ldapi:// 等本地套接字或UDP协议不经过内网地址校验
+func dialLDAP(ldapConfig *model.LDAPConfig) (*ldap.Conn, error) {
+	timeout := config.GetDuration("ldap.timeout")
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	u, err := url.Parse(ldapConfig.URL)
+	if err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") {
+		return nil, errors.New("LDAP服务器地址必须以 ldap:// 或 ldaps:// 开头")
+	}
+	conn, err := ldap.DialURL(ldapConfig.URL,
+		ldap.DialWithDialer(netguard.Dialer(timeout)),
+		ldap.DialWithTLSConfig(&tls.Config{InsecureSkipVerify: ldapConfig.InsecureSkipVerify}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(timeout)
+	return conn, nil
+}
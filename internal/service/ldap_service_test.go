@@ -0,0 +1,319 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/database"
+	"backend/pkg/hasher"
+	"backend/pkg/netguard"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+const (
+	testLDAPBindDN     = "cn=reader,dc=example,dc=com"
+	testLDAPBindSecret = "reader-secret"
+	testLDAPAdminGroup = "cn=admins,ou=groups,dc=example,dc=com"
+)
+
+// directoryUser 模拟目录中的用户
+type directoryUser struct {
+	DN       string
+	Password string
+	Mail     string
+	Groups   []string
+}
+
+// fakeLDAPServer 只实现简单绑定和按 uid 搜索的模拟目录服务
+type fakeLDAPServer struct {
+	listener net.Listener
+	users    map[string]directoryUser
+}
+
+func newFakeLDAPServer(t *testing.T, users map[string]directoryUser) *fakeLDAPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeLDAPServer{listener: listener, users: users}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.handle(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeLDAPServer) URL() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *fakeLDAPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		id, _ := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			code := ldap.LDAPResultInvalidCredentials
+			if s.checkPassword(op.Children[1].Data.String(), op.Children[2].Data.String()) {
+				code = ldap.LDAPResultSuccess
+			}
+			conn.Write(ldapMessage(id, ldapResult(ldap.ApplicationBindResponse, code)))
+
+		case ldap.ApplicationSearchRequest:
+			filter, _ := ldap.DecompileFilter(op.Children[6])
+			for uid, user := range s.users {
+				if strings.Contains(filter, "(uid="+ldap.EscapeFilter(uid)+")") {
+					conn.Write(ldapMessage(id, ldapEntry(uid, user)))
+				}
+			}
+			conn.Write(ldapMessage(id, ldapResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess)))
+
+		default:
+			// 解绑等其他请求直接断开连接
+			return
+		}
+	}
+}
+
+func (s *fakeLDAPServer) checkPassword(dn, password string) bool {
+	if dn == testLDAPBindDN {
+		return password == testLDAPBindSecret
+	}
+	for _, user := range s.users {
+		if user.DN == dn {
+			return password == user.Password
+		}
+	}
+	return false
+}
+
+func ldapMessage(id int64, op *ber.Packet) []byte {
+	packet := ber.NewSequence("LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "Message ID"))
+	packet.AppendChild(op)
+	return packet.Bytes()
+}
+
+func ldapResult(tag ber.Tag, code int) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return op
+}
+
+func ldapEntry(uid string, user directoryUser) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, user.DN, "DN"))
+
+	attributes := ber.NewSequence("Attributes")
+	for name, values := range map[string][]string{"uid": {uid}, "mail": {user.Mail}, "memberOf": user.Groups} {
+		attribute := ber.NewSequence("Attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, value := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		}
+		attribute.AppendChild(set)
+		attributes.AppendChild(attribute)
+	}
+	op.AppendChild(attributes)
+	return op
+}
+
+// setupLDAP 启动模拟目录服务并为组织启用LDAP认证
+// 目录中 alice 属于管理员组，bob 为普通成员
+func setupLDAP(t *testing.T, orgCode string) *model.LDAPConfig {
+	t.Helper()
+	if err := netguard.SetAllowedNetworks([]string{"127.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { netguard.SetAllowedNetworks(nil) })
+
+	var org model.Organization
+	if err := database.DB.Where(model.Organization{Code: orgCode}).FirstOrCreate(&org).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	server := newFakeLDAPServer(t, map[string]directoryUser{
+		"alice": {
+			DN:       "uid=alice,ou=people,dc=example,dc=com",
+			Password: "directory-password",
+			Mail:     "alice@example.com",
+			Groups:   []string{testLDAPAdminGroup},
+		},
+		"bob": {
+			DN:       "uid=bob,ou=people,dc=example,dc=com",
+			Password: "directory-password",
+			Mail:     "bob@example.com",
+		},
+	})
+	ldapConfig := &model.LDAPConfig{
+		OrganizationID:    org.ID,
+		URL:               server.URL(),
+		BindDN:            testLDAPBindDN,
+		BindPassword:      testLDAPBindSecret,
+		BaseDN:            "ou=people,dc=example,dc=com",
+		UserFilter:        "(&(objectClass=person)(uid={username}))",
+		UsernameAttribute: "uid",
+		EmailAttribute:    "mail",
+		GroupAttribute:    "memberOf",
+		AdminGroups:       model.LineList{testLDAPAdminGroup},
+		Enabled:           true,
+	}
+	if err := database.DB.Create(ldapConfig).Error; err != nil {
+		t.Fatal(err)
+	}
+	return ldapConfig
+}
+
+func ldapLogin(orgCode, username, password string) (*model.User, error) {
+	result, err := (&AuthService{}).Login(username, password, orgCode, ClientInfo{IP: "192.0.2.1"})
+	if err != nil {
+		return nil, err
+	}
+	return result.User, nil
+}
+
+func TestLDAPFirstLoginProvisionsUser(t *testing.T) {
+	setupTestDB(t)
+	ldapConfig := setupLDAP(t, "acme")
+
+	user, err := ldapLogin("acme", "alice", "directory-password")
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if user.Username != "alice" || user.Role != model.RoleOrgAdmin || user.Password != "" ||
+		user.Email != "alice@example.com" || user.EmailVerifiedAt == nil {
+		t.Errorf("unexpected user: %+v", user)
+	}
+
+	var identity model.UserIdentity
+	if err := database.DB.Where("user_id = ?", user.ID).First(&identity).Error; err != nil {
+		t.Fatal(err)
+	}
+	if identity.Issuer != ldapConfig.URL || identity.Subject != "uid=alice,ou=people,dc=example,dc=com" {
+		t.Errorf("unexpected identity: %+v", identity)
+	}
+
+	again, err := ldapLogin("acme", "alice", "directory-password")
+	if err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+	if again.ID != user.ID {
+		t.Errorf("second login user = %d, want %d", again.ID, user.ID)
+	}
+
+	if _, err := ldapLogin("acme", "alice", "wrong-password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("wrong password: err = %v, want ErrInvalidCredentials", err)
+	}
+	if _, err := ldapLogin("acme", "alice", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("empty password: err = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+// 同名的本地用户不会被目录登录自动关联，也不会被覆盖角色
+func TestLDAPDoesNotLinkLocalUser(t *testing.T) {
+	setupTestDB(t)
+	local := createTestUser(t, "acme", "alice")
+	hashed, err := hasher.Hash("local-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.DB.Model(local).Update("password", hashed)
+	setupLDAP(t, "acme")
+
+	if _, err := ldapLogin("acme", "alice", "directory-password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("directory password: err = %v, want ErrInvalidCredentials", err)
+	}
+	user, err := ldapLogin("acme", "alice", "local-password")
+	if err != nil {
+		t.Fatalf("local password: %v", err)
+	}
+	if user.ID != local.ID || user.Role != model.RoleOrgMember {
+		t.Errorf("logged in as user %d with role %s, want local user %d as org_member", user.ID, user.Role, local.ID)
+	}
+
+	var count int64
+	database.DB.Model(&model.UserIdentity{}).Count(&count)
+	if count != 0 {
+		t.Errorf("%d identities linked", count)
+	}
+}
+
+func TestLDAPLinkUser(t *testing.T) {
+	setupTestDB(t)
+	local := createTestUser(t, "acme", "robert")
+	ldapConfig := setupLDAP(t, "acme")
+	admin := &model.User{Role: model.RoleOrgAdmin, OrganizationID: ldapConfig.OrganizationID}
+	service := &LDAPService{}
+
+	if _, err := service.LinkUser(admin, ldapConfig.OrganizationID, local.ID, "nobody"); err == nil {
+		t.Error("linked a user missing from the directory")
+	}
+	identity, err := service.LinkUser(admin, ldapConfig.OrganizationID, local.ID, "bob")
+	if err != nil {
+		t.Fatalf("LinkUser: %v", err)
+	}
+	if identity.UserID != local.ID || identity.Subject != "uid=bob,ou=people,dc=example,dc=com" {
+		t.Errorf("unexpected identity: %+v", identity)
+	}
+	if _, err := service.LinkUser(admin, ldapConfig.OrganizationID, local.ID, "bob"); err == nil {
+		t.Error("linked the same user twice")
+	}
+
+	user, err := ldapLogin("acme", "bob", "directory-password")
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if user.ID != local.ID {
+		t.Errorf("logged in as user %d, want linked user %d", user.ID, local.ID)
+	}
+
+	// 其他组织的管理员不能关联本组织的用户
+	other := createTestUser(t, "globex", "mallory")
+	other.Role = model.RoleOrgAdmin
+	if _, err := service.LinkUser(other, ldapConfig.OrganizationID, local.ID, "bob"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("cross-organization link: err = %v, want ErrForbidden", err)
+	}
+}
+
+// 只能分配组织成员角色的操作者不能借关联管理员组中的目录账号提升用户角色
+func TestLDAPLinkUserChecksSyncedRole(t *testing.T) {
+	setupTestDB(t)
+	local := createTestUser(t, "acme", "alice")
+	ldapConfig := setupLDAP(t, "acme")
+
+	permissions, _ := model.BuiltinRolePermissions(model.RoleOrgMember)
+	role := model.Role{OrganizationID: ldapConfig.OrganizationID, Name: "user-manager",
+		Permissions: append(model.StringList{model.PermissionUserRead, model.PermissionUserUpdate}, permissions...)}
+	if err := database.DB.Create(&role).Error; err != nil {
+		t.Fatal(err)
+	}
+	manager := &model.User{Role: role.Name, OrganizationID: ldapConfig.OrganizationID}
+
+	if _, err := (&LDAPService{}).LinkUser(manager, ldapConfig.OrganizationID, local.ID, ""); err == nil {
+		t.Fatal("linked a member to a directory administrator")
+	}
+	if _, err := (&LDAPService{}).LinkUser(manager, ldapConfig.OrganizationID, local.ID, "bob"); err != nil {
+		t.Errorf("link to a directory member: %v", err)
+	}
+}
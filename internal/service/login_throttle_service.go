@@ -0,0 +1,235 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/config"
+	"backend/pkg/database"
+	"backend/pkg/logger"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginThrottledError 登录尝试被限制
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("登录失败次数过多，请在%d分钟后重试", int(math.Ceil(e.RetryAfter.Minutes())))
+	}
+	return "登录尝试过于频繁，请稍后重试"
+}
+
+// loginThrottlePolicy 登录限制策略
+type loginThrottlePolicy struct {
+	window          time.Duration // 失败计数窗口，超过窗口的失败不再累计
+	userMaxFailures int           // 单个用户连续失败多少次后锁定
+	userLockout     time.Duration
+	ipMaxFailures   int // 单个IP连续失败多少次后锁定
+	ipLockout       time.Duration
+	delayAfter      int           // 单个用户失败多少次后开始渐进延迟
+	delayBase       time.Duration // 第一次延迟时长，之后每次翻倍
+	delayMax        time.Duration
+}
+
+// loadLoginThrottlePolicy 读取 security.login 配置，未配置的项使用默认值
+func loadLoginThrottlePolicy() loginThrottlePolicy {
+	policy := loginThrottlePolicy{
+		window:          15 * time.Minute,
+		userMaxFailures: 5,
+		userLockout:     15 * time.Minute,
+		ipMaxFailures:   50,
+		ipLockout:       15 * time.Minute,
+		delayAfter:      2,
+		delayBase:       time.Second,
+		delayMax:        30 * time.Second,
+	}
+	if v := config.GetDuration("security.login.failure_window"); v > 0 {
+		policy.window = v
+	}
+	if v := config.GetInt("security.login.user_max_failures"); v > 0 {
+		policy.userMaxFailures = v
+	}
+	if v := config.GetDuration("security.login.user_lockout"); v > 0 {
+		policy.userLockout = v
+	}
+	if v := config.GetInt("security.login.ip_max_failures"); v > 0 {
+		policy.ipMaxFailures = v
+	}
+	if v := config.GetDuration("security.login.ip_lockout"); v > 0 {
+		policy.ipLockout = v
+	}
+	if v := config.GetInt("security.login.delay_after"); v > 0 {
+		policy.delayAfter = v
+	}
+	if v := config.GetDuration("security.login.delay_base"); v > 0 {
+		policy.delayBase = v
+	}
+	if v := config.GetDuration("security.login.delay_max"); v > 0 {
+		policy.delayMax = v
+	}
+	return policy
+}
+
+// LoginThrottleService 登录暴力破解防护：按用户和IP统计失败次数，渐进延迟并临时锁定
+type LoginThrottleService struct{}
+
+// UserTarget 组织内用户名的统计对象
+func (s *LoginThrottleService) UserTarget(organizationID uint, username string) string {
+	return fmt.Sprintf("%d:%s", organizationID, username)
+}
+
+// Check 检查是否允许本次登录尝试
+func (s *LoginThrottleService) Check(userTarget, ip string) error {
+	now := time.Now()
+
+	var throttles []model.LoginThrottle
+	database.DB.Where("(kind = ? AND target = ?) OR (kind = ? AND target = ?)",
+		model.LoginThrottleUser, userTarget, model.LoginThrottleIP, ip).Find(&throttles)
+
+	var blocked *LoginThrottledError
+	for _, throttle := range throttles {
+		var err *LoginThrottledError
+		switch {
+		case throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil):
+			err = &LoginThrottledError{RetryAfter: throttle.LockedUntil.Sub(now), Locked: true}
+		case throttle.NextAttemptAt != nil && now.Before(*throttle.NextAttemptAt):
+			err = &LoginThrottledError{RetryAfter: throttle.NextAttemptAt.Sub(now)}
+		default:
+			continue
+		}
+		if blocked == nil || err.RetryAfter > blocked.RetryAfter {
+			blocked = err
+		}
+	}
+	if blocked != nil {
+		return blocked
+	}
+	return nil
+}
+
+// RecordFailure 记录一次失败的登录，达到阈值时锁定并写入安全日志
+func (s *LoginThrottleService) RecordFailure(userTarget, ip string) {
+	policy := loadLoginThrottlePolicy()
+
+	s.recordFailure(model.LoginThrottleUser, userTarget, ip, policy.userMaxFailures, policy.userLockout, policy)
+	if ip != "" {
+		s.recordFailure(model.LoginThrottleIP, ip, ip, policy.ipMaxFailures, policy.ipLockout, policy)
+	}
+
+	// 顺带清理窗口外且未锁定的记录
+	now := time.Now()
+	database.DB.Unscoped().
+		Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until < ?)", now.Add(-policy.window), now).
+		Delete(&model.LoginThrottle{})
+}
+
+// RecordSuccess 登录成功后清除该用户的失败计数（IP计数不清除，避免用已知账号重置）
+func (s *LoginThrottleService) RecordSuccess(userTarget string) {
+	database.DB.Unscoped().Where("kind = ? AND target = ?", model.LoginThrottleUser, userTarget).
+		Delete(&model.LoginThrottle{})
+}
+
+// Unlock 解除用户的登录锁定，ip 不为空时同时解除该IP的锁定
+func (s *LoginThrottleService) Unlock(actor *model.User, userID uint, ip string) error {
+	user, err := findManagedUser(actor, userID)
+	if err != nil {
+		return err
+	}
+
+	query := database.DB.Unscoped().Where("kind = ? AND target = ?", model.LoginThrottleUser, s.UserTarget(user.OrganizationID, user.Username))
+	if ip != "" {
+		query = query.Or("kind = ? AND target = ?", model.LoginThrottleIP, ip)
+	}
+	if err := query.Delete(&model.LoginThrottle{}).Error; err != nil {
+		return errors.New("解除锁定失败")
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"event":    "login_unlock",
+		"user_id":  user.ID,
+		"ip":       ip,
+		"actor_id": actor.ID,
+	}).Info("管理员解除了登录锁定")
+	return nil
+}
+
+// recordFailure 累加单个维度的失败次数，并计算渐进延迟和锁定
+// 失败次数使用原子的自增更新，并发的失败登录不会互相覆盖计数
+func (s *LoginThrottleService) recordFailure(kind, target, ip string, maxFailures int, lockout time.Duration, policy loginThrottlePolicy) {
+	now := time.Now()
+
+	var throttle model.LoginThrottle
+	locked := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.LoginThrottle{Kind: kind, Target: target, LastFailureAt: now}).Error; err != nil {
+			return err
+		}
+
+		// 超出统计窗口或上一次锁定已过期时重新计数
+		if err := tx.Model(&model.LoginThrottle{}).
+			Where("kind = ? AND target = ?", kind, target).
+			Where("last_failure_at < ? OR locked_until < ?", now.Add(-policy.window), now).
+			Updates(map[string]interface{}{"failures": 0, "locked_until": nil, "next_attempt_at": nil}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.LoginThrottle{}).
+			Where("kind = ? AND target = ?", kind, target).
+			Updates(map[string]interface{}{"failures": gorm.Expr("failures + 1"), "last_failure_at": now}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("kind = ? AND target = ?", kind, target).First(&throttle).Error; err != nil {
+			return err
+		}
+
+		if kind == model.LoginThrottleUser && throttle.Failures >= policy.delayAfter {
+			delay := policy.delayBase << uint(throttle.Failures-policy.delayAfter)
+			if delay <= 0 || delay > policy.delayMax {
+				delay = policy.delayMax
+			}
+			if err := tx.Model(&throttle).Update("next_attempt_at", now.Add(delay)).Error; err != nil {
+				return err
+			}
+		}
+
+		if throttle.Failures >= maxFailures && throttle.LockedUntil == nil {
+			// 并发的请求中只有一个设置锁定时间并写入安全日志
+			lockedUntil := now.Add(lockout)
+			result := tx.Model(&model.LoginThrottle{}).
+				Where("id = ? AND locked_until IS NULL", throttle.ID).
+				Update("locked_until", lockedUntil)
+			if result.Error != nil {
+				return result.Error
+			}
+			throttle.LockedUntil = &lockedUntil
+			locked = result.RowsAffected == 1
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"kind":   kind,
+			"target": target,
+		}).Error("记录登录失败次数失败: " + err.Error())
+		return
+	}
+
+	if locked {
+		logger.WithFields(map[string]interface{}{
+			"event":        "login_lockout",
+			"kind":         kind,
+			"target":       target,
+			"ip":           ip,
+			"failures":     throttle.Failures,
+			"locked_until": throttle.LockedUntil.Format(time.RFC3339),
+		}).Warn("登录失败次数过多，已临时锁定")
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/config"
+	"backend/pkg/database"
+	"backend/pkg/hasher"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func findThrottle(t *testing.T, kind, target string) *model.LoginThrottle {
+	t.Helper()
+	var throttle model.LoginThrottle
+	if err := database.DB.Where("kind = ? AND target = ?", kind, target).First(&throttle).Error; err != nil {
+		return nil
+	}
+	return &throttle
+}
+
+func TestLoginThrottleProgressiveDelayAndLockout(t *testing.T) {
+	setupTestDB(t)
+	service := &LoginThrottleService{}
+	target := service.UserTarget(1, "alice")
+	const ip = "192.0.2.1"
+
+	service.RecordFailure(target, ip)
+	if err := service.Check(target, ip); err != nil {
+		t.Fatalf("after one failure: %v", err)
+	}
+
+	service.RecordFailure(target, ip)
+	var throttled *LoginThrottledError
+	if err := service.Check(target, ip); !errors.As(err, &throttled) || throttled.Locked {
+		t.Fatalf("after two failures: err = %v, want a progressive delay", err)
+	}
+	if throttled.RetryAfter <= 0 || throttled.RetryAfter > time.Second {
+		t.Errorf("first delay = %v, want at most 1s", throttled.RetryAfter)
+	}
+
+	for i := 0; i < 3; i++ {
+		service.RecordFailure(target, ip)
+	}
+	if err := service.Check(target, ip); !errors.As(err, &throttled) || !throttled.Locked {
+		t.Fatalf("after five failures: err = %v, want locked", err)
+	}
+	if throttled.RetryAfter <= 14*time.Minute || throttled.RetryAfter > 15*time.Minute {
+		t.Errorf("lockout = %v, want 15m", throttled.RetryAfter)
+	}
+
+	// 其他用户不受影响，IP计数未达到阈值
+	if err := service.Check(service.UserTarget(1, "bob"), ip); err != nil {
+		t.Errorf("other user: %v", err)
+	}
+	if throttle := findThrottle(t, model.LoginThrottleIP, ip); throttle == nil || throttle.Failures != 5 || throttle.LockedUntil != nil {
+		t.Errorf("unexpected ip throttle: %+v", throttle)
+	}
+
+	service.RecordSuccess(target)
+	if err := service.Check(target, ip); err != nil {
+		t.Errorf("after success: %v", err)
+	}
+	if throttle := findThrottle(t, model.LoginThrottleIP, ip); throttle == nil {
+		t.Error("success cleared the ip failures")
+	}
+}
+
+func TestLoginThrottleResetsAfterWindow(t *testing.T) {
+	setupTestDB(t)
+	service := &LoginThrottleService{}
+	target := service.UserTarget(1, "alice")
+
+	for i := 0; i < 5; i++ {
+		service.RecordFailure(target, "")
+	}
+	// 锁定过期后重新计数
+	past := time.Now().Add(-time.Minute)
+	database.DB.Model(&model.LoginThrottle{}).Where("target = ?", target).Update("locked_until", past)
+	service.RecordFailure(target, "")
+	if throttle := findThrottle(t, model.LoginThrottleUser, target); throttle.Failures != 1 || throttle.LockedUntil != nil {
+		t.Errorf("after expired lockout: %+v", throttle)
+	}
+
+	// 超出统计窗口的失败不再累计
+	database.DB.Model(&model.LoginThrottle{}).Where("target = ?", target).Update("last_failure_at", time.Now().Add(-time.Hour))
+	service.RecordFailure(target, "")
+	if throttle := findThrottle(t, model.LoginThrottleUser, target); throttle.Failures != 1 {
+		t.Errorf("after window: %d failures, want 1", throttle.Failures)
+	}
+}
+
+// 并发的失败登录全部计入，锁定日志只写一次
+func TestLoginThrottleConcurrentFailures(t *testing.T) {
+	setupTestDB(t)
+	config.Config.Set("security.login.user_max_failures", 10)
+	t.Cleanup(func() { config.Config.Set("security.login.user_max_failures", 0) })
+	service := &LoginThrottleService{}
+	target := service.UserTarget(1, "alice")
+
+	const attempts = 20
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			service.RecordFailure(target, "192.0.2.1")
+		}()
+	}
+	wg.Wait()
+
+	user := findThrottle(t, model.LoginThrottleUser, target)
+	if user == nil || user.Failures != attempts || user.LockedUntil == nil {
+		t.Errorf("user throttle = %+v, want %d failures and locked", user, attempts)
+	}
+	if ip := findThrottle(t, model.LoginThrottleIP, "192.0.2.1"); ip == nil || ip.Failures != attempts {
+		t.Errorf("ip throttle = %+v, want %d failures", ip, attempts)
+	}
+}
+
+// 锁定期间即使密码正确也不能登录，管理员解除锁定后恢复
+func TestLoginRejectedWhileLocked(t *testing.T) {
+	setupTestDB(t)
+	user := createTestUser(t, "acme", "alice")
+	hashed, err := hasher.Hash("correct-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.DB.Model(user).Update("password", hashed)
+
+	authService := &AuthService{}
+	client := ClientInfo{IP: "192.0.2.1"}
+	for i := 0; i < 5; i++ {
+		authService.throttleService.RecordFailure(authService.throttleService.UserTarget(user.OrganizationID, "alice"), client.IP)
+	}
+
+	var throttled *LoginThrottledError
+	if _, err := authService.Login("alice", "correct-password", "acme", client); !errors.As(err, &throttled) || !throttled.Locked {
+		t.Fatalf("locked login: err = %v, want locked", err)
+	}
+
+	admin := &model.User{Role: model.RoleSuperAdmin}
+	if err := (&LoginThrottleService{}).Unlock(admin, user.ID, client.IP); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if _, err := authService.Login("alice", "correct-password", "acme", client); err != nil {
+		t.Errorf("login after unlock: %v", err)
+	}
+}
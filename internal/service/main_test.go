@@ -0,0 +1,75 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/config"
+	"backend/pkg/database"
+	"backend/pkg/jwt"
+	"backend/pkg/logger"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestMain(m *testing.M) {
+	config.Config = viper.New()
+	if err := logger.Init("error", "console", "stdout"); err != nil {
+		panic(err)
+	}
+	key, err := jwt.GenerateEphemeralKey()
+	if err != nil {
+		panic(err)
+	}
+	if err := jwt.SetKeys([]*jwt.Key{key}, key.ID); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// setupTestDB 为每个测试创建独立的SQLite数据库并迁移表结构
+func setupTestDB(t *testing.T) {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() {
+		database.DB = previous
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if err := database.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// createTestUser 创建组织和该组织中的正常用户
+func createTestUser(t *testing.T, orgCode, username string) *model.User {
+	t.Helper()
+	org := model.Organization{Code: orgCode}
+	if err := database.DB.Create(&org).Error; err != nil {
+		t.Fatal(err)
+	}
+	user := model.User{
+		Username:       username,
+		Email:          username + "@example.com",
+		Role:           model.RoleOrgMember,
+		Status:         model.UserStatusActive,
+		OrganizationID: org.ID,
+	}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	user.Organization = org
+	return &user
+}
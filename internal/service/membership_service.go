@@ -0,0 +1,230 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/database"
+	"backend/pkg/logger"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotMember          = errors.New("不是该组织的成员")
+	ErrMembershipNotFound = errors.New("成员不存在")
+)
+
+// OrganizationMembership 用户可以进入的组织
+type OrganizationMembership struct {
+	OrganizationID uint   `json:"organization_id" example:"2"`
+	Code           string `json:"code" example:"company_a"`
+	Description    string `json:"description"`
+	Role           string `json:"role" example:"org_member"` // 在该组织中的角色
+	Home           bool   `json:"home"`                      // 是否为用户的所在组织
+	Current        bool   `json:"current"`                   // 是否为当前令牌所在的组织
+}
+
+// MemberInfo 从其他组织加入本组织的成员
+type MemberInfo struct {
+	UserID           uint      `json:"user_id"`
+	Username         string    `json:"username"`
+	Email            string    `json:"email"`
+	Role             string    `json:"role" example:"org_member"`             // 在本组织中的角色
+	HomeOrganization string    `json:"home_organization" example:"company_b"` // 用户的所在组织
+	JoinedAt         time.Time `json:"joined_at"`
+}
+
+// MembershipService 多组织成员关系和组织切换
+type MembershipService struct {
+	tokenService TokenService
+	roleService  RoleService
+}
+
+// Organizations 获取用户可以进入的组织：所在组织和加入的组织
+// current 为当前令牌所在的组织
+func (s *MembershipService) Organizations(userID, current uint) ([]OrganizationMembership, error) {
+	var user model.User
+	if err := database.DB.Preload("Organization").First(&user, userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	var memberships []model.Membership
+	if err := database.DB.Preload("Organization").
+		Joins("JOIN organizations ON organizations.id = memberships.organization_id AND organizations.deleted_at IS NULL").
+		Where("memberships.user_id = ?", userID).Order("memberships.id").Find(&memberships).Error; err != nil {
+		return nil, errors.New("获取组织列表失败")
+	}
+
+	organizations := []OrganizationMembership{{
+		OrganizationID: user.OrganizationID,
+		Code:           user.Organization.Code,
+		Description:    user.Organization.Description,
+		Role:           user.Role,
+		Home:           true,
+		Current:        current == user.OrganizationID,
+	}}
+	for _, membership := range memberships {
+		organizations = append(organizations, OrganizationMembership{
+			OrganizationID: membership.OrganizationID,
+			Code:           membership.Organization.Code,
+			Description:    membership.Organization.Description,
+			Role:           membership.Role,
+			Current:        current == membership.OrganizationID,
+		})
+	}
+	return organizations, nil
+}
+
+// Apply 将用户切换到指定组织，用于处理切换组织后签发的令牌
+func (s *MembershipService) Apply(user *model.User, organizationID uint) error {
+	return applyMembership(user, organizationID)
+}
+
+// Switch 为用户签发进入指定组织的令牌对，开始一个新的登录会话
+// 切换回所在组织时使用用户本身的角色
+func (s *MembershipService) Switch(userID, organizationID uint, client ClientInfo) (*model.User, *TokenPair, error) {
+	var user model.User
+	if err := database.DB.Preload("Organization").First(&user, userID).Error; err != nil {
+		return nil, nil, ErrUserNotFound
+	}
+	if err := CheckUserStatus(&user); err != nil {
+		return nil, nil, err
+	}
+	if err := applyMembership(&user, organizationID); err != nil {
+		return nil, nil, err
+	}
+
+	pair, err := s.tokenService.IssueTokenPair(&user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"event":           "organization_switched",
+		"user_id":         user.ID,
+		"organization_id": user.OrganizationID,
+		"role":            user.Role,
+		"ip":              client.IP,
+	}).Info("用户切换了组织")
+	return &user, pair, nil
+}
+
+// List 获取从其他组织加入本组织的成员
+func (s *MembershipService) List(actor *model.User, organizationID uint) ([]MemberInfo, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+
+	var memberships []model.Membership
+	if err := database.DB.Preload("User").Preload("User.Organization").
+		Where("organization_id = ?", organizationID).Order("id").Find(&memberships).Error; err != nil {
+		return nil, errors.New("获取成员列表失败")
+	}
+
+	members := make([]MemberInfo, 0, len(memberships))
+	for _, membership := range memberships {
+		members = append(members, MemberInfo{
+			UserID:           membership.UserID,
+			Username:         membership.User.Username,
+			Email:            membership.User.Email,
+			Role:             membership.Role,
+			HomeOrganization: membership.User.Organization.Code,
+			JoinedAt:         membership.CreatedAt,
+		})
+	}
+	return members, nil
+}
+
+// UpdateRole 修改成员在本组织中的角色，修改后该成员进入本组织的令牌在下一个请求时即按新角色授权
+func (s *MembershipService) UpdateRole(actor *model.User, organizationID, userID uint, role string) (*model.Membership, error) {
+	membership, err := s.find(actor, organizationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.roleService.Assignable(actor, organizationID, role); err != nil {
+		return nil, err
+	}
+
+	if err := database.DB.Model(membership).Update("role", role).Error; err != nil {
+		return nil, errors.New("修改成员角色失败")
+	}
+	s.log("membership_updated", actor, membership)
+	return membership, nil
+}
+
+// Remove 将成员移出本组织及其中的团队，该成员进入本组织的令牌和会话随即失效
+func (s *MembershipService) Remove(actor *model.User, organizationID, userID uint) error {
+	membership, err := s.find(actor, organizationID, userID)
+	if err != nil {
+		return err
+	}
+
+	// 物理删除，以便之后重新加入
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().
+			Where("user_id = ? AND team_id IN (?)", userID, tx.Model(&model.Team{}).Select("id").Where("organization_id = ?", organizationID)).
+			Delete(&model.TeamMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(membership).Error
+	})
+	if err != nil {
+		return errors.New("移除成员失败")
+	}
+	s.log("membership_removed", actor, membership)
+	return nil
+}
+
+// find 查找操作者可以管理的成员，成员在本组织的权限不能超出操作者
+func (s *MembershipService) find(actor *model.User, organizationID, userID uint) (*model.Membership, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+
+	var membership model.Membership
+	if err := database.DB.Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		First(&membership).Error; err != nil {
+		return nil, ErrMembershipNotFound
+	}
+	if actor.ID == membership.UserID {
+		return nil, errors.New("不能修改自己的成员关系")
+	}
+	if actor.Role != model.RoleSuperAdmin {
+		permissions, _ := s.roleService.rolePermissions(organizationID, membership.Role)
+		if s.roleService.checkGrantable(actor, permissions) != nil {
+			return nil, ErrForbidden
+		}
+	}
+	return &membership, nil
+}
+
+func (s *MembershipService) log(event string, actor *model.User, membership *model.Membership) {
+	logger.WithFields(map[string]interface{}{
+		"event":           event,
+		"user_id":         membership.UserID,
+		"organization_id": membership.OrganizationID,
+		"role":            membership.Role,
+		"actor_id":        actor.ID,
+	}).Info("已修改组织成员")
+}
+
+// applyMembership 按成员关系将用户切换到指定组织，组织和角色替换为加入的组织及其中的角色
+// organizationID 为 0 或用户的所在组织时不做修改
+func applyMembership(user *model.User, organizationID uint) error {
+	if organizationID == 0 || organizationID == user.OrganizationID {
+		return nil
+	}
+
+	var membership model.Membership
+	if err := database.DB.Preload("Organization").
+		Joins("JOIN organizations ON organizations.id = memberships.organization_id AND organizations.deleted_at IS NULL").
+		Where("memberships.user_id = ? AND memberships.organization_id = ?", user.ID, organizationID).
+		First(&membership).Error; err != nil {
+		return ErrNotMember
+	}
+	user.OrganizationID = membership.OrganizationID
+	user.Organization = membership.Organization
+	user.Role = membership.Role
+	return nil
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/config"
+	"backend/pkg/database"
+	"backend/pkg/hasher"
+	"backend/pkg/token"
+	"backend/pkg/totp"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// recoveryCodeCount 每次生成的恢复码数量
+	recoveryCodeCount = 10
+	// recoveryCodeBytes 每个恢复码的随机字节数（80位）
+	recoveryCodeBytes = 10
+	// maxMFAChallengeAttempts 单个挑战允许的最大尝试次数
+	maxMFAChallengeAttempts = 5
+)
+
+var (
+	ErrInvalidMFAChallenge = errors.New("双因素认证已过期，请重新登录")
+	ErrInvalidMFACode      = errors.New("验证码错误")
+)
+
+// MFAEnrollment 双因素认证注册信息
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type MFAService struct {
+	throttleService LoginThrottleService
+}
+
+// IsEnabled 检查用户是否已启用双因素认证
+func (s *MFAService) IsEnabled(userID uint) bool {
+	var count int64
+	database.DB.Model(&model.UserMFA{}).
+		Where("user_id = ? AND enabled_at IS NOT NULL", userID).Count(&count)
+	return count > 0
+}
+
+// EnrollmentRequired 检查用户所在组织是否要求双因素认证而用户尚未启用
+func (s *MFAService) EnrollmentRequired(user *model.User) bool {
+	if !user.Organization.RequireMFA {
+		return false
+	}
+	return !s.IsEnabled(user.ID)
+}
+
+// Enroll 生成新的TOTP密钥，需通过 Confirm 确认后才会启用
+func (s *MFAService) Enroll(user *model.User) (*MFAEnrollment, error) {
+	if s.IsEnabled(user.ID) {
+		return nil, errors.New("已启用双因素认证")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.New("生成密钥失败")
+	}
+
+	// 覆盖尚未确认的旧密钥
+	if err := database.DB.Unscoped().Where("user_id = ?", user.ID).Delete(&model.UserMFA{}).Error; err != nil {
+		return nil, errors.New("生成密钥失败")
+	}
+	if err := database.DB.Create(&model.UserMFA{UserID: user.ID, Secret: secret}).Error; err != nil {
+		return nil, errors.New("生成密钥失败")
+	}
+
+	issuer := config.GetString("mfa.issuer")
+	if issuer == "" {
+		issuer = "Windz"
+	}
+	account := user.Username
+	if user.Organization.Code != "" {
+		account = user.Username + "@" + user.Organization.Code
+	}
+
+	return &MFAEnrollment{
+		Secret: secret,
+		URI:    totp.URI(issuer, account, secret),
+	}, nil
+}
+
+// Confirm 使用验证码确认注册并启用双因素认证，返回仅展示一次的恢复码
+func (s *MFAService) Confirm(user *model.User, code string) ([]string, error) {
+	var mfa model.UserMFA
+	if err := database.DB.Where("user_id = ?", user.ID).First(&mfa).Error; err != nil {
+		return nil, errors.New("请先生成双因素认证密钥")
+	}
+	if mfa.EnabledAt != nil {
+		return nil, errors.New("已启用双因素认证")
+	}
+
+	step, ok := totp.Validate(mfa.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	var codes []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&mfa).Updates(map[string]interface{}{
+			"enabled_at":     now,
+			"last_used_step": step,
+		}).Error; err != nil {
+			return err
+		}
+
+		var err error
+		codes, err = s.replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, errors.New("启用双因素认证失败")
+	}
+
+	return codes, nil
+}
+
+// Disable 关闭双因素认证，需要提供有效的验证码或恢复码
+func (s *MFAService) Disable(user *model.User, code string) error {
+	if user.Organization.RequireMFA {
+		return errors.New("组织要求启用双因素认证，不能关闭")
+	}
+
+	var mfa model.UserMFA
+	if err := database.DB.Where("user_id = ? AND enabled_at IS NOT NULL", user.ID).First(&mfa).Error; err != nil {
+		return errors.New("未启用双因素认证")
+	}
+	if err := s.verifyCode(&mfa, code); err != nil {
+		return err
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(&model.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&mfa).Error
+	})
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，旧恢复码全部失效
+func (s *MFAService) RegenerateRecoveryCodes(user *model.User, code string) ([]string, error) {
+	var mfa model.UserMFA
+	if err := database.DB.Where("user_id = ? AND enabled_at IS NOT NULL", user.ID).First(&mfa).Error; err != nil {
+		return nil, errors.New("未启用双因素认证")
+	}
+	if err := s.verifyCode(&mfa, code); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		codes, err = s.replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, errors.New("生成恢复码失败")
+	}
+	return codes, nil
+}
+
+// CreateChallenge 为密码验证通过的用户创建双因素认证挑战
+// throttleTarget 为登录时的失败计数对象，验证码错误计入同一计数
+func (s *MFAService) CreateChallenge(userID uint, throttleTarget string) (string, time.Duration, error) {
+	ttl := config.GetDuration("mfa.challenge_ttl")
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+
+	rawToken, err := token.Generate(32)
+	if err != nil {
+		return "", 0, errors.New("生成双因素认证挑战失败")
+	}
+
+	challenge := model.MFAChallenge{
+		UserID:         userID,
+		TokenHash:      token.Hash(rawToken),
+		ExpiresAt:      time.Now().Add(ttl),
+		ThrottleTarget: throttleTarget,
+	}
+	if err := database.DB.Create(&challenge).Error; err != nil {
+		return "", 0, errors.New("生成双因素认证挑战失败")
+	}
+
+	return rawToken, ttl, nil
+}
+
+// VerifyChallenge 校验挑战令牌和验证码（或恢复码），成功后挑战失效并清除登录失败计数
+// 验证码错误与密码错误计入同一失败计数，跨挑战累计达到阈值时锁定登录
+func (s *MFAService) VerifyChallenge(rawToken, code, ip string) (*model.User, error) {
+	var challenge model.MFAChallenge
+	if err := database.DB.Where("token_hash = ?", token.Hash(rawToken)).First(&challenge).Error; err != nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+	if challenge.UsedAt != nil || time.Now().After(challenge.ExpiresAt) ||
+		challenge.Attempts >= maxMFAChallengeAttempts {
+		return nil, ErrInvalidMFAChallenge
+	}
+	if err := s.throttleService.Check(challenge.ThrottleTarget, ip); err != nil {
+		return nil, err
+	}
+
+	var mfa model.UserMFA
+	if err := database.DB.Where("user_id = ? AND enabled_at IS NOT NULL", challenge.UserID).First(&mfa).Error; err != nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	if err := s.verifyCode(&mfa, code); err != nil {
+		database.DB.Model(&challenge).Update("attempts", gorm.Expr("attempts + 1"))
+		s.throttleService.RecordFailure(challenge.ThrottleTarget, ip)
+		return nil, err
+	}
+
+	// 条件更新保证挑战只能使用一次
+	result := database.DB.Model(&model.MFAChallenge{}).
+		Where("id = ? AND used_at IS NULL", challenge.ID).
+		Update("used_at", time.Now())
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, ErrInvalidMFAChallenge
+	}
+	s.throttleService.RecordSuccess(challenge.ThrottleTarget)
+
+	var user model.User
+	if err := database.DB.Preload("Organization").First(&user, challenge.UserID).Error; err != nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+	return &user, nil
+}
+
+// verifyCode 校验TOTP验证码或恢复码
+func (s *MFAService) verifyCode(mfa *model.UserMFA, code string) error {
+	code = strings.TrimSpace(code)
+
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(mfa.Secret, code, time.Now())
+		if !ok || step <= mfa.LastUsedStep {
+			return ErrInvalidMFACode
+		}
+		// 条件更新防止同一验证码被并发重放
+		result := database.DB.Model(&model.UserMFA{}).
+			Where("id = ? AND last_used_step < ?", mfa.ID, step).
+			Update("last_used_step", step)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	// 恢复码使用加盐的慢哈希存储，只能逐个比对用户未使用的恢复码
+	var recoveryCodes []model.MFARecoveryCode
+	if err := database.DB.Where("user_id = ? AND used_at IS NULL", mfa.UserID).Find(&recoveryCodes).Error; err != nil {
+		return ErrInvalidMFACode
+	}
+	code = normalizeRecoveryCode(code)
+	for _, recoveryCode := range recoveryCodes {
+		if !hasher.Matches(code, recoveryCode.CodeHash) {
+			continue
+		}
+		// 条件更新保证恢复码只能使用一次
+		result := database.DB.Model(&model.MFARecoveryCode{}).
+			Where("id = ? AND used_at IS NULL", recoveryCode.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil || result.RowsAffected == 0 {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+	return ErrInvalidMFACode
+}
+
+// replaceRecoveryCodes 删除旧恢复码并生成新的恢复码
+func (s *MFAService) replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&model.MFARecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]model.MFARecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		raw := hex.EncodeToString(buf)
+		hash, err := hasher.Hash(raw)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, formatRecoveryCode(raw))
+		records = append(records, model.MFARecoveryCode{UserID: userID, CodeHash: hash})
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// formatRecoveryCode 每5个字符插入一个分隔符，便于抄写
+func formatRecoveryCode(raw string) string {
+	groups := make([]string, 0, (len(raw)+4)/5)
+	for len(raw) > 5 {
+		groups = append(groups, raw[:5])
+		raw = raw[5:]
+	}
+	return strings.Join(append(groups, raw), "-")
+}
+
+// normalizeRecoveryCode 统一恢复码格式（忽略大小写和分隔符）
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/config"
+	"backend/pkg/database"
+	"backend/pkg/jwt"
+	"backend/pkg/logger"
+	"backend/pkg/token"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OAuth2 错误码（RFC 6749 第5.2节）
+const (
+	OAuthErrInvalidRequest       = "invalid_request"
+	OAuthErrInvalidClient        = "invalid_client"
+	OAuthErrUnsupportedGrantType = "unsupported_grant_type"
+	OAuthErrInvalidScope         = "invalid_scope"
+	OAuthErrServerError          = "server_error"
+)
+
+// OAuthError OAuth2 令牌端点错误
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Description
+}
+
+// OAuthToken 客户端凭证模式签发的访问令牌
+type OAuthToken struct {
+	AccessToken string
+	ExpiresIn   int64
+	Scope       string
+}
+
+type OAuthClientService struct {
+	revocationService RevocationService
+	roleService       RoleService
+}
+
+// Create 在组织内注册OAuth2客户端，返回的客户端密钥只展示一次
+// organizationID 为空时为操作者所在的组织，非超级管理员只能指定自己所在的组织或其下级组织
+// role 为服务账号的角色，为空时为组织成员；不能超出操作者自己拥有的权限
+func (s *OAuthClientService) Create(actor *model.User, organizationID uint, name string, scopes []string, role string) (*model.OAuthClient, string, error) {
+	if actor.Role != model.RoleSuperAdmin {
+		if organizationID == 0 {
+			organizationID = actor.OrganizationID
+		}
+		if !canAdministerOrganization(actor, organizationID) {
+			return nil, "", ErrForbidden
+		}
+	}
+	for _, scope := range scopes {
+		if !model.IsValidScope(scope) {
+			return nil, "", fmt.Errorf("无效的访问范围: %s", scope)
+		}
+	}
+	if role == "" {
+		role = model.RoleOrgMember
+	}
+
+	var org model.Organization
+	if err := database.DB.First(&org, organizationID).Error; err != nil {
+		return nil, "", errors.New("组织不存在")
+	}
+	if err := s.roleService.Assignable(actor, org.ID, role); err != nil {
+		return nil, "", err
+	}
+
+	idBytes := make([]byte, 8)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, "", errors.New("生成客户端ID失败")
+	}
+	clientID := "wzc_" + hex.EncodeToString(idBytes)
+
+	secret, err := token.Generate(32)
+	if err != nil {
+		return nil, "", errors.New("生成客户端密钥失败")
+	}
+
+	client := model.OAuthClient{
+		OrganizationID: org.ID,
+		Name:           name,
+		ClientID:       clientID,
+		SecretHash:     token.Hash(secret),
+		Scopes:         scopes,
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// 服务账号没有可用的密码，只能通过客户端凭证获取令牌
+		serviceAccount := model.User{
+			Username:         "svc-" + clientID,
+			Password:         "",
+			Role:             role,
+			OrganizationID:   org.ID,
+			IsServiceAccount: true,
+		}
+		if err := tx.Create(&serviceAccount).Error; err != nil {
+			return err
+		}
+
+		client.UserID = serviceAccount.ID
+		client.ServiceAccount = &serviceAccount
+		return tx.Omit("ServiceAccount").Create(&client).Error
+	})
+	if err != nil {
+		return nil, "", errors.New("创建OAuth2客户端失败")
+	}
+
+	return &client, secret, nil
+}
+
+// List 获取客户端列表，非超级管理员只能看到自己所在的组织及其下级组织的客户端
+func (s *OAuthClientService) List(actor *model.User) ([]model.OAuthClient, error) {
+	query := database.DB.Preload("ServiceAccount").Order("id DESC")
+	if actor.Role != model.RoleSuperAdmin {
+		descendants, err := organizationDescendants(actor.OrganizationID)
+		if err != nil {
+			return nil, errors.New("获取OAuth2客户端列表失败")
+		}
+		organizationIDs := []uint{actor.OrganizationID}
+		for _, org := range descendants {
+			organizationIDs = append(organizationIDs, org.ID)
+		}
+		query = query.Where("organization_id IN ?", organizationIDs)
+	}
+
+	var clients []model.OAuthClient
+	if err := query.Find(&clients).Error; err != nil {
+		return nil, errors.New("获取OAuth2客户端列表失败")
+	}
+	return clients, nil
+}
+
+// RotateSecret 轮换客户端密钥，旧密钥在宽限期内仍然有效
+func (s *OAuthClientService) RotateSecret(actor *model.User, id uint) (*model.OAuthClient, string, error) {
+	client, err := s.get(actor, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if client.RevokedAt != nil {
+		return nil, "", errors.New("客户端已撤销")
+	}
+
+	secret, err := token.Generate(32)
+	if err != nil {
+		return nil, "", errors.New("生成客户端密钥失败")
+	}
+
+	grace := config.GetDuration("oauth.secret_rotation_grace")
+	if grace < 0 {
+		grace = 0
+	}
+	now := time.Now()
+	previousExpiresAt := now.Add(grace)
+
+	client.PreviousSecretHash = client.SecretHash
+	client.PreviousSecretExpiresAt = &previousExpiresAt
+	client.SecretHash = token.Hash(secret)
+	client.SecretRotatedAt = &now
+	if err := database.DB.Model(client).Select(
+		"secret_hash", "previous_secret_hash", "previous_secret_expires_at", "secret_rotated_at",
+	).Updates(client).Error; err != nil {
+		return nil, "", errors.New("轮换客户端密钥失败")
+	}
+
+	return client, secret, nil
+}
+
+// UpdateRole 修改客户端服务账号的角色，对客户端已签发的令牌立即生效
+// 与管理用户相同，只能修改权限不超过自己的客户端，也只能分配不超过自己权限的角色
+func (s *OAuthClientService) UpdateRole(actor *model.User, id uint, role string) (*model.OAuthClient, error) {
+	client, err := s.get(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if client.RevokedAt != nil {
+		return nil, errors.New("客户端已撤销")
+	}
+
+	var serviceAccount model.User
+	if err := database.DB.First(&serviceAccount, client.UserID).Error; err != nil {
+		return nil, errors.New("客户端的服务账号不存在")
+	}
+	if actor.Role != model.RoleSuperAdmin {
+		permissions, _ := s.roleService.rolePermissions(client.OrganizationID, serviceAccount.Role)
+		if s.roleService.checkGrantable(actor, permissions) != nil {
+			return nil, ErrForbidden
+		}
+	}
+	if err := s.roleService.Assignable(actor, client.OrganizationID, role); err != nil {
+		return nil, err
+	}
+
+	if err := database.DB.Model(&serviceAccount).Update("role", role).Error; err != nil {
+		return nil, errors.New("修改客户端角色失败")
+	}
+	client.ServiceAccount = &serviceAccount
+
+	logger.WithFields(map[string]interface{}{
+		"event":     "oauth_client_role_changed",
+		"client_id": client.ClientID,
+		"role":      role,
+		"actor_id":  actor.ID,
+	}).Info("已修改OAuth2客户端的角色")
+	return client, nil
+}
+
+// Revoke 撤销客户端，并使其已签发的令牌立即失效
+func (s *OAuthClientService) Revoke(actor *model.User, id uint) error {
+	client, err := s.get(actor, id)
+	if err != nil {
+		return err
+	}
+	if client.RevokedAt != nil {
+		return nil
+	}
+
+	if err := database.DB.Model(client).Update("revoked_at", time.Now()).Error; err != nil {
+		return errors.New("撤销客户端失败")
+	}
+	return s.revocationService.RevokeUserTokens(client.UserID, "oauth_client_revoked")
+}
+
+// IsActive 检查客户端是否存在且未被撤销
+func (s *OAuthClientService) IsActive(clientID string) bool {
+	var count int64
+	database.DB.Model(&model.OAuthClient{}).
+		Where("client_id = ? AND revoked_at IS NULL", clientID).Count(&count)
+	return count > 0
+}
+
+// IssueToken 客户端凭证模式：校验客户端身份并签发访问令牌
+// requestedScope 为空时授予客户端的全部访问范围
+func (s *OAuthClientService) IssueToken(clientID, clientSecret, requestedScope string) (*OAuthToken, error) {
+	var client model.OAuthClient
+	if err := database.DB.Where("client_id = ?", clientID).First(&client).Error; err != nil {
+		return nil, &OAuthError{Code: OAuthErrInvalidClient, Description: "客户端认证失败"}
+	}
+	if client.RevokedAt != nil || !s.verifySecret(&client, clientSecret) {
+		return nil, &OAuthError{Code: OAuthErrInvalidClient, Description: "客户端认证失败"}
+	}
+
+	scopes := []string(client.Scopes)
+	if requestedScope != "" {
+		scopes = strings.Fields(requestedScope)
+		for _, scope := range scopes {
+			if !client.Scopes.Contains(scope) {
+				return nil, &OAuthError{Code: OAuthErrInvalidScope, Description: "客户端无权申请访问范围: " + scope}
+			}
+		}
+	}
+
+	var user model.User
+	if err := database.DB.First(&user, client.UserID).Error; err != nil {
+		return nil, &OAuthError{Code: OAuthErrInvalidClient, Description: "客户端认证失败"}
+	}
+
+	scope := strings.Join(scopes, " ")
+	accessToken, err := jwt.Sign(&jwt.CustomClaims{
+		UserID:         user.ID,
+		Username:       user.Username,
+		Role:           user.Role,
+		OrganizationID: user.OrganizationID,
+		ClientID:       client.ClientID,
+		Scope:          scope,
+	})
+	if err != nil {
+		return nil, &OAuthError{Code: OAuthErrServerError, Description: "生成token失败"}
+	}
+
+	return &OAuthToken{
+		AccessToken: accessToken,
+		ExpiresIn:   int64(jwt.TokenExpireDuration.Seconds()),
+		Scope:       scope,
+	}, nil
+}
+
+// get 获取客户端，组织管理员只能操作本组织及下级组织的客户端
+func (s *OAuthClientService) get(actor *model.User, id uint) (*model.OAuthClient, error) {
+	var client model.OAuthClient
+	if err := database.DB.First(&client, id).Error; err != nil {
+		return nil, errors.New("客户端不存在")
+	}
+	if !canAdministerOrganization(actor, client.OrganizationID) {
+		return nil, ErrForbidden
+	}
+	return &client, nil
+}
+
+// verifySecret 校验客户端密钥，轮换宽限期内旧密钥同样有效
+func (s *OAuthClientService) verifySecret(client *model.OAuthClient, secret string) bool {
+	hash := []byte(token.Hash(secret))
+	if subtle.ConstantTimeCompare(hash, []byte(client.SecretHash)) == 1 {
+		return true
+	}
+	return client.PreviousSecretHash != "" &&
+		client.PreviousSecretExpiresAt != nil &&
+		time.Now().Before(*client.PreviousSecretExpiresAt) &&
+		subtle.ConstantTimeCompare(hash, []byte(client.PreviousSecretHash)) == 1
+}
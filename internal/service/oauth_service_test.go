@@ -0,0 +1,56 @@
+package service
+
+import (
+	"backend/internal/model"
+	"errors"
+	"testing"
+)
+
+// 组织管理员可以为本组织及下级组织注册客户端，并在列表中看到它们
+func TestOAuthClientOrganizationScope(t *testing.T) {
+	setupTestDB(t)
+	reseller, customer, _, globex := createOrganizationTree(t)
+	admin := addTestUser(t, reseller.ID, "reseller-admin", model.RoleOrgAdmin)
+	root := &model.User{Role: model.RoleSuperAdmin}
+	service := &OAuthClientService{}
+	scopes := []string{model.ScopeOrgsRead}
+
+	own, _, err := service.Create(admin, 0, "billing", scopes, "")
+	if err != nil {
+		t.Fatalf("create in own organization: %v", err)
+	}
+	if own.OrganizationID != reseller.ID {
+		t.Errorf("client organization = %d, want %d", own.OrganizationID, reseller.ID)
+	}
+	child, _, err := service.Create(admin, customer.ID, "sync", scopes, "")
+	if err != nil {
+		t.Fatalf("create in child organization: %v", err)
+	}
+	if child.OrganizationID != customer.ID {
+		t.Errorf("client organization = %d, want %d", child.OrganizationID, customer.ID)
+	}
+	if _, _, err := service.Create(admin, globex.ID, "intruder", scopes, ""); !errors.Is(err, ErrForbidden) {
+		t.Errorf("create in unrelated organization: err = %v, want ErrForbidden", err)
+	}
+	other, _, err := service.Create(root, globex.ID, "globex", scopes, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clients, err := service.List(admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed := map[uint]bool{}
+	for _, client := range clients {
+		listed[client.ID] = true
+	}
+	if len(clients) != 2 || !listed[own.ID] || !listed[child.ID] || listed[other.ID] {
+		t.Errorf("listed clients %v, want %d and %d", listed, own.ID, child.ID)
+	}
+
+	customerAdmin := addTestUser(t, customer.ID, "customer-admin", model.RoleOrgAdmin)
+	if clients, err := service.List(customerAdmin); err != nil || len(clients) != 1 || clients[0].ID != child.ID {
+		t.Errorf("customer admin listed %d clients (err %v), want only %d", len(clients), err, child.ID)
+	}
+}
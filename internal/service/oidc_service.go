@@ -0,0 +1,355 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/config"
+	"backend/pkg/database"
+	"backend/pkg/logger"
+	"backend/pkg/netguard"
+	"backend/pkg/oidc"
+	"backend/pkg/token"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOIDCNotConfigured = errors.New("该组织未启用OIDC登录")
+	ErrInvalidOIDCState  = errors.New("登录请求已过期，请重新登录")
+)
+
+// OIDCProviderSettings 身份提供方配置，nil 字段保持原值或使用默认值
+type OIDCProviderSettings struct {
+	Issuer          string
+	ClientID        string
+	ClientSecret    *string
+	Scopes          []string
+	UsernameClaim   string
+	EmailClaim      string
+	RoleClaim       *string
+	AdminRoleValues []string
+	AutoProvision   *bool
+	Enabled         *bool
+}
+
+type OIDCService struct{}
+
+// GetProvider 获取组织的身份提供方配置
+func (s *OIDCService) GetProvider(actor *model.User, organizationID uint) (*model.OIDCProvider, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+
+	var provider model.OIDCProvider
+	if err := database.DB.Where("organization_id = ?", organizationID).First(&provider).Error; err != nil {
+		return nil, ErrOIDCNotConfigured
+	}
+	return &provider, nil
+}
+
+// SaveProvider 创建或更新组织的身份提供方配置，保存前校验发现文档是否可用
+func (s *OIDCService) SaveProvider(actor *model.User, organizationID uint, settings OIDCProviderSettings) (*model.OIDCProvider, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+
+	var org model.Organization
+	if err := database.DB.First(&org, organizationID).Error; err != nil {
+		return nil, errors.New("组织不存在")
+	}
+
+	var provider model.OIDCProvider
+	err := database.DB.Where("organization_id = ?", organizationID).First(&provider).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("获取OIDC配置失败")
+		}
+		provider = model.OIDCProvider{
+			OrganizationID: organizationID,
+			UsernameClaim:  "preferred_username",
+			EmailClaim:     "email",
+			AutoProvision:  true,
+			Enabled:        true,
+		}
+	}
+
+	provider.Issuer = strings.TrimSuffix(settings.Issuer, "/")
+	provider.ClientID = settings.ClientID
+	provider.Scopes = settings.Scopes
+	provider.AdminRoleValues = settings.AdminRoleValues
+	if settings.ClientSecret != nil {
+		provider.ClientSecret = *settings.ClientSecret
+	}
+	if settings.UsernameClaim != "" {
+		provider.UsernameClaim = settings.UsernameClaim
+	}
+	if settings.EmailClaim != "" {
+		provider.EmailClaim = settings.EmailClaim
+	}
+	if settings.RoleClaim != nil {
+		provider.RoleClaim = *settings.RoleClaim
+	}
+	if settings.AutoProvision != nil {
+		provider.AutoProvision = *settings.AutoProvision
+	}
+	if settings.Enabled != nil {
+		provider.Enabled = *settings.Enabled
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := oidc.Discover(ctx, provider.Issuer); err != nil {
+		if errors.Is(err, netguard.ErrForbiddenAddress) {
+			return nil, errors.New("issuer 不能使用内网地址")
+		}
+		return nil, err
+	}
+
+	if err := database.DB.Save(&provider).Error; err != nil {
+		return nil, errors.New("保存OIDC配置失败")
+	}
+	return &provider, nil
+}
+
+// DeleteProvider 删除组织的身份提供方配置，已关联的外部身份保留
+func (s *OIDCService) DeleteProvider(actor *model.User, organizationID uint) error {
+	provider, err := s.GetProvider(actor, organizationID)
+	if err != nil {
+		return err
+	}
+	if err := database.DB.Unscoped().Delete(provider).Error; err != nil {
+		return errors.New("删除OIDC配置失败")
+	}
+	return nil
+}
+
+// AuthorizationURL 为组织发起授权码请求，返回身份提供方的授权地址
+func (s *OIDCService) AuthorizationURL(ctx context.Context, organizationCode string) (string, error) {
+	provider, err := s.enabledProvider(organizationCode)
+	if err != nil {
+		return "", err
+	}
+
+	metadata, err := oidc.Discover(ctx, provider.Issuer)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"organization_id": provider.OrganizationID,
+			"issuer":          provider.Issuer,
+		}).Warn("获取OIDC发现文档失败: " + err.Error())
+		return "", errors.New("身份提供方暂时不可用")
+	}
+
+	state, err := token.Generate(32)
+	if err != nil {
+		return "", errors.New("生成登录请求失败")
+	}
+	nonce, err := token.Generate(16)
+	if err != nil {
+		return "", errors.New("生成登录请求失败")
+	}
+	verifier, challenge, err := oidc.GeneratePKCE()
+	if err != nil {
+		return "", errors.New("生成登录请求失败")
+	}
+
+	ttl := config.GetDuration("oidc.state_ttl")
+	if ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+
+	// 顺带清理过期的登录请求
+	database.DB.Unscoped().Where("expires_at < ?", time.Now()).Delete(&model.OIDCLoginState{})
+
+	if err := database.DB.Create(&model.OIDCLoginState{
+		OrganizationID: provider.OrganizationID,
+		StateHash:      token.Hash(state),
+		Nonce:          nonce,
+		CodeVerifier:   verifier,
+		ExpiresAt:      time.Now().Add(ttl),
+	}).Error; err != nil {
+		return "", errors.New("生成登录请求失败")
+	}
+
+	return oidc.AuthCodeURL(metadata, s.clientConfig(provider), state, nonce, challenge), nil
+}
+
+// Authenticate 处理授权回调：换取并校验ID令牌，查找或创建组织内的用户
+func (s *OIDCService) Authenticate(ctx context.Context, code, state string) (*model.User, error) {
+	var loginState model.OIDCLoginState
+	if err := database.DB.Where("state_hash = ?", token.Hash(state)).First(&loginState).Error; err != nil {
+		return nil, ErrInvalidOIDCState
+	}
+	if loginState.UsedAt != nil || time.Now().After(loginState.ExpiresAt) {
+		return nil, ErrInvalidOIDCState
+	}
+
+	// 条件更新保证同一登录请求只能回调一次
+	result := database.DB.Model(&model.OIDCLoginState{}).
+		Where("id = ? AND used_at IS NULL", loginState.ID).
+		Update("used_at", time.Now())
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, ErrInvalidOIDCState
+	}
+
+	var provider model.OIDCProvider
+	if err := database.DB.Where("organization_id = ? AND enabled = ?", loginState.OrganizationID, true).
+		First(&provider).Error; err != nil {
+		return nil, ErrOIDCNotConfigured
+	}
+
+	metadata, err := oidc.Discover(ctx, provider.Issuer)
+	if err != nil {
+		return nil, errors.New("身份提供方暂时不可用")
+	}
+
+	tokenResp, err := oidc.Exchange(ctx, metadata, s.clientConfig(&provider), code, loginState.CodeVerifier)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"organization_id": provider.OrganizationID,
+			"issuer":          provider.Issuer,
+		}).Warn("OIDC授权码换取令牌失败: " + err.Error())
+		return nil, errors.New("身份提供方认证失败")
+	}
+
+	claims, err := oidc.VerifyIDToken(ctx, metadata, provider.ClientID, tokenResp.IDToken, loginState.Nonce)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"organization_id": provider.OrganizationID,
+			"issuer":          provider.Issuer,
+		}).Warn(err.Error())
+		return nil, errors.New("身份提供方认证失败")
+	}
+
+	return s.findOrProvision(&provider, claims)
+}
+
+// findOrProvision 根据外部身份查找用户，未关联时按已验证邮箱关联或自动创建用户
+func (s *OIDCService) findOrProvision(provider *model.OIDCProvider, claims oidc.Claims) (*model.User, error) {
+	subject := claims.String("sub")
+	email := claims.String(provider.EmailClaim)
+	emailVerified := email != "" && claims.Bool("email_verified")
+
+	var user model.User
+	var identity model.UserIdentity
+	err := database.DB.Where("organization_id = ? AND issuer = ? AND subject = ?",
+		provider.OrganizationID, provider.Issuer, subject).First(&identity).Error
+	switch {
+	case err == nil:
+		if err := database.DB.First(&user, identity.UserID).Error; err != nil {
+			return nil, errors.New("关联的用户不存在")
+		}
+		if user.OrganizationID != provider.OrganizationID {
+			return nil, errors.New("关联的用户不存在")
+		}
+
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// 仅当身份提供方确认邮箱已验证时，才关联同一组织内的已有用户
+		linked := false
+		if emailVerified {
+			if err := database.DB.Where("organization_id = ? AND email = ? AND is_service_account = ?",
+				provider.OrganizationID, email, false).First(&user).Error; err == nil {
+				linked = true
+			}
+		}
+		if !linked {
+			if !provider.AutoProvision {
+				return nil, errors.New("该用户尚未开通，请联系组织管理员")
+			}
+
+			username := claims.String(provider.UsernameClaim)
+			if username == "" || len(username) > 32 {
+				return nil, errors.New("身份提供方未返回有效的用户名")
+			}
+			user = model.User{
+				Username:       username,
+				Password:       "", // 联合登录用户没有本地密码
+				Role:           s.mapRole(provider, claims, model.RoleOrgMember),
+				OrganizationID: provider.OrganizationID,
+			}
+			if emailVerified {
+				now := time.Now()
+				user.Email = email
+				user.EmailVerifiedAt = &now
+			}
+		}
+
+		identity = model.UserIdentity{OrganizationID: provider.OrganizationID, Issuer: provider.Issuer, Subject: subject}
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if user.ID == 0 {
+				if err := tx.Create(&user).Error; err != nil {
+					return err
+				}
+			}
+			identity.UserID = user.ID
+			return tx.Create(&identity).Error
+		})
+		if err != nil {
+			return nil, errors.New("用户名或邮箱已被占用，请联系组织管理员")
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"user_id":         user.ID,
+			"organization_id": provider.OrganizationID,
+			"issuer":          provider.Issuer,
+			"provisioned":     !linked,
+		}).Info("已关联OIDC外部身份")
+
+	default:
+		return nil, errors.New("查询外部身份失败")
+	}
+
+	now := time.Now()
+	database.DB.Model(&identity).Update("last_login_at", now)
+
+	// 每次登录按身份提供方的声明同步角色，只在内置的组织管理员和组织成员之间切换，超级管理员和自定义角色不受影响
+	if role := s.mapRole(provider, claims, user.Role); role != user.Role && (user.Role == model.RoleOrgAdmin || user.Role == model.RoleOrgMember) {
+		if err := database.DB.Model(&user).Update("role", role).Error; err != nil {
+			return nil, errors.New("同步用户角色失败")
+		}
+	}
+
+	if err := database.DB.Preload("Organization").First(&user, user.ID).Error; err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	return &user, nil
+}
+
+// mapRole 根据角色声明映射组织内角色，未配置角色声明时返回 current
+func (s *OIDCService) mapRole(provider *model.OIDCProvider, claims oidc.Claims, current string) string {
+	if provider.RoleClaim == "" {
+		return current
+	}
+	for _, value := range claims.Strings(provider.RoleClaim) {
+		if provider.AdminRoleValues.Contains(value) {
+			return model.RoleOrgAdmin
+		}
+	}
+	return model.RoleOrgMember
+}
+
+// enabledProvider 根据组织代码获取已启用的身份提供方配置
+func (s *OIDCService) enabledProvider(organizationCode string) (*model.OIDCProvider, error) {
+	var org model.Organization
+	if err := database.DB.Where("code = ?", organizationCode).First(&org).Error; err != nil {
+		return nil, errors.New("组织不存在")
+	}
+
+	var provider model.OIDCProvider
+	if err := database.DB.Where("organization_id = ? AND enabled = ?", org.ID, true).First(&provider).Error; err != nil {
+		return nil, ErrOIDCNotConfigured
+	}
+	return &provider, nil
+}
+
+// clientConfig 构建OIDC客户端配置
+func (s *OIDCService) clientConfig(provider *model.OIDCProvider) oidc.Config {
+	return oidc.Config{
+		ClientID:     provider.ClientID,
+		ClientSecret: provider.ClientSecret,
+		RedirectURL:  config.GetString("oidc.redirect_url"),
+		Scopes:       provider.Scopes,
+	}
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/config"
+	"backend/pkg/database"
+	"backend/pkg/netguard"
+	"backend/pkg/oidc/oidctest"
+	"backend/pkg/token"
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// setupOIDC 启动模拟身份提供方并为组织启用OIDC登录
+func setupOIDC(t *testing.T, orgCode string) (*oidctest.Server, *model.OIDCProvider) {
+	t.Helper()
+	if err := netguard.SetAllowedNetworks([]string{"127.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { netguard.SetAllowedNetworks(nil) })
+	config.Config.Set("oidc.redirect_url", "https://app.example.com/oidc/callback")
+
+	var org model.Organization
+	if err := database.DB.Where(model.Organization{Code: orgCode}).FirstOrCreate(&org).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	server := oidctest.NewServer(t, "windz")
+	provider := &model.OIDCProvider{
+		OrganizationID: org.ID,
+		Issuer:         server.Issuer(),
+		ClientID:       server.ClientID,
+		UsernameClaim:  "preferred_username",
+		EmailClaim:     "email",
+		AutoProvision:  true,
+		Enabled:        true,
+	}
+	if err := database.DB.Create(provider).Error; err != nil {
+		t.Fatal(err)
+	}
+	return server, provider
+}
+
+// oidcLogin 发起授权请求并模拟用户在身份提供方登录，返回回调参数
+func oidcLogin(t *testing.T, server *oidctest.Server, orgCode string, claims map[string]interface{}) (code, state string) {
+	t.Helper()
+	authURL, err := (&OIDCService{}).AuthorizationURL(context.Background(), orgCode)
+	if err != nil {
+		t.Fatalf("AuthorizationURL: %v", err)
+	}
+	if u, _ := url.Parse(authURL); u.Query().Get("redirect_uri") != "https://app.example.com/oidc/callback" {
+		t.Errorf("redirect_uri = %q", u.Query().Get("redirect_uri"))
+	}
+	code, state, err = server.Authorize(authURL, claims)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	return code, state
+}
+
+func aliceClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":                "idp-alice",
+		"preferred_username": "alice",
+		"email":              "alice@example.com",
+		"email_verified":     true,
+	}
+}
+
+func countUsers(t *testing.T) int64 {
+	t.Helper()
+	var count int64
+	database.DB.Model(&model.User{}).Count(&count)
+	return count
+}
+
+func TestOIDCFirstLoginProvisionsUser(t *testing.T) {
+	setupTestDB(t)
+	server, provider := setupOIDC(t, "acme")
+	service := &OIDCService{}
+	ctx := context.Background()
+
+	code, state := oidcLogin(t, server, "acme", aliceClaims())
+	user, err := service.Authenticate(ctx, code, state)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if user.Username != "alice" || user.OrganizationID != provider.OrganizationID || user.Role != model.RoleOrgMember {
+		t.Errorf("unexpected user: %+v", user)
+	}
+	if user.Email != "alice@example.com" || user.EmailVerifiedAt == nil || user.Password != "" {
+		t.Errorf("provisioned user email/password not set as expected: %+v", user)
+	}
+
+	var identity model.UserIdentity
+	if err := database.DB.Where("user_id = ?", user.ID).First(&identity).Error; err != nil {
+		t.Fatal(err)
+	}
+	if identity.OrganizationID != provider.OrganizationID || identity.Issuer != server.Issuer() ||
+		identity.Subject != "idp-alice" || identity.LastLoginAt == nil {
+		t.Errorf("unexpected identity: %+v", identity)
+	}
+
+	// 再次登录找到同一用户，不重复创建
+	code, state = oidcLogin(t, server, "acme", aliceClaims())
+	again, err := service.Authenticate(ctx, code, state)
+	if err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+	if again.ID != user.ID {
+		t.Errorf("second login user = %d, want %d", again.ID, user.ID)
+	}
+	if n := countUsers(t); n != 1 {
+		t.Errorf("%d users, want 1", n)
+	}
+}
+
+func TestOIDCLinksVerifiedEmail(t *testing.T) {
+	setupTestDB(t)
+	existing := createTestUser(t, "acme", "alice")
+	server, _ := setupOIDC(t, "acme")
+
+	claims := aliceClaims()
+	claims["preferred_username"] = "alice.idp"
+	code, state := oidcLogin(t, server, "acme", claims)
+	user, err := (&OIDCService{}).Authenticate(context.Background(), code, state)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if user.ID != existing.ID {
+		t.Errorf("linked user = %d, want existing user %d", user.ID, existing.ID)
+	}
+}
+
+// 同一外部身份在不同组织中各自关联到本组织的用户
+func TestOIDCIdentityPerOrganization(t *testing.T) {
+	setupTestDB(t)
+	server, _ := setupOIDC(t, "acme")
+	service := &OIDCService{}
+	ctx := context.Background()
+
+	code, state := oidcLogin(t, server, "acme", aliceClaims())
+	first, err := service.Authenticate(ctx, code, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other := model.Organization{Code: "globex"}
+	if err := database.DB.Create(&other).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := database.DB.Create(&model.OIDCProvider{
+		OrganizationID: other.ID,
+		Issuer:         server.Issuer(),
+		ClientID:       server.ClientID,
+		UsernameClaim:  "preferred_username",
+		EmailClaim:     "email",
+		AutoProvision:  true,
+		Enabled:        true,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	code, state = oidcLogin(t, server, "globex", aliceClaims())
+	second, err := service.Authenticate(ctx, code, state)
+	if err != nil {
+		t.Fatalf("login to the second organization: %v", err)
+	}
+	if second.ID == first.ID || second.OrganizationID != other.ID {
+		t.Errorf("second organization login returned user %d in organization %d", second.ID, second.OrganizationID)
+	}
+}
+
+func TestOIDCRejectsInvalidState(t *testing.T) {
+	setupTestDB(t)
+	server, _ := setupOIDC(t, "acme")
+	service := &OIDCService{}
+	ctx := context.Background()
+
+	code, _ := oidcLogin(t, server, "acme", aliceClaims())
+	if _, err := service.Authenticate(ctx, code, "forged-state"); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Errorf("unknown state: err = %v, want ErrInvalidOIDCState", err)
+	}
+
+	// 登录请求只能回调一次
+	code, state := oidcLogin(t, server, "acme", aliceClaims())
+	if _, err := service.Authenticate(ctx, code, state); err != nil {
+		t.Fatal(err)
+	}
+	code, _ = oidcLogin(t, server, "acme", aliceClaims())
+	if _, err := service.Authenticate(ctx, code, state); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Errorf("reused state: err = %v, want ErrInvalidOIDCState", err)
+	}
+}
+
+// 身份提供方返回异常的令牌时登录失败，且不会创建用户
+func TestOIDCRejectsInvalidIDToken(t *testing.T) {
+	setupTestDB(t)
+	server, _ := setupOIDC(t, "acme")
+	otherKey := oidctest.NewServer(t, "windz").Key
+
+	tests := []struct {
+		name   string
+		before func(state string)
+		mutate func(claims jwt.MapClaims)
+	}{
+		{"wrong PKCE verifier", func(state string) {
+			database.DB.Model(&model.OIDCLoginState{}).Where("state_hash = ?", token.Hash(state)).
+				Update("code_verifier", "tampered-verifier")
+		}, nil},
+		{"nonce mismatch", nil, func(c jwt.MapClaims) { c["nonce"] = "replayed-nonce" }},
+		{"bad signature", func(string) { server.SigningKey = otherKey }, nil},
+		{"wrong audience", nil, func(c jwt.MapClaims) { c["aud"] = "another-client" }},
+		{"wrong issuer", nil, func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.IDTokenHook = tt.mutate
+			defer func() {
+				server.IDTokenHook = nil
+				server.SigningKey = nil
+			}()
+
+			code, state := oidcLogin(t, server, "acme", aliceClaims())
+			if tt.before != nil {
+				tt.before(state)
+			}
+			if _, err := (&OIDCService{}).Authenticate(context.Background(), code, state); err == nil {
+				t.Fatal("login succeeded")
+			}
+			if n := countUsers(t); n != 0 {
+				t.Errorf("%d users provisioned", n)
+			}
+		})
+	}
+}
+
+func TestOIDCWithoutAutoProvision(t *testing.T) {
+	setupTestDB(t)
+	createTestUser(t, "acme", "alice")
+	server, provider := setupOIDC(t, "acme")
+	database.DB.Model(provider).Update("auto_provision", false)
+
+	// 邮箱未验证时不关联已有用户
+	claims := aliceClaims()
+	claims["email_verified"] = false
+
+	code, state := oidcLogin(t, server, "acme", claims)
+	if _, err := (&OIDCService{}).Authenticate(context.Background(), code, state); err == nil {
+		t.Fatal("login succeeded without auto provisioning")
+	}
+	if n := countUsers(t); n != 1 {
+		t.Errorf("%d users, want only the existing one", n)
+	}
+}
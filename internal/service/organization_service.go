@@ -3,13 +3,45 @@ package service
 import (
 	"backend/internal/model"
 	"backend/pkg/database"
+	"backend/pkg/logger"
 	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrganizationNotFound = errors.New("组织不存在")
+	ErrOrganizationCycle    = errors.New("不能将组织移动到自己或下级组织之下")
 )
 
+// OrganizationSettings 组织安全设置，字段为 nil 时保持不变
+type OrganizationSettings struct {
+	RequireMFA               *bool
+	RequireEmailVerification *bool
+	RegistrationMode         *string
+	RegistrationDomains      []string // nil 表示保持不变
+}
+
 type OrganizationService struct{}
 
-// Create 创建新组织
-func (s *OrganizationService) Create(code, description string) (*model.Organization, error) {
+// Create 创建新组织，parentID 不为空时创建为该组织的下级组织
+// 只有超级管理员可以创建顶级组织，其他管理员只能在自己所在的组织或其下级组织之下创建
+func (s *OrganizationService) Create(actor *model.User, code, description string, parentID *uint) (*model.Organization, error) {
+	if parentID != nil && *parentID == 0 {
+		parentID = nil
+	}
+	if parentID == nil && actor.Role != model.RoleSuperAdmin {
+		return nil, ErrForbidden
+	}
+	if parentID != nil {
+		if !canAdministerOrganization(actor, *parentID) {
+			return nil, ErrForbidden
+		}
+		if _, err := s.findParent(*parentID); err != nil {
+			return nil, err
+		}
+	}
+
 	// 检查组织代码是否已存在
 	var count int64
 	database.DB.Model(&model.Organization{}).Where("code = ?", code).Count(&count)
@@ -21,12 +53,14 @@ func (s *OrganizationService) Create(code, description string) (*model.Organizat
 	org := model.Organization{
 		Code:        code,
 		Description: description,
+		ParentID:    parentID,
 	}
 
 	if err := database.DB.Create(&org).Error; err != nil {
 		return nil, errors.New("创建组织失败")
 	}
 
+	s.log("organization_created", actor, &org)
 	return &org, nil
 }
 
@@ -43,7 +77,7 @@ func (s *OrganizationService) List() ([]model.Organization, error) {
 func (s *OrganizationService) Get(id uint) (*model.Organization, error) {
 	var org model.Organization
 	if err := database.DB.First(&org, id).Error; err != nil {
-		return nil, errors.New("组织不存在")
+		return nil, ErrOrganizationNotFound
 	}
 	return &org, nil
 }
@@ -52,7 +86,7 @@ func (s *OrganizationService) Get(id uint) (*model.Organization, error) {
 func (s *OrganizationService) Update(id uint, code, description string) (*model.Organization, error) {
 	var org model.Organization
 	if err := database.DB.First(&org, id).Error; err != nil {
-		return nil, errors.New("组织不存在")
+		return nil, ErrOrganizationNotFound
 	}
 
 	// 如果修改了组织代码，检查新代码是否已存在
@@ -76,15 +110,18 @@ func (s *OrganizationService) Update(id uint, code, description string) (*model.
 }
 
 // Delete 删除组织
+// 组织下仍有用户、加入的成员、未撤销的OAuth2客户端或下级组织时不能删除；
+// 团队、自定义角色、邀请、已撤销的OAuth2客户端及其服务账号、OIDC和LDAP配置、密码策略在同一事务中一并删除
 func (s *OrganizationService) Delete(id uint) error {
 	var org model.Organization
 	if err := database.DB.First(&org, id).Error; err != nil {
-		return errors.New("组织不存在")
+		return ErrOrganizationNotFound
 	}
 
-	// 检查组织是否有关联的用户
+	// 检查组织是否有关联的用户，已撤销客户端的服务账号随组织删除
 	var userCount int64
-	if err := database.DB.Model(&model.User{}).Where("organization_id = ?", id).Count(&userCount).Error; err != nil {
+	if err := database.DB.Model(&model.User{}).
+		Where("organization_id = ? AND is_service_account = ?", id, false).Count(&userCount).Error; err != nil {
 		return errors.New("检查组织用户失败")
 	}
 
@@ -92,9 +129,236 @@ func (s *OrganizationService) Delete(id uint) error {
 		return errors.New("组织下有用户，不能删除")
 	}
 
-	if err := database.DB.Delete(&org).Error; err != nil {
+	var memberCount int64
+	if err := database.DB.Model(&model.Membership{}).Where("organization_id = ?", id).Count(&memberCount).Error; err != nil {
+		return errors.New("检查组织成员失败")
+	}
+	if memberCount > 0 {
+		return errors.New("组织下有加入的成员，不能删除")
+	}
+
+	var clientCount int64
+	if err := database.DB.Model(&model.OAuthClient{}).
+		Where("organization_id = ? AND revoked_at IS NULL", id).Count(&clientCount).Error; err != nil {
+		return errors.New("检查OAuth2客户端失败")
+	}
+	if clientCount > 0 {
+		return errors.New("组织下有未撤销的OAuth2客户端，不能删除")
+	}
+
+	var childCount int64
+	database.DB.Model(&model.Organization{}).Where("parent_id = ?", id).Count(&childCount)
+	if childCount > 0 {
+		return errors.New("组织下有下级组织，不能删除")
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		teamIDs := tx.Model(&model.Team{}).Select("id").Where("organization_id = ?", id)
+		if err := tx.Unscoped().Where("team_id IN (?)", teamIDs).Delete(&model.TeamMember{}).Error; err != nil {
+			return err
+		}
+		for _, related := range []interface{}{
+			&model.Team{}, &model.Role{}, &model.Invitation{}, &model.OAuthClient{},
+			&model.OIDCProvider{}, &model.OIDCLoginState{}, &model.UserIdentity{},
+			&model.LDAPConfig{}, &model.PasswordPolicy{},
+		} {
+			if err := tx.Unscoped().Where("organization_id = ?", id).Delete(related).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("organization_id = ? AND is_service_account = ?", id, true).Delete(&model.User{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&org).Error
+	})
+	if err != nil {
 		return errors.New("删除组织失败")
 	}
 
 	return nil
 }
+
+// UpdateSettings 更新组织安全设置
+// 组织管理员只能修改自己所在的组织及其下级组织
+func (s *OrganizationService) UpdateSettings(actor *model.User, id uint, settings OrganizationSettings) (*model.Organization, error) {
+	if !canAdministerOrganization(actor, id) {
+		return nil, ErrForbidden
+	}
+
+	var org model.Organization
+	if err := database.DB.First(&org, id).Error; err != nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	updates := map[string]interface{}{}
+	if settings.RequireMFA != nil {
+		updates["require_mfa"] = *settings.RequireMFA
+	}
+	if settings.RequireEmailVerification != nil {
+		updates["require_email_verification"] = *settings.RequireEmailVerification
+	}
+	if settings.RegistrationDomains != nil {
+		domains, err := normalizeRegistrationDomains(settings.RegistrationDomains)
+		if err != nil {
+			return nil, err
+		}
+		org.RegistrationDomains = domains
+		updates["registration_domains"] = model.StringList(domains)
+	}
+	if settings.RegistrationMode != nil {
+		switch *settings.RegistrationMode {
+		case model.RegistrationOpen, model.RegistrationClosed, model.RegistrationDomain, model.RegistrationApproval:
+		default:
+			return nil, errors.New("无效的注册方式")
+		}
+		updates["registration_mode"] = *settings.RegistrationMode
+	}
+	mode := org.RegistrationMode
+	if settings.RegistrationMode != nil {
+		mode = *settings.RegistrationMode
+	}
+	if mode == model.RegistrationDomain && len(org.RegistrationDomains) == 0 {
+		return nil, errors.New("注册方式为 domain 时必须指定允许的邮箱域名")
+	}
+	if len(updates) > 0 {
+		if err := database.DB.Model(&org).Updates(updates).Error; err != nil {
+			return nil, errors.New("更新组织设置失败")
+		}
+	}
+
+	return &org, nil
+}
+
+// Descendants 获取组织的全部下级组织，按层级由近到远排列
+func (s *OrganizationService) Descendants(actor *model.User, id uint) ([]model.Organization, error) {
+	if !canAdministerOrganization(actor, id) {
+		return nil, ErrForbidden
+	}
+	if _, err := s.Get(id); err != nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	descendants, err := organizationDescendants(id)
+	if err != nil {
+		return nil, errors.New("获取下级组织失败")
+	}
+	return descendants, nil
+}
+
+// Move 将组织连同其下级组织移动到新的上级组织之下，parentID 为空时成为顶级组织
+// 超级管理员可以移动任意组织；其他管理员只能在自己管理的子树内移动下级组织，不能移动自己所在的组织
+func (s *OrganizationService) Move(actor *model.User, id uint, parentID *uint) (*model.Organization, error) {
+	if parentID != nil && *parentID == 0 {
+		parentID = nil
+	}
+	if actor.Role != model.RoleSuperAdmin {
+		if id == actor.OrganizationID || parentID == nil {
+			return nil, ErrForbidden
+		}
+		if !canAdministerOrganization(actor, id) || !canAdministerOrganization(actor, *parentID) {
+			return nil, ErrForbidden
+		}
+	}
+
+	var org model.Organization
+	if err := database.DB.First(&org, id).Error; err != nil {
+		return nil, ErrOrganizationNotFound
+	}
+	if org.Code == "system" {
+		return nil, errors.New("不能移动系统组织")
+	}
+	if parentID != nil {
+		if _, err := s.findParent(*parentID); err != nil {
+			return nil, err
+		}
+		for _, ancestor := range organizationAncestors(*parentID) {
+			if ancestor == id {
+				return nil, ErrOrganizationCycle
+			}
+		}
+	}
+
+	if err := database.DB.Model(&org).Update("parent_id", parentID).Error; err != nil {
+		return nil, errors.New("移动组织失败")
+	}
+	org.ParentID = parentID
+
+	s.log("organization_moved", actor, &org)
+	return &org, nil
+}
+
+// findParent 查找可以作为上级组织的组织，系统组织不能有下级组织
+func (s *OrganizationService) findParent(id uint) (*model.Organization, error) {
+	var parent model.Organization
+	if err := database.DB.First(&parent, id).Error; err != nil {
+		return nil, errors.New("上级组织不存在")
+	}
+	if parent.Code == "system" {
+		return nil, errors.New("系统组织不能有下级组织")
+	}
+	return &parent, nil
+}
+
+func (s *OrganizationService) log(event string, actor *model.User, org *model.Organization) {
+	fields := map[string]interface{}{
+		"event":           event,
+		"organization_id": org.ID,
+		"code":            org.Code,
+		"actor_id":        actor.ID,
+	}
+	if org.ParentID != nil {
+		fields["parent_id"] = *org.ParentID
+	}
+	logger.WithFields(fields).Info("已修改组织层级")
+}
+
+// organizationAncestors 获取组织自身及其全部上级组织的ID，由近到远排列
+func organizationAncestors(id uint) []uint {
+	ancestors := []uint{id}
+	for current := id; ; {
+		var org model.Organization
+		if err := database.DB.Select("id", "parent_id").First(&org, current).Error; err != nil || org.ParentID == nil {
+			return ancestors
+		}
+		// 层级数据异常形成环时停止查找
+		for _, seen := range ancestors {
+			if seen == *org.ParentID {
+				return ancestors
+			}
+		}
+		current = *org.ParentID
+		ancestors = append(ancestors, current)
+	}
+}
+
+// organizationDescendants 获取组织的全部下级组织，按层级由近到远排列
+func organizationDescendants(id uint) ([]model.Organization, error) {
+	var descendants []model.Organization
+	parents := []uint{id}
+	for len(parents) > 0 {
+		var children []model.Organization
+		if err := database.DB.Where("parent_id IN ?", parents).Order("id").Find(&children).Error; err != nil {
+			return nil, err
+		}
+		parents = parents[:0]
+		for _, child := range children {
+			descendants = append(descendants, child)
+			parents = append(parents, child.ID)
+		}
+	}
+	return descendants, nil
+}
+
+// canAdministerOrganization 检查操作者能否管理组织，所需的权限由路由校验
+// 超级管理员可以管理所有组织，其他用户可以管理自己所在的组织及其下级组织
+func canAdministerOrganization(actor *model.User, organizationID uint) bool {
+	if actor.Role == model.RoleSuperAdmin || actor.OrganizationID == organizationID {
+		return true
+	}
+	for _, ancestor := range organizationAncestors(organizationID) {
+		if ancestor == actor.OrganizationID {
+			return true
+		}
+	}
+	return false
+}
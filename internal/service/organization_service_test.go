@@ -0,0 +1,150 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/database"
+	"errors"
+	"testing"
+)
+
+// createOrganizationTree 创建 reseller -> customer -> branch 三级组织和不相关的 globex
+func createOrganizationTree(t *testing.T) (reseller, customer, branch, globex *model.Organization) {
+	t.Helper()
+	root := &model.User{Role: model.RoleSuperAdmin}
+	service := &OrganizationService{}
+	create := func(code string, parent *model.Organization) *model.Organization {
+		var parentID *uint
+		if parent != nil {
+			parentID = &parent.ID
+		}
+		org, err := service.Create(root, code, "", parentID)
+		if err != nil {
+			t.Fatalf("create %s: %v", code, err)
+		}
+		return org
+	}
+	reseller = create("reseller", nil)
+	customer = create("customer", reseller)
+	branch = create("branch", customer)
+	globex = create("globex", nil)
+	return reseller, customer, branch, globex
+}
+
+func TestOrganizationSubtreeAdministration(t *testing.T) {
+	setupTestDB(t)
+	reseller, customer, branch, globex := createOrganizationTree(t)
+	resellerAdmin := addTestUser(t, reseller.ID, "reseller-admin", model.RoleOrgAdmin)
+	customerAdmin := addTestUser(t, customer.ID, "customer-admin", model.RoleOrgAdmin)
+
+	for _, org := range []*model.Organization{reseller, customer, branch} {
+		if !canAdministerOrganization(resellerAdmin, org.ID) {
+			t.Errorf("reseller admin cannot administer %s", org.Code)
+		}
+	}
+	if canAdministerOrganization(resellerAdmin, globex.ID) {
+		t.Error("reseller admin can administer an unrelated organization")
+	}
+	if canAdministerOrganization(customerAdmin, reseller.ID) {
+		t.Error("customer admin can administer its parent organization")
+	}
+
+	// 上级组织的管理员可以管理下级组织的用户
+	branchUser := addTestUser(t, branch.ID, "branch-user", model.RoleOrgMember)
+	if _, err := (&UserService{}).Get(resellerAdmin, branchUser.ID); err != nil {
+		t.Errorf("reseller admin reading a branch user: %v", err)
+	}
+	if _, err := (&UserService{}).Get(customerAdmin, resellerAdmin.ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("customer admin reading a reseller user: err = %v, want ErrForbidden", err)
+	}
+
+	descendants, err := (&OrganizationService{}).Descendants(resellerAdmin, reseller.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(descendants) != 2 || descendants[0].ID != customer.ID || descendants[1].ID != branch.ID {
+		t.Errorf("descendants = %+v, want customer then branch", descendants)
+	}
+	if _, err := (&OrganizationService{}).Descendants(customerAdmin, reseller.ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("customer admin listing reseller descendants: err = %v, want ErrForbidden", err)
+	}
+}
+
+func TestOrganizationCreateChild(t *testing.T) {
+	setupTestDB(t)
+	reseller, customer, _, globex := createOrganizationTree(t)
+	customerAdmin := addTestUser(t, customer.ID, "customer-admin", model.RoleOrgAdmin)
+	service := &OrganizationService{}
+
+	if _, err := service.Create(customerAdmin, "top-level", "", nil); !errors.Is(err, ErrForbidden) {
+		t.Errorf("top-level organization: err = %v, want ErrForbidden", err)
+	}
+	for _, parent := range []*model.Organization{reseller, globex} {
+		if _, err := service.Create(customerAdmin, "child-of-"+parent.Code, "", &parent.ID); !errors.Is(err, ErrForbidden) {
+			t.Errorf("child of %s: err = %v, want ErrForbidden", parent.Code, err)
+		}
+	}
+	child, err := service.Create(customerAdmin, "customer-east", "", &customer.ID)
+	if err != nil {
+		t.Fatalf("child of own organization: %v", err)
+	}
+	if child.ParentID == nil || *child.ParentID != customer.ID {
+		t.Errorf("child parent = %v, want %d", child.ParentID, customer.ID)
+	}
+}
+
+func TestOrganizationMove(t *testing.T) {
+	setupTestDB(t)
+	reseller, customer, branch, globex := createOrganizationTree(t)
+	resellerAdmin := addTestUser(t, reseller.ID, "reseller-admin", model.RoleOrgAdmin)
+	root := &model.User{Role: model.RoleSuperAdmin}
+	service := &OrganizationService{}
+
+	// 不能移动到自己或自己的下级组织之下
+	if _, err := service.Move(root, customer.ID, &branch.ID); !errors.Is(err, ErrOrganizationCycle) {
+		t.Errorf("move under descendant: err = %v, want ErrOrganizationCycle", err)
+	}
+	if _, err := service.Move(root, customer.ID, &customer.ID); !errors.Is(err, ErrOrganizationCycle) {
+		t.Errorf("move under itself: err = %v, want ErrOrganizationCycle", err)
+	}
+
+	// 非超级管理员只能在自己的子树内移动，不能移出子树或移动自己所在的组织
+	if _, err := service.Move(resellerAdmin, branch.ID, &reseller.ID); err != nil {
+		t.Errorf("move within subtree: %v", err)
+	}
+	if _, err := service.Move(resellerAdmin, branch.ID, &globex.ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("move out of subtree: err = %v, want ErrForbidden", err)
+	}
+	if _, err := service.Move(resellerAdmin, branch.ID, nil); !errors.Is(err, ErrForbidden) {
+		t.Errorf("move to top level: err = %v, want ErrForbidden", err)
+	}
+	if _, err := service.Move(resellerAdmin, reseller.ID, &globex.ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("move own organization: err = %v, want ErrForbidden", err)
+	}
+
+	// 超级管理员移动后，原上级组织的管理员不再能管理该组织
+	if _, err := service.Move(root, customer.ID, &globex.ID); err != nil {
+		t.Fatalf("move by super admin: %v", err)
+	}
+	if canAdministerOrganization(resellerAdmin, customer.ID) {
+		t.Error("reseller admin still administers a moved organization")
+	}
+}
+
+func TestOrganizationDeleteWithChildren(t *testing.T) {
+	setupTestDB(t)
+	_, customer, branch, _ := createOrganizationTree(t)
+	service := &OrganizationService{}
+
+	if err := service.Delete(customer.ID); err == nil {
+		t.Fatal("deleted an organization with children")
+	}
+	if err := service.Delete(branch.ID); err != nil {
+		t.Fatalf("delete leaf: %v", err)
+	}
+	if err := service.Delete(customer.ID); err != nil {
+		t.Errorf("delete after removing children: %v", err)
+	}
+	if err := database.DB.First(&model.Organization{}, customer.ID).Error; err == nil {
+		t.Error("organization still exists")
+	}
+}
@@ -0,0 +1,346 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/config"
+	"backend/pkg/database"
+	"backend/pkg/hasher"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// maxPasswordHistory 每个用户最多保留的历史密码数量，也是 history_depth 的上限
+const maxPasswordHistory = 24
+
+// bcryptMaxPasswordBytes bcrypt 只使用密码的前72个字节
+const bcryptMaxPasswordBytes = 72
+
+// PasswordViolation 密码违反的单条策略
+type PasswordViolation struct {
+	Code    string `json:"code" example:"too_short"`       // 违规代码
+	Message string `json:"message" example:"密码长度不能少于8个字符"` // 违规说明
+}
+
+// PasswordPolicyError 密码不符合策略，包含全部违规项
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "密码不符合安全策略"
+}
+
+// PasswordRules 生效的密码策略（全局配置合并组织覆盖后的结果）
+type PasswordRules struct {
+	MinLength        int      `mapstructure:"min_length" json:"min_length"`
+	MaxLength        int      `mapstructure:"max_length" json:"max_length"`
+	RequireUppercase bool     `mapstructure:"require_uppercase" json:"require_uppercase"`
+	RequireLowercase bool     `mapstructure:"require_lowercase" json:"require_lowercase"`
+	RequireDigit     bool     `mapstructure:"require_digit" json:"require_digit"`
+	RequireSymbol    bool     `mapstructure:"require_symbol" json:"require_symbol"`
+	DisallowUsername bool     `mapstructure:"disallow_username" json:"disallow_username"`
+	BannedWords      []string `mapstructure:"banned_words" json:"banned_words"`
+	HistoryDepth     int      `mapstructure:"history_depth" json:"history_depth"`
+	MaxAgeDays       int      `mapstructure:"max_age_days" json:"max_age_days"`
+}
+
+// PasswordPolicySettings 组织密码策略，nil 字段沿用全局配置
+type PasswordPolicySettings struct {
+	MinLength        *int
+	MaxLength        *int
+	RequireUppercase *bool
+	RequireLowercase *bool
+	RequireDigit     *bool
+	RequireSymbol    *bool
+	DisallowUsername *bool
+	BannedWords      []string
+	HistoryDepth     *int
+	MaxAgeDays       *int
+}
+
+// loadGlobalPasswordRules 读取 security.password 配置，未配置的项使用默认值
+func loadGlobalPasswordRules() PasswordRules {
+	rules := PasswordRules{
+		MinLength:        8,
+		MaxLength:        64,
+		DisallowUsername: true,
+	}
+	_ = config.UnmarshalKey("security.password", &rules)
+	return rules
+}
+
+// PasswordPolicyService 密码策略
+// 请求中的密码字段只限制最大长度以拒绝过长的输入，密码的长度和复杂度统一由 Validate 按组织的策略校验
+type PasswordPolicyService struct{}
+
+// Rules 获取组织生效的密码策略，organizationID 为 0 时只使用全局配置
+func (s *PasswordPolicyService) Rules(organizationID uint) PasswordRules {
+	rules := loadGlobalPasswordRules()
+	if organizationID == 0 {
+		return rules
+	}
+
+	var policy model.PasswordPolicy
+	if err := database.DB.Where("organization_id = ?", organizationID).First(&policy).Error; err != nil {
+		return rules
+	}
+
+	if policy.MinLength != nil {
+		rules.MinLength = *policy.MinLength
+	}
+	if policy.MaxLength != nil {
+		rules.MaxLength = *policy.MaxLength
+	}
+	if policy.RequireUppercase != nil {
+		rules.RequireUppercase = *policy.RequireUppercase
+	}
+	if policy.RequireLowercase != nil {
+		rules.RequireLowercase = *policy.RequireLowercase
+	}
+	if policy.RequireDigit != nil {
+		rules.RequireDigit = *policy.RequireDigit
+	}
+	if policy.RequireSymbol != nil {
+		rules.RequireSymbol = *policy.RequireSymbol
+	}
+	if policy.DisallowUsername != nil {
+		rules.DisallowUsername = *policy.DisallowUsername
+	}
+	if policy.HistoryDepth != nil {
+		rules.HistoryDepth = *policy.HistoryDepth
+	}
+	if policy.MaxAgeDays != nil {
+		rules.MaxAgeDays = *policy.MaxAgeDays
+	}
+	rules.BannedWords = append(append([]string{}, rules.BannedWords...), policy.BannedWords...)
+	return rules
+}
+
+// Validate 按用户所在组织的策略校验密码，不符合时返回 *PasswordPolicyError
+// 用户尚未创建（ID 为 0）时不检查历史密码
+func (s *PasswordPolicyService) Validate(user *model.User, password string) error {
+	rules := s.Rules(user.OrganizationID)
+	violations := rules.check(user.Username, password)
+
+	if user.ID != 0 && rules.HistoryDepth > 0 && s.reused(user, password, rules.HistoryDepth) {
+		violations = append(violations, PasswordViolation{
+			Code:    "reused",
+			Message: fmt.Sprintf("不能使用最近%d次使用过的密码", rules.HistoryDepth),
+		})
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// Record 记录用户的新密码，并清理超出保留数量的历史密码
+func (s *PasswordPolicyService) Record(tx *gorm.DB, userID uint, passwordHash string) error {
+	if err := tx.Create(&model.PasswordHistory{UserID: userID, PasswordHash: passwordHash}).Error; err != nil {
+		return err
+	}
+
+	var stale []uint
+	tx.Model(&model.PasswordHistory{}).Where("user_id = ?", userID).
+		Order("id DESC").Offset(maxPasswordHistory).Pluck("id", &stale)
+	if len(stale) > 0 {
+		return tx.Unscoped().Delete(&model.PasswordHistory{}, stale).Error
+	}
+	return nil
+}
+
+// Expired 判断用户的本地密码是否已超过组织规定的有效期
+func (s *PasswordPolicyService) Expired(user *model.User) bool {
+	// 目录用户、联合登录用户和服务账号没有本地密码
+	if user.Password == "" || user.IsServiceAccount {
+		return false
+	}
+
+	rules := s.Rules(user.OrganizationID)
+	if rules.MaxAgeDays <= 0 {
+		return false
+	}
+
+	changedAt := user.CreatedAt
+	if user.PasswordChangedAt != nil {
+		changedAt = *user.PasswordChangedAt
+	}
+	return time.Since(changedAt) > time.Duration(rules.MaxAgeDays)*24*time.Hour
+}
+
+// GetPolicy 获取组织的密码策略覆盖项和生效的策略
+func (s *PasswordPolicyService) GetPolicy(actor *model.User, organizationID uint) (*model.PasswordPolicy, PasswordRules, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, PasswordRules{}, ErrForbidden
+	}
+
+	var org model.Organization
+	if err := database.DB.First(&org, organizationID).Error; err != nil {
+		return nil, PasswordRules{}, errors.New("组织不存在")
+	}
+
+	policy := model.PasswordPolicy{OrganizationID: organizationID}
+	database.DB.Where("organization_id = ?", organizationID).First(&policy)
+	return &policy, s.Rules(organizationID), nil
+}
+
+// SavePolicy 保存组织的密码策略，整体替换原有的覆盖项
+func (s *PasswordPolicyService) SavePolicy(actor *model.User, organizationID uint, settings PasswordPolicySettings) (*model.PasswordPolicy, PasswordRules, error) {
+	policy, _, err := s.GetPolicy(actor, organizationID)
+	if err != nil {
+		return nil, PasswordRules{}, err
+	}
+
+	policy.MinLength = settings.MinLength
+	policy.MaxLength = settings.MaxLength
+	policy.RequireUppercase = settings.RequireUppercase
+	policy.RequireLowercase = settings.RequireLowercase
+	policy.RequireDigit = settings.RequireDigit
+	policy.RequireSymbol = settings.RequireSymbol
+	policy.DisallowUsername = settings.DisallowUsername
+	policy.HistoryDepth = settings.HistoryDepth
+	policy.MaxAgeDays = settings.MaxAgeDays
+	policy.BannedWords = nil
+	for _, word := range settings.BannedWords {
+		if word = strings.TrimSpace(word); word != "" {
+			policy.BannedWords = append(policy.BannedWords, word)
+		}
+	}
+
+	// 按合并后的结果校验，避免组织覆盖项与全局配置组合出无法满足的策略
+	rules := loadGlobalPasswordRules()
+	if policy.MinLength != nil {
+		rules.MinLength = *policy.MinLength
+	}
+	if policy.MaxLength != nil {
+		rules.MaxLength = *policy.MaxLength
+	}
+	switch {
+	case rules.MinLength < 1:
+		return nil, PasswordRules{}, errors.New("最小长度不能小于1")
+	case rules.MaxLength < rules.MinLength:
+		return nil, PasswordRules{}, errors.New("最大长度不能小于最小长度")
+	case rules.MaxLength > maxPasswordLength():
+		return nil, PasswordRules{}, fmt.Errorf("最大长度不能超过%d", maxPasswordLength())
+	case policy.HistoryDepth != nil && (*policy.HistoryDepth < 0 || *policy.HistoryDepth > maxPasswordHistory):
+		return nil, PasswordRules{}, fmt.Errorf("历史密码数量必须在0到%d之间", maxPasswordHistory)
+	case policy.MaxAgeDays != nil && *policy.MaxAgeDays < 0:
+		return nil, PasswordRules{}, errors.New("密码有效天数不能为负数")
+	}
+
+	if err := database.DB.Save(policy).Error; err != nil {
+		return nil, PasswordRules{}, errors.New("保存密码策略失败")
+	}
+	return policy, s.Rules(organizationID), nil
+}
+
+// DeletePolicy 删除组织的密码策略，恢复使用全局配置
+func (s *PasswordPolicyService) DeletePolicy(actor *model.User, organizationID uint) error {
+	if !canAdministerOrganization(actor, organizationID) {
+		return ErrForbidden
+	}
+
+	result := database.DB.Unscoped().Where("organization_id = ?", organizationID).Delete(&model.PasswordPolicy{})
+	if result.Error != nil {
+		return errors.New("删除密码策略失败")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("该组织未配置密码策略")
+	}
+	return nil
+}
+
+// maxPasswordLength 策略允许的最大密码长度
+// bcrypt 只使用密码的前72个字节，其他算法受请求中密码字段的长度限制
+func maxPasswordLength() int {
+	if _, ok := hasher.Default.(*hasher.Bcrypt); ok {
+		return bcryptMaxPasswordBytes
+	}
+	return 128
+}
+
+// reused 检查密码是否与当前密码或最近 depth 次使用过的密码相同
+func (s *PasswordPolicyService) reused(user *model.User, password string, depth int) bool {
+	hashes := []string{}
+	if user.Password != "" {
+		hashes = append(hashes, user.Password)
+	}
+
+	var history []model.PasswordHistory
+	database.DB.Where("user_id = ?", user.ID).Order("id DESC").Limit(depth).Find(&history)
+	for _, item := range history {
+		hashes = append(hashes, item.PasswordHash)
+	}
+
+	for _, hash := range hashes {
+		if hasher.Matches(password, hash) {
+			return true
+		}
+	}
+	return false
+}
+
+// check 校验不依赖历史记录的规则，返回全部违规项
+func (r PasswordRules) check(username, password string) []PasswordViolation {
+	var violations []PasswordViolation
+	add := func(code, message string) {
+		violations = append(violations, PasswordViolation{Code: code, Message: message})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < r.MinLength {
+		add("too_short", fmt.Sprintf("密码长度不能少于%d个字符", r.MinLength))
+	}
+	if r.MaxLength > 0 && length > r.MaxLength {
+		add("too_long", fmt.Sprintf("密码长度不能超过%d个字符", r.MaxLength))
+	} else if _, ok := hasher.Default.(*hasher.Bcrypt); ok && len(password) > bcryptMaxPasswordBytes {
+		// 策略的最大长度按字符计算，bcrypt 的限制按字节计算
+		add("too_long", fmt.Sprintf("密码长度不能超过%d个字节，中文等字符按多个字节计算", bcryptMaxPasswordBytes))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, ch := range password {
+		switch {
+		case unicode.IsUpper(ch):
+			hasUpper = true
+		case unicode.IsLower(ch):
+			hasLower = true
+		case unicode.IsDigit(ch):
+			hasDigit = true
+		case unicode.IsPunct(ch) || unicode.IsSymbol(ch) || unicode.IsSpace(ch):
+			hasSymbol = true
+		}
+	}
+	if r.RequireUppercase && !hasUpper {
+		add("missing_uppercase", "密码必须包含大写字母")
+	}
+	if r.RequireLowercase && !hasLower {
+		add("missing_lowercase", "密码必须包含小写字母")
+	}
+	if r.RequireDigit && !hasDigit {
+		add("missing_digit", "密码必须包含数字")
+	}
+	if r.RequireSymbol && !hasSymbol {
+		add("missing_symbol", "密码必须包含特殊字符")
+	}
+
+	lowered := strings.ToLower(password)
+	if r.DisallowUsername && username != "" && strings.Contains(lowered, strings.ToLower(username)) {
+		add("contains_username", "密码不能包含用户名")
+	}
+	for _, word := range r.BannedWords {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" && strings.Contains(lowered, word) {
+			add("banned_word", "密码不能包含常见或被禁用的词语: "+word)
+			break
+		}
+	}
+
+	return violations
+}
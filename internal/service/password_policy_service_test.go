@@ -0,0 +1,55 @@
+package service
+
+import (
+	"backend/pkg/hasher"
+	"strings"
+	"testing"
+)
+
+func hasViolation(violations []PasswordViolation, code string) bool {
+	for _, violation := range violations {
+		if violation.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// bcrypt 的72字节限制按字节计算，多字节字符不能借字符数绕过
+func TestPasswordRulesBcryptByteLimit(t *testing.T) {
+	previous := hasher.Default
+	t.Cleanup(func() { hasher.Default = previous })
+	rules := PasswordRules{MinLength: 8, MaxLength: 72}
+
+	fits := strings.Repeat("密", 24)    // 24个字符，72个字节
+	tooLong := strings.Repeat("密", 25) // 25个字符，75个字节
+
+	hasher.Default = hasher.DefaultBcrypt()
+	if violations := rules.check("alice", fits); hasViolation(violations, "too_long") {
+		t.Errorf("72 bytes rejected: %+v", violations)
+	}
+	if violations := rules.check("alice", tooLong); !hasViolation(violations, "too_long") {
+		t.Errorf("75 bytes accepted with bcrypt: %+v", violations)
+	}
+	if _, err := hasher.Hash(fits); err != nil {
+		t.Errorf("hashing 72 bytes: %v", err)
+	}
+
+	hasher.Default = hasher.DefaultArgon2id()
+	if violations := rules.check("alice", tooLong); hasViolation(violations, "too_long") {
+		t.Errorf("25 characters rejected with argon2id: %+v", violations)
+	}
+
+	// 超过策略的字符数时只报告一次
+	hasher.Default = hasher.DefaultBcrypt()
+	violations := rules.check("alice", strings.Repeat("a", 80))
+	count := 0
+	for _, violation := range violations {
+		if violation.Code == "too_long" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("%d too_long violations, want 1", count)
+	}
+}
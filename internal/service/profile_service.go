@@ -0,0 +1,146 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/database"
+	"backend/pkg/logger"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	_ "time/tzdata" // 内嵌时区数据库，运行环境缺少时区数据时也能校验时区
+
+	"gorm.io/gorm"
+)
+
+// localePattern BCP 47 语言标签，如 zh-CN、en、zh-Hant-TW
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// Profile 当前用户的资料
+type Profile struct {
+	UserID         uint   `json:"user_id"`
+	Username       string `json:"username" example:"john_doe"`
+	Email          string `json:"email" example:"john@example.com"`
+	EmailVerified  bool   `json:"email_verified"`
+	Role           string `json:"role" example:"org_member"`
+	OrganizationID uint   `json:"organization_id"`
+	Organization   string `json:"organization" example:"company_a"` // 组织代码
+	Status         string `json:"status" example:"active"`
+	DisplayName    string `json:"display_name" example:"张三"`
+	Locale         string `json:"locale" example:"zh-CN"`
+	Timezone       string `json:"timezone" example:"Asia/Shanghai"`
+}
+
+// ProfileInput 用户修改自己的资料，为 nil 的字段保持不变，空字符串表示清除
+type ProfileInput struct {
+	Email       *string
+	DisplayName *string
+	Locale      *string
+	Timezone    *string
+}
+
+// ProfileService 用户本人维护的个人资料
+type ProfileService struct {
+	emailService EmailVerificationService
+}
+
+// Get 获取用户的资料，组织和角色按当前令牌所在的组织返回
+func (s *ProfileService) Get(user *model.User) (*Profile, error) {
+	var org model.Organization
+	if err := database.DB.First(&org, user.OrganizationID).Error; err != nil {
+		return nil, errors.New("获取组织信息失败")
+	}
+
+	var profile model.UserProfile
+	database.DB.Where("user_id = ?", user.ID).First(&profile)
+
+	return &Profile{
+		UserID:         user.ID,
+		Username:       user.Username,
+		Email:          user.Email,
+		EmailVerified:  user.EmailVerifiedAt != nil,
+		Role:           user.Role,
+		OrganizationID: org.ID,
+		Organization:   org.Code,
+		Status:         user.Status,
+		DisplayName:    profile.DisplayName,
+		Locale:         profile.Locale,
+		Timezone:       profile.Timezone,
+	}, nil
+}
+
+// Update 修改用户自己的资料
+// 修改邮箱后需要重新验证，之前发出的验证链接随即作废；目录用户和联合登录用户的邮箱由身份提供方维护，不能修改
+func (s *ProfileService) Update(current *model.User, input ProfileInput) (*Profile, error) {
+	// 切换组织后的令牌中组织和角色已被替换，按用户本身的所在组织修改
+	var user model.User
+	if err := database.DB.Preload("Organization").First(&user, current.ID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	var profile model.UserProfile
+	if err := database.DB.Where("user_id = ?", user.ID).First(&profile).Error; err != nil {
+		profile = model.UserProfile{UserID: user.ID}
+	}
+	if input.DisplayName != nil {
+		profile.DisplayName = strings.TrimSpace(*input.DisplayName)
+	}
+	if input.Locale != nil {
+		locale := strings.TrimSpace(*input.Locale)
+		if locale != "" && !localePattern.MatchString(locale) {
+			return nil, errors.New("无效的语言区域，请使用 BCP 47 格式，如 zh-CN")
+		}
+		profile.Locale = locale
+	}
+	if input.Timezone != nil {
+		timezone := strings.TrimSpace(*input.Timezone)
+		if timezone != "" {
+			if _, err := time.LoadLocation(timezone); err != nil || timezone == "Local" {
+				return nil, errors.New("无效的时区，请使用 IANA 时区名称，如 Asia/Shanghai")
+			}
+		}
+		profile.Timezone = timezone
+	}
+
+	emailChanged := input.Email != nil && !strings.EqualFold(*input.Email, user.Email)
+	if emailChanged {
+		if user.Password == "" || user.IsServiceAccount {
+			return nil, errors.New("该账号的邮箱由身份提供方维护，不能修改")
+		}
+		user.Email = strings.TrimSpace(*input.Email)
+		user.EmailVerifiedAt = nil
+		if err := user.CheckUnique(database.DB); err != nil {
+			return nil, err
+		}
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if emailChanged {
+			if err := tx.Model(&user).Updates(map[string]interface{}{
+				"email":             user.Email,
+				"email_verified_at": nil,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Save(&profile).Error
+	})
+	if err != nil {
+		return nil, errors.New("修改个人资料失败")
+	}
+	if emailChanged {
+		s.emailService.Restart(&user)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"event":         "profile_updated",
+		"user_id":       user.ID,
+		"email_changed": emailChanged,
+	}).Info("用户修改了个人资料")
+
+	if emailChanged {
+		current.Email = user.Email
+		current.EmailVerifiedAt = nil
+	}
+	return s.Get(current)
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/database"
+	"backend/pkg/logger"
+	"backend/pkg/mailer"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRegistrationClosed    = errors.New("该组织未开放注册，请通过邀请加入")
+	ErrEmailDomainNotAllowed = errors.New("该组织只允许使用指定域名的邮箱注册")
+	ErrAccountPending        = errors.New("账号正在等待组织管理员审核")
+	ErrRegistrationNotFound  = errors.New("待审核的注册不存在")
+)
+
+// RegistrationService 自助注册审核
+type RegistrationService struct{}
+
+// ListPending 获取组织等待审核的注册
+func (s *RegistrationService) ListPending(actor *model.User, organizationID uint) ([]model.User, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+
+	var users []model.User
+	if err := database.DB.Where("organization_id = ? AND status = ?", organizationID, model.UserStatusPending).
+		Order("id").Find(&users).Error; err != nil {
+		return nil, errors.New("获取待审核注册失败")
+	}
+	return users, nil
+}
+
+// Approve 通过注册审核，用户随后可以登录
+func (s *RegistrationService) Approve(actor *model.User, organizationID, userID uint) (*model.User, error) {
+	user, err := s.findPending(actor, organizationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := changeUserStatus(actor, user, model.UserStatusActive, "注册审核通过"); err != nil {
+		if errors.Is(err, ErrStatusTransition) {
+			return nil, ErrRegistrationNotFound
+		}
+		return nil, errors.New("审核注册失败")
+	}
+
+	s.log("registration_approved", actor, user, "")
+	s.notify(*user, fmt.Sprintf("%s，您好：\n\n您在组织 %s 的注册申请已通过审核，现在可以登录。",
+		user.Username, user.Organization.Code))
+	return user, nil
+}
+
+// Reject 拒绝注册并删除该账号，用户名和邮箱可以重新注册
+func (s *RegistrationService) Reject(actor *model.User, organizationID, userID uint, reason string) error {
+	user, err := s.findPending(actor, organizationID, userID)
+	if err != nil {
+		return err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Where("id = ? AND status = ?", user.ID, model.UserStatusPending).Delete(&model.User{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRegistrationNotFound
+		}
+		if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(&model.PasswordHistory{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("user_id = ?", user.ID).Delete(&model.UserToken{}).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrRegistrationNotFound) {
+			return err
+		}
+		return errors.New("拒绝注册失败")
+	}
+
+	s.log("registration_rejected", actor, user, reason)
+	body := fmt.Sprintf("%s，您好：\n\n您在组织 %s 的注册申请未通过审核。", user.Username, user.Organization.Code)
+	if reason != "" {
+		body += "\n\n原因：" + reason
+	}
+	s.notify(*user, body)
+	return nil
+}
+
+// findPending 查找组织中等待审核的用户
+func (s *RegistrationService) findPending(actor *model.User, organizationID, userID uint) (*model.User, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+
+	var user model.User
+	if err := database.DB.Preload("Organization").
+		Where("id = ? AND organization_id = ? AND status = ?", userID, organizationID, model.UserStatusPending).
+		First(&user).Error; err != nil {
+		return nil, ErrRegistrationNotFound
+	}
+	return &user, nil
+}
+
+func (s *RegistrationService) log(event string, actor, user *model.User, reason string) {
+	logger.WithFields(map[string]interface{}{
+		"event":           event,
+		"user_id":         user.ID,
+		"organization_id": user.OrganizationID,
+		"actor_id":        actor.ID,
+		"reason":          reason,
+	}).Info("已处理注册审核")
+}
+
+// notify 在后台向用户发送审核结果
+func (s *RegistrationService) notify(user model.User, body string) {
+	if user.Email == "" {
+		return
+	}
+	go func() {
+		err := mailer.Send(mailer.Message{
+			To:      user.Email,
+			Subject: fmt.Sprintf("组织 %s 注册审核结果", user.Organization.Code),
+			Body:    body,
+		})
+		if err != nil {
+			logger.WithFields(map[string]interface{}{"user_id": user.ID}).Error("发送审核结果邮件失败: " + err.Error())
+		}
+	}()
+}
+
+// registrationStatus 按组织的注册方式检查邮箱能否自助注册，返回新用户的初始状态
+func registrationStatus(org *model.Organization, email string) (string, error) {
+	switch org.RegistrationMode {
+	case model.RegistrationClosed:
+		return "", ErrRegistrationClosed
+	case model.RegistrationDomain:
+		at := strings.LastIndex(email, "@")
+		if at < 0 || !org.RegistrationDomains.Contains(strings.ToLower(email[at+1:])) {
+			return "", ErrEmailDomainNotAllowed
+		}
+		return model.UserStatusActive, nil
+	case model.RegistrationApproval:
+		return model.UserStatusPending, nil
+	default:
+		return model.UserStatusActive, nil
+	}
+}
+
+// normalizeRegistrationDomains 规范化允许注册的邮箱域名：去除空白和 @ 前缀并转为小写
+func normalizeRegistrationDomains(domains []string) ([]string, error) {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+		if domain == "" {
+			continue
+		}
+		if !strings.Contains(domain, ".") || strings.ContainsAny(domain, "@, \t") {
+			return nil, fmt.Errorf("无效的邮箱域名: %s", domain)
+		}
+		if !model.StringList(normalized).Contains(domain) {
+			normalized = append(normalized, domain)
+		}
+	}
+	return normalized, nil
+}
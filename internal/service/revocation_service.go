@@ -0,0 +1,130 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/database"
+	"backend/pkg/jwt"
+	"backend/pkg/logger"
+	"errors"
+	"sync"
+	"time"
+)
+
+// revokedTokenCache 已撤销令牌的内存缓存（jti -> 令牌过期时间）
+// 数据库是撤销记录的唯一来源，缓存定期与数据库同步以感知其他实例的撤销操作
+var revokedTokenCache = struct {
+	sync.RWMutex
+	items map[string]time.Time
+}{items: make(map[string]time.Time)}
+
+type RevocationService struct {
+	tokenService TokenService
+}
+
+// RevokeToken 撤销单个访问令牌
+func (s *RevocationService) RevokeToken(claims *jwt.CustomClaims, reason string) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return errors.New("令牌缺少jti，无法撤销")
+	}
+
+	record := model.RevokedToken{
+		JTI:       claims.ID,
+		UserID:    claims.UserID,
+		ExpiresAt: claims.ExpiresAt.Time,
+		Reason:    reason,
+	}
+	if err := database.DB.Where("jti = ?", record.JTI).FirstOrCreate(&record).Error; err != nil {
+		return errors.New("撤销令牌失败")
+	}
+
+	revokedTokenCache.Lock()
+	revokedTokenCache.items[record.JTI] = record.ExpiresAt
+	revokedTokenCache.Unlock()
+	return nil
+}
+
+// IsRevoked 检查令牌是否已被撤销
+func (s *RevocationService) IsRevoked(jti string) bool {
+	revokedTokenCache.RLock()
+	defer revokedTokenCache.RUnlock()
+	_, ok := revokedTokenCache.items[jti]
+	return ok
+}
+
+// RevokeUserTokens 撤销用户所有已签发的访问令牌和刷新令牌
+// 用于管理员强制下线、修改或重置密码等场景
+func (s *RevocationService) RevokeUserTokens(userID uint, reason string) error {
+	if err := database.DB.Model(&model.User{}).Where("id = ?", userID).
+		Update("tokens_revoked_at", time.Now()).Error; err != nil {
+		return errors.New("撤销用户令牌失败")
+	}
+	if err := s.tokenService.RevokeUserRefreshTokens(userID); err != nil {
+		return err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"user_id": userID,
+		"reason":  reason,
+	}).Info("已撤销用户的所有令牌")
+	return nil
+}
+
+// IsUserTokenRevoked 检查令牌是否签发于用户的全局撤销时间之前
+// iat 只精确到秒，因此撤销时间也按秒截断比较
+func (s *RevocationService) IsUserTokenRevoked(user *model.User, claims *jwt.CustomClaims) bool {
+	if user.TokensRevokedAt == nil {
+		return false
+	}
+	if claims.IssuedAt == nil {
+		return true
+	}
+	return claims.IssuedAt.Time.Before(user.TokensRevokedAt.Truncate(time.Second))
+}
+
+// Sync 清理已过期的撤销记录并从数据库重新加载缓存
+func (s *RevocationService) Sync() error {
+	now := time.Now()
+	if err := database.DB.Unscoped().Where("expires_at < ?", now).
+		Delete(&model.RevokedToken{}).Error; err != nil {
+		return err
+	}
+
+	var records []model.RevokedToken
+	if err := database.DB.Select("jti", "expires_at").Find(&records).Error; err != nil {
+		return err
+	}
+
+	items := make(map[string]time.Time, len(records))
+	for _, record := range records {
+		items[record.JTI] = record.ExpiresAt
+	}
+
+	revokedTokenCache.Lock()
+	// 保留查询期间新增且尚未过期的本地撤销记录
+	for jti, expiresAt := range revokedTokenCache.items {
+		if expiresAt.After(now) {
+			items[jti] = expiresAt
+		}
+	}
+	revokedTokenCache.items = items
+	revokedTokenCache.Unlock()
+	return nil
+}
+
+// StartSync 加载撤销列表并启动后台定期同步
+func (s *RevocationService) StartSync(interval time.Duration) error {
+	if err := s.Sync(); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := s.Sync(); err != nil {
+				logger.Errorf("同步令牌撤销列表失败: %v", err)
+			}
+		}
+	}()
+	return nil
+}
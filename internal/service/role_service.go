@@ -0,0 +1,284 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/database"
+	"backend/pkg/logger"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrRoleNotFound = errors.New("角色不存在")
+	ErrRoleInUse    = errors.New("仍有用户、成员、团队或未接受的邀请使用该角色，不能删除")
+)
+
+// roleNamePattern 自定义角色名称：小写字母开头，只包含小写字母、数字和下划线
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,31}$`)
+
+// RoleInfo 组织内可分配的角色
+type RoleInfo struct {
+	ID          uint     `json:"id,omitempty"` // 自定义角色ID，内置角色为空
+	Name        string   `json:"name" example:"helpdesk"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	Builtin     bool     `json:"builtin"` // 是否为内置角色，内置角色不能修改
+}
+
+// RoleSettings 自定义角色的可修改项
+type RoleSettings struct {
+	Description string
+	Permissions []string
+}
+
+// RoleService 权限解析和组织自定义角色管理
+type RoleService struct{}
+
+// Permissions 获取用户的权限：用户的角色拥有的权限，加上用户所在团队及其上级团队被分配的角色拥有的权限
+func (s *RoleService) Permissions(user *model.User) model.StringList {
+	permissions, _ := s.rolePermissions(user.OrganizationID, user.Role)
+	if user.Role == model.RoleSuperAdmin {
+		return permissions
+	}
+	for _, permission := range s.rolesPermissions(user.OrganizationID, teamRoles(user.ID, user.OrganizationID)) {
+		if !permissions.Contains(permission) {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions
+}
+
+// HasPermission 检查用户是否拥有指定权限
+func (s *RoleService) HasPermission(user *model.User, permission string) bool {
+	return s.Permissions(user).Contains(permission)
+}
+
+// Assignable 检查操作者能否将角色分配给组织内的用户
+// 角色必须是组织内置角色或该组织的自定义角色，且操作者拥有该角色的全部权限，避免借此提升权限
+func (s *RoleService) Assignable(actor *model.User, organizationID uint, role string) error {
+	if role == model.RoleSuperAdmin {
+		return errors.New("不能分配超级管理员角色")
+	}
+	permissions, ok := s.rolePermissions(organizationID, role)
+	if !ok {
+		return fmt.Errorf("无效的角色: %s", role)
+	}
+	return s.checkGrantable(actor, permissions)
+}
+
+// List 获取组织可分配的角色，包括内置的组织管理员、组织成员角色和组织的自定义角色
+func (s *RoleService) List(actor *model.User, organizationID uint) ([]RoleInfo, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+
+	var roles []model.Role
+	if err := database.DB.Where("organization_id = ?", organizationID).Order("name").Find(&roles).Error; err != nil {
+		return nil, errors.New("获取角色列表失败")
+	}
+
+	adminPermissions, _ := model.BuiltinRolePermissions(model.RoleOrgAdmin)
+	memberPermissions, _ := model.BuiltinRolePermissions(model.RoleOrgMember)
+	infos := []RoleInfo{
+		{Name: model.RoleOrgAdmin, Description: "组织管理员", Permissions: adminPermissions, Builtin: true},
+		{Name: model.RoleOrgMember, Description: "组织成员", Permissions: memberPermissions, Builtin: true},
+	}
+	for _, role := range roles {
+		infos = append(infos, RoleInfo{
+			ID:          role.ID,
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: role.Permissions,
+		})
+	}
+	return infos, nil
+}
+
+// Create 创建组织自定义角色
+func (s *RoleService) Create(actor *model.User, organizationID uint, name string, settings RoleSettings) (*model.Role, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+	if !roleNamePattern.MatchString(name) {
+		return nil, errors.New("角色名称只能包含小写字母、数字和下划线，以字母开头，长度为2到32个字符")
+	}
+	if model.IsBuiltinRole(name) {
+		return nil, errors.New("不能使用内置角色的名称")
+	}
+
+	var org model.Organization
+	if err := database.DB.First(&org, organizationID).Error; err != nil {
+		return nil, errors.New("组织不存在")
+	}
+	if org.Code == "system" {
+		return nil, errors.New("不能为系统组织创建角色")
+	}
+
+	permissions, err := s.validatePermissions(actor, settings.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	database.DB.Model(&model.Role{}).Where("organization_id = ? AND name = ?", organizationID, name).Count(&count)
+	if count > 0 {
+		return nil, errors.New("角色名称已存在于此组织")
+	}
+
+	role := model.Role{
+		OrganizationID: organizationID,
+		Name:           name,
+		Description:    strings.TrimSpace(settings.Description),
+		Permissions:    permissions,
+	}
+	if err := database.DB.Create(&role).Error; err != nil {
+		return nil, errors.New("创建角色失败")
+	}
+
+	s.log("role_created", actor, &role)
+	return &role, nil
+}
+
+// Update 修改自定义角色的描述和权限，角色名称创建后不能修改
+// 修改后拥有该角色的用户在下一个请求时即按新的权限授权
+func (s *RoleService) Update(actor *model.User, organizationID, roleID uint, settings RoleSettings) (*model.Role, error) {
+	role, err := s.find(actor, organizationID, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := s.validatePermissions(actor, settings.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role.Description = strings.TrimSpace(settings.Description)
+	role.Permissions = permissions
+	if err := database.DB.Save(role).Error; err != nil {
+		return nil, errors.New("修改角色失败")
+	}
+
+	s.log("role_updated", actor, role)
+	return role, nil
+}
+
+// Delete 删除自定义角色，仍有用户、其他组织加入的成员、团队或未接受的邀请使用该角色时不能删除
+func (s *RoleService) Delete(actor *model.User, organizationID, roleID uint) error {
+	role, err := s.find(actor, organizationID, roleID)
+	if err != nil {
+		return err
+	}
+
+	var users, members, invitations int64
+	database.DB.Model(&model.User{}).Where("organization_id = ? AND role = ?", organizationID, role.Name).Count(&users)
+	database.DB.Model(&model.Membership{}).Where("organization_id = ? AND role = ?", organizationID, role.Name).Count(&members)
+	database.DB.Model(&model.Invitation{}).
+		Where("organization_id = ? AND role = ? AND accepted_at IS NULL AND revoked_at IS NULL", organizationID, role.Name).
+		Count(&invitations)
+	if users > 0 || members > 0 || invitations > 0 {
+		return ErrRoleInUse
+	}
+	teams, err := loadTeams(organizationID)
+	if err != nil {
+		return err
+	}
+	for _, team := range teams {
+		if team.Roles.Contains(role.Name) {
+			return ErrRoleInUse
+		}
+	}
+
+	// 物理删除，以便之后重新创建同名角色
+	if err := database.DB.Unscoped().Delete(role).Error; err != nil {
+		return errors.New("删除角色失败")
+	}
+
+	s.log("role_deleted", actor, role)
+	return nil
+}
+
+// find 查找组织的自定义角色
+func (s *RoleService) find(actor *model.User, organizationID, roleID uint) (*model.Role, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+
+	var role model.Role
+	if err := database.DB.Where("id = ? AND organization_id = ?", roleID, organizationID).First(&role).Error; err != nil {
+		return nil, ErrRoleNotFound
+	}
+	return &role, nil
+}
+
+// rolePermissions 获取组织内角色的权限，角色不存在时返回 false
+func (s *RoleService) rolePermissions(organizationID uint, name string) (model.StringList, bool) {
+	if permissions, ok := model.BuiltinRolePermissions(name); ok {
+		return permissions, true
+	}
+
+	var role model.Role
+	if err := database.DB.Where("organization_id = ? AND name = ?", organizationID, name).First(&role).Error; err != nil {
+		return nil, false
+	}
+	return role.Permissions, true
+}
+
+// rolesPermissions 获取组织内多个角色的权限合集，忽略不存在的角色
+func (s *RoleService) rolesPermissions(organizationID uint, roles model.StringList) model.StringList {
+	var permissions model.StringList
+	for _, role := range roles {
+		rolePermissions, _ := s.rolePermissions(organizationID, role)
+		for _, permission := range rolePermissions {
+			if !permissions.Contains(permission) {
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	return permissions
+}
+
+// validatePermissions 校验并去重自定义角色的权限
+// 平台级权限只属于超级管理员，操作者也不能授予自己没有的权限
+func (s *RoleService) validatePermissions(actor *model.User, permissions []string) (model.StringList, error) {
+	var normalized model.StringList
+	for _, name := range permissions {
+		permission, ok := model.FindPermission(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("无效的权限: %s", name)
+		}
+		if permission.Global {
+			return nil, fmt.Errorf("平台级权限不能授予自定义角色: %s", permission.Name)
+		}
+		if !normalized.Contains(permission.Name) {
+			normalized = append(normalized, permission.Name)
+		}
+	}
+	if err := s.checkGrantable(actor, normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// checkGrantable 检查操作者是否拥有全部指定权限
+func (s *RoleService) checkGrantable(actor *model.User, permissions model.StringList) error {
+	held := s.Permissions(actor)
+	for _, permission := range permissions {
+		if !held.Contains(permission) {
+			return fmt.Errorf("不能授予自己没有的权限: %s", permission)
+		}
+	}
+	return nil
+}
+
+func (s *RoleService) log(event string, actor *model.User, role *model.Role) {
+	logger.WithFields(map[string]interface{}{
+		"event":           event,
+		"role_id":         role.ID,
+		"role":            role.Name,
+		"organization_id": role.OrganizationID,
+		"permissions":     strings.Join(role.Permissions, ","),
+		"actor_id":        actor.ID,
+	}).Info("已修改组织角色")
+}
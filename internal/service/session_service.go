@@ -0,0 +1,227 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/database"
+	"backend/pkg/logger"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSessionRevoked  = errors.New("登录会话已失效")
+	ErrSessionNotFound = errors.New("会话不存在")
+)
+
+// sessionTouchInterval 更新会话最近访问时间的最小间隔，避免每个请求都写数据库
+const sessionTouchInterval = time.Minute
+
+// ClientInfo 发起登录或刷新请求的客户端
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// SessionInfo 会话及其是否为当前请求所在的会话
+type SessionInfo struct {
+	model.Session
+	Current bool `json:"current"` // 是否为当前会话
+}
+
+type SessionService struct{}
+
+// Create 为新的刷新令牌族创建会话
+func (s *SessionService) Create(tx *gorm.DB, user *model.User, familyID string, expiresAt time.Time, client ClientInfo) error {
+	now := time.Now()
+	userAgent := client.UserAgent
+	if len(userAgent) > 256 {
+		userAgent = userAgent[:256]
+	}
+	return tx.Create(&model.Session{
+		UserID:         user.ID,
+		OrganizationID: user.OrganizationID,
+		FamilyID:       familyID,
+		Device:         describeDevice(client.UserAgent),
+		UserAgent:      userAgent,
+		IP:             client.IP,
+		LastSeenIP:     client.IP,
+		LastSeenAt:     now,
+		ExpiresAt:      expiresAt,
+	}).Error
+}
+
+// Extend 刷新令牌轮换后延长会话有效期并记录访问
+func (s *SessionService) Extend(tx *gorm.DB, familyID string, expiresAt time.Time, client ClientInfo) error {
+	return tx.Model(&model.Session{}).Where("family_id = ?", familyID).Updates(map[string]interface{}{
+		"expires_at":   expiresAt,
+		"last_seen_at": time.Now(),
+		"last_seen_ip": client.IP,
+	}).Error
+}
+
+// Check 校验访问令牌所属的会话是否有效，并按间隔更新最近访问时间和IP
+func (s *SessionService) Check(familyID, ip string) error {
+	var session model.Session
+	if err := database.DB.Where("family_id = ?", familyID).First(&session).Error; err != nil {
+		return ErrSessionRevoked
+	}
+	if session.RevokedAt != nil {
+		return ErrSessionRevoked
+	}
+
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval || session.LastSeenIP != ip {
+		database.DB.Model(&session).UpdateColumns(map[string]interface{}{
+			"last_seen_at": now,
+			"last_seen_ip": ip,
+		})
+	}
+	return nil
+}
+
+// List 获取用户未过期且未撤销的会话，currentFamilyID 对应的会话标记为当前会话
+func (s *SessionService) List(userID uint, currentFamilyID string) ([]SessionInfo, error) {
+	var sessions []model.Session
+	if err := database.DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").Find(&sessions).Error; err != nil {
+		return nil, errors.New("获取会话列表失败")
+	}
+
+	infos := make([]SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		infos = append(infos, SessionInfo{
+			Session: session,
+			Current: currentFamilyID != "" && session.FamilyID == currentFamilyID,
+		})
+	}
+	return infos, nil
+}
+
+// Revoke 撤销用户的单个会话
+func (s *SessionService) Revoke(userID, sessionID uint) error {
+	var session model.Session
+	if err := database.DB.Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		First(&session).Error; err != nil {
+		return ErrSessionNotFound
+	}
+	return s.revokeFamilies(userID, []string{session.FamilyID}, "session_revoke")
+}
+
+// RevokeOthers 撤销用户除 keepFamilyID 以外的全部会话，keepFamilyID 为空时撤销全部会话
+func (s *SessionService) RevokeOthers(userID uint, keepFamilyID string) (int, error) {
+	var families []string
+	database.DB.Model(&model.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL AND family_id <> ?", userID, keepFamilyID).
+		Pluck("family_id", &families)
+	if len(families) == 0 {
+		return 0, nil
+	}
+	if err := s.revokeFamilies(userID, families, "session_revoke_others"); err != nil {
+		return 0, err
+	}
+	return len(families), nil
+}
+
+// ListForUser 管理员获取所管理用户的会话
+func (s *SessionService) ListForUser(actor *model.User, userID uint) ([]SessionInfo, error) {
+	if err := s.authorize(actor, userID); err != nil {
+		return nil, err
+	}
+	return s.List(userID, "")
+}
+
+// RevokeForUser 管理员撤销所管理用户的单个会话
+func (s *SessionService) RevokeForUser(actor *model.User, userID, sessionID uint) error {
+	if err := s.authorize(actor, userID); err != nil {
+		return err
+	}
+	return s.Revoke(userID, sessionID)
+}
+
+// RevokeAllForUser 管理员撤销所管理用户的全部会话
+func (s *SessionService) RevokeAllForUser(actor *model.User, userID uint) (int, error) {
+	if err := s.authorize(actor, userID); err != nil {
+		return 0, err
+	}
+	return s.RevokeOthers(userID, "")
+}
+
+// authorize 检查操作者能否管理目标用户的会话，规则见 canManageUser
+func (s *SessionService) authorize(actor *model.User, userID uint) error {
+	_, err := findManagedUser(actor, userID)
+	return err
+}
+
+// revokeFamilies 撤销会话及其刷新令牌族
+func (s *SessionService) revokeFamilies(userID uint, families []string, reason string) error {
+	now := time.Now()
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Session{}).
+			Where("family_id IN ? AND revoked_at IS NULL", families).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.RefreshToken{}).
+			Where("family_id IN ? AND revoked_at IS NULL", families).
+			Update("revoked_at", now).Error
+	})
+	if err != nil {
+		return errors.New("撤销会话失败")
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"user_id":  userID,
+		"sessions": len(families),
+		"reason":   reason,
+	}).Info("已撤销登录会话")
+	return nil
+}
+
+// describeDevice 根据 User-Agent 识别浏览器和操作系统
+func describeDevice(userAgent string) string {
+	if userAgent == "" {
+		return "未知设备"
+	}
+
+	browser := "未知浏览器"
+	for _, candidate := range []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+		{"okhttp/", "OkHttp"},
+		{"Go-http-client/", "Go"},
+		{"python-requests/", "Python"},
+	} {
+		if strings.Contains(userAgent, candidate.token) {
+			browser = candidate.name
+			break
+		}
+	}
+
+	platform := ""
+	for _, candidate := range []struct{ token, name string }{
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(userAgent, candidate.token) {
+			platform = candidate.name
+			break
+		}
+	}
+
+	if platform == "" {
+		return browser
+	}
+	return browser + " / " + platform
+}
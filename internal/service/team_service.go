@@ -0,0 +1,392 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/database"
+	"backend/pkg/logger"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTeamNotFound       = errors.New("团队不存在")
+	ErrTeamHasChildren    = errors.New("团队仍有下级团队，请先删除或移动下级团队")
+	ErrTeamMemberNotFound = errors.New("团队成员不存在")
+)
+
+// TeamSettings 团队的可修改项，修改时整体替换
+type TeamSettings struct {
+	Name        string
+	Description string
+	ParentID    *uint
+	Roles       []string
+}
+
+// TeamMemberInfo 团队成员
+type TeamMemberInfo struct {
+	UserID   uint      `json:"user_id"`
+	Username string    `json:"username"`
+	Email    string    `json:"email"`
+	Lead     bool      `json:"lead"` // 是否为团队负责人
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// TeamService 组织团队管理
+type TeamService struct {
+	roleService RoleService
+}
+
+// List 获取组织的全部团队，通过 parent_id 组成树
+func (s *TeamService) List(actor *model.User, organizationID uint) ([]model.Team, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+
+	var teams []model.Team
+	if err := database.DB.Where("organization_id = ?", organizationID).Order("name").Find(&teams).Error; err != nil {
+		return nil, errors.New("获取团队列表失败")
+	}
+	return teams, nil
+}
+
+// Get 获取组织的团队
+func (s *TeamService) Get(actor *model.User, organizationID, teamID uint) (*model.Team, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+	return s.find(organizationID, teamID)
+}
+
+// Create 创建团队，分配给团队的角色不能超出操作者的权限
+func (s *TeamService) Create(actor *model.User, organizationID uint, settings TeamSettings) (*model.Team, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+
+	var org model.Organization
+	if err := database.DB.First(&org, organizationID).Error; err != nil {
+		return nil, errors.New("组织不存在")
+	}
+	if org.Code == "system" {
+		return nil, errors.New("不能为系统组织创建团队")
+	}
+
+	team := model.Team{OrganizationID: organizationID}
+	if err := s.apply(actor, &team, settings); err != nil {
+		return nil, err
+	}
+	if err := database.DB.Create(&team).Error; err != nil {
+		return nil, errors.New("创建团队失败")
+	}
+
+	s.log("team_created", actor, &team)
+	return &team, nil
+}
+
+// Update 修改团队的名称、描述、上级团队和角色
+// 修改后团队及其下级团队的成员在下一个请求时即按新的角色授权
+func (s *TeamService) Update(actor *model.User, organizationID, teamID uint, settings TeamSettings) (*model.Team, error) {
+	team, err := s.findManaged(actor, organizationID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(actor, team, settings); err != nil {
+		return nil, err
+	}
+	if err := database.DB.Save(team).Error; err != nil {
+		return nil, errors.New("修改团队失败")
+	}
+
+	s.log("team_updated", actor, team)
+	return team, nil
+}
+
+// Delete 删除团队及其成员关系，仍有下级团队时不能删除
+func (s *TeamService) Delete(actor *model.User, organizationID, teamID uint) error {
+	team, err := s.findManaged(actor, organizationID, teamID)
+	if err != nil {
+		return err
+	}
+
+	var children int64
+	database.DB.Model(&model.Team{}).Where("parent_id = ?", team.ID).Count(&children)
+	if children > 0 {
+		return ErrTeamHasChildren
+	}
+
+	// 物理删除，以便之后重新创建同名团队
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("team_id = ?", team.ID).Delete(&model.TeamMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(team).Error
+	})
+	if err != nil {
+		return errors.New("删除团队失败")
+	}
+
+	s.log("team_deleted", actor, team)
+	return nil
+}
+
+// Members 获取团队成员
+func (s *TeamService) Members(actor *model.User, organizationID, teamID uint) ([]TeamMemberInfo, error) {
+	team, err := s.Get(actor, organizationID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []model.TeamMember
+	if err := database.DB.Preload("User").Where("team_id = ?", team.ID).Order("id").Find(&members).Error; err != nil {
+		return nil, errors.New("获取团队成员失败")
+	}
+
+	infos := make([]TeamMemberInfo, 0, len(members))
+	for _, member := range members {
+		infos = append(infos, TeamMemberInfo{
+			UserID:   member.UserID,
+			Username: member.User.Username,
+			Email:    member.User.Email,
+			Lead:     member.Lead,
+			JoinedAt: member.CreatedAt,
+		})
+	}
+	return infos, nil
+}
+
+// SetMember 将用户加入团队或修改其是否为负责人
+// 用户必须属于团队所在的组织或已加入该组织；操作者需要拥有团队获得的全部权限
+func (s *TeamService) SetMember(actor *model.User, organizationID, teamID, userID uint, lead bool) (*model.TeamMember, error) {
+	team, err := s.Get(actor, organizationID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTeamGrantable(actor, team); err != nil {
+		return nil, err
+	}
+
+	var user model.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Role == model.RoleSuperAdmin || user.IsServiceAccount {
+		return nil, errors.New("超级管理员和服务账号不能加入团队")
+	}
+	if user.OrganizationID != organizationID {
+		var count int64
+		database.DB.Model(&model.Membership{}).Where("user_id = ? AND organization_id = ?", userID, organizationID).Count(&count)
+		if count == 0 {
+			return nil, ErrNotMember
+		}
+	}
+
+	var member model.TeamMember
+	err = database.DB.Where("team_id = ? AND user_id = ?", team.ID, userID).First(&member).Error
+	switch {
+	case err == nil:
+		if err := database.DB.Model(&member).Update("lead", lead).Error; err != nil {
+			return nil, errors.New("修改团队成员失败")
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		member = model.TeamMember{TeamID: team.ID, UserID: userID, Lead: lead}
+		if err := database.DB.Create(&member).Error; err != nil {
+			return nil, errors.New("加入团队失败")
+		}
+	default:
+		return nil, errors.New("加入团队失败")
+	}
+
+	s.logMember("team_member_set", actor, &member)
+	return &member, nil
+}
+
+// RemoveMember 将用户移出团队，用户随即失去团队获得的权限
+func (s *TeamService) RemoveMember(actor *model.User, organizationID, teamID, userID uint) error {
+	team, err := s.Get(actor, organizationID, teamID)
+	if err != nil {
+		return err
+	}
+
+	var member model.TeamMember
+	if err := database.DB.Where("team_id = ? AND user_id = ?", team.ID, userID).First(&member).Error; err != nil {
+		return ErrTeamMemberNotFound
+	}
+	if err := database.DB.Unscoped().Delete(&member).Error; err != nil {
+		return errors.New("移出团队失败")
+	}
+
+	s.logMember("team_member_removed", actor, &member)
+	return nil
+}
+
+// apply 校验并写入团队的可修改项
+// 上级团队必须属于同一组织且不能形成环；操作者需要拥有新的角色和上级团队获得的全部权限
+func (s *TeamService) apply(actor *model.User, team *model.Team, settings TeamSettings) error {
+	name := strings.TrimSpace(settings.Name)
+	if name == "" {
+		return errors.New("团队名称不能为空")
+	}
+	var count int64
+	database.DB.Model(&model.Team{}).
+		Where("organization_id = ? AND name = ? AND id <> ?", team.OrganizationID, name, team.ID).Count(&count)
+	if count > 0 {
+		return errors.New("团队名称已存在于此组织")
+	}
+
+	var roles model.StringList
+	for _, role := range settings.Roles {
+		role = strings.TrimSpace(role)
+		if roles.Contains(role) {
+			continue
+		}
+		if err := s.roleService.Assignable(actor, team.OrganizationID, role); err != nil {
+			return err
+		}
+		roles = append(roles, role)
+	}
+
+	var parentID *uint
+	if settings.ParentID != nil && *settings.ParentID != 0 {
+		teams, err := loadTeams(team.OrganizationID)
+		if err != nil {
+			return err
+		}
+		if _, ok := teams[*settings.ParentID]; !ok {
+			return errors.New("上级团队不存在")
+		}
+		// 从新的上级团队向上查找，遇到自己说明会形成环
+		for id := settings.ParentID; id != nil; id = teams[*id].ParentID {
+			if team.ID != 0 && *id == team.ID {
+				return errors.New("不能将团队移动到自己或下级团队之下")
+			}
+		}
+		if err := s.roleService.checkGrantable(actor, s.roleService.rolesPermissions(team.OrganizationID, inheritedRoles(teams, *settings.ParentID))); err != nil {
+			return err
+		}
+		parentID = settings.ParentID
+	}
+
+	team.Name = name
+	team.Description = strings.TrimSpace(settings.Description)
+	team.ParentID = parentID
+	team.Roles = roles
+	return nil
+}
+
+// find 查找组织的团队
+func (s *TeamService) find(organizationID, teamID uint) (*model.Team, error) {
+	var team model.Team
+	if err := database.DB.Where("id = ? AND organization_id = ?", teamID, organizationID).First(&team).Error; err != nil {
+		return nil, ErrTeamNotFound
+	}
+	return &team, nil
+}
+
+// findManaged 查找操作者可以修改的团队，团队获得的权限不能超出操作者
+func (s *TeamService) findManaged(actor *model.User, organizationID, teamID uint) (*model.Team, error) {
+	if !canAdministerOrganization(actor, organizationID) {
+		return nil, ErrForbidden
+	}
+	team, err := s.find(organizationID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if s.checkTeamGrantable(actor, team) != nil {
+		return nil, ErrForbidden
+	}
+	return team, nil
+}
+
+// checkTeamGrantable 检查操作者是否拥有团队成员获得的全部权限
+func (s *TeamService) checkTeamGrantable(actor *model.User, team *model.Team) error {
+	teams, err := loadTeams(team.OrganizationID)
+	if err != nil {
+		return err
+	}
+	return s.roleService.checkGrantable(actor, s.roleService.rolesPermissions(team.OrganizationID, inheritedRoles(teams, team.ID)))
+}
+
+func (s *TeamService) log(event string, actor *model.User, team *model.Team) {
+	fields := map[string]interface{}{
+		"event":           event,
+		"team_id":         team.ID,
+		"team":            team.Name,
+		"organization_id": team.OrganizationID,
+		"roles":           strings.Join(team.Roles, ","),
+		"actor_id":        actor.ID,
+	}
+	if team.ParentID != nil {
+		fields["parent_id"] = *team.ParentID
+	}
+	logger.WithFields(fields).Info("已修改组织团队")
+}
+
+func (s *TeamService) logMember(event string, actor *model.User, member *model.TeamMember) {
+	logger.WithFields(map[string]interface{}{
+		"event":    event,
+		"team_id":  member.TeamID,
+		"user_id":  member.UserID,
+		"lead":     member.Lead,
+		"actor_id": actor.ID,
+	}).Info("已修改团队成员")
+}
+
+// loadTeams 加载组织的全部团队，用于沿上级团队查找
+func loadTeams(organizationID uint) (map[uint]model.Team, error) {
+	var teams []model.Team
+	if err := database.DB.Where("organization_id = ?", organizationID).Find(&teams).Error; err != nil {
+		return nil, errors.New("获取团队失败")
+	}
+	byID := make(map[uint]model.Team, len(teams))
+	for _, team := range teams {
+		byID[team.ID] = team
+	}
+	return byID, nil
+}
+
+// inheritedRoles 获取团队及其全部上级团队被分配的角色
+func inheritedRoles(teams map[uint]model.Team, teamID uint) model.StringList {
+	var roles model.StringList
+	for id := &teamID; id != nil; {
+		team, ok := teams[*id]
+		if !ok {
+			break
+		}
+		for _, role := range team.Roles {
+			if !roles.Contains(role) {
+				roles = append(roles, role)
+			}
+		}
+		id = team.ParentID
+	}
+	return roles
+}
+
+// teamRoles 获取用户在组织中通过所在团队及其上级团队获得的角色
+func teamRoles(userID, organizationID uint) model.StringList {
+	var teamIDs []uint
+	database.DB.Model(&model.TeamMember{}).
+		Joins("JOIN teams ON teams.id = team_members.team_id AND teams.deleted_at IS NULL").
+		Where("team_members.user_id = ? AND teams.organization_id = ?", userID, organizationID).
+		Pluck("team_members.team_id", &teamIDs)
+	if len(teamIDs) == 0 {
+		return nil
+	}
+
+	teams, err := loadTeams(organizationID)
+	if err != nil {
+		return nil
+	}
+	var roles model.StringList
+	for _, teamID := range teamIDs {
+		for _, role := range inheritedRoles(teams, teamID) {
+			if !roles.Contains(role) {
+				roles = append(roles, role)
+			}
+		}
+	}
+	return roles
+}
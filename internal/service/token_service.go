@@ -0,0 +1,216 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/database"
+	"backend/pkg/jwt"
+	"backend/pkg/logger"
+	"backend/pkg/token"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("无效的刷新令牌")
+	ErrRefreshTokenReused  = errors.New("刷新令牌已被使用，该登录会话已失效")
+)
+
+// TokenPair 访问令牌与刷新令牌
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // 访问令牌有效期（秒）
+}
+
+type TokenService struct {
+	sessionService SessionService
+}
+
+// IssueTokenPair 为新的登录会话签发令牌对，并记录会话的客户端信息
+func (s *TokenService) IssueTokenPair(user *model.User, client ClientInfo) (*TokenPair, error) {
+	familyID, err := token.Generate(16)
+	if err != nil {
+		return nil, errors.New("生成token失败")
+	}
+
+	var pair *TokenPair
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var refreshToken *model.RefreshToken
+		var err error
+		pair, refreshToken, err = s.issue(tx, user, familyID)
+		if err != nil {
+			return err
+		}
+		return s.sessionService.Create(tx, user, familyID, refreshToken.ExpiresAt, client)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// Refresh 使用刷新令牌换取新的令牌对
+// 已轮换过的刷新令牌再次出现时视为泄露，撤销整个令牌族
+func (s *TokenService) Refresh(rawToken string, client ClientInfo) (*model.User, *TokenPair, error) {
+	var stored model.RefreshToken
+	if err := database.DB.Where("token_hash = ?", token.Hash(rawToken)).First(&stored).Error; err != nil {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	if stored.UsedAt != nil {
+		return nil, nil, s.reused(stored)
+	}
+
+	if stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	var user model.User
+	if err := database.DB.Preload("Organization").First(&user, stored.UserID).Error; err != nil {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	if err := CheckUserStatus(&user); err != nil {
+		return nil, nil, err
+	}
+
+	// 切换组织后的会话：继续使用加入的组织，成员关系已被移除时不能再刷新
+	var session model.Session
+	if err := database.DB.Where("family_id = ?", stored.FamilyID).First(&session).Error; err == nil {
+		if err := applyMembership(&user, session.OrganizationID); err != nil {
+			return nil, nil, ErrInvalidRefreshToken
+		}
+	}
+
+	var pair *TokenPair
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// 条件更新保证同一刷新令牌只能被轮换一次
+		now := time.Now()
+		result := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", stored.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRefreshTokenReused
+		}
+
+		var next *model.RefreshToken
+		var err error
+		pair, next, err = s.issue(tx, &user, stored.FamilyID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&model.RefreshToken{}).Where("id = ?", stored.ID).
+			Update("replaced_by_id", next.ID).Error; err != nil {
+			return err
+		}
+		return s.sessionService.Extend(tx, stored.FamilyID, next.ExpiresAt, client)
+	})
+	if err != nil {
+		if errors.Is(err, ErrRefreshTokenReused) {
+			// 同一刷新令牌被并发使用，与重复使用同样处理
+			return nil, nil, s.reused(stored)
+		}
+		return nil, nil, errors.New("刷新令牌失败")
+	}
+
+	return &user, pair, nil
+}
+
+// reused 刷新令牌被重复使用时撤销整个令牌族
+func (s *TokenService) reused(stored model.RefreshToken) error {
+	if err := s.RevokeFamily(stored.FamilyID); err != nil {
+		return err
+	}
+	logger.WithFields(map[string]interface{}{
+		"user_id":   stored.UserID,
+		"family_id": stored.FamilyID,
+	}).Warn("检测到刷新令牌重用，已撤销令牌族")
+	return ErrRefreshTokenReused
+}
+
+// RevokeFamily 撤销令牌族中所有未撤销的刷新令牌及对应的会话
+func (s *TokenService) RevokeFamily(familyID string) error {
+	now := time.Now()
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.RefreshToken{}).
+			Where("family_id = ? AND revoked_at IS NULL", familyID).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.Session{}).
+			Where("family_id = ? AND revoked_at IS NULL", familyID).
+			Update("revoked_at", now).Error
+	})
+	if err != nil {
+		return errors.New("撤销刷新令牌失败")
+	}
+	return nil
+}
+
+// RevokeByRefreshToken 撤销刷新令牌所在的令牌族（仅限令牌所属用户）
+func (s *TokenService) RevokeByRefreshToken(userID uint, rawToken string) error {
+	var stored model.RefreshToken
+	if err := database.DB.Where("token_hash = ? AND user_id = ?", token.Hash(rawToken), userID).
+		First(&stored).Error; err != nil {
+		return ErrInvalidRefreshToken
+	}
+	return s.RevokeFamily(stored.FamilyID)
+}
+
+// RevokeUserRefreshTokens 撤销用户所有未撤销的刷新令牌及会话
+func (s *TokenService) RevokeUserRefreshTokens(userID uint) error {
+	now := time.Now()
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", now).Error
+	})
+	if err != nil {
+		return errors.New("撤销刷新令牌失败")
+	}
+	return nil
+}
+
+// issue 签发访问令牌并在指定令牌族中持久化新的刷新令牌
+func (s *TokenService) issue(tx *gorm.DB, user *model.User, familyID string) (*TokenPair, *model.RefreshToken, error) {
+	accessToken, err := jwt.Sign(&jwt.CustomClaims{
+		UserID:         user.ID,
+		Username:       user.Username,
+		Role:           user.Role,
+		OrganizationID: user.OrganizationID,
+		SessionID:      familyID,
+	})
+	if err != nil {
+		return nil, nil, errors.New("生成token失败")
+	}
+
+	rawRefreshToken, err := token.Generate(32)
+	if err != nil {
+		return nil, nil, errors.New("生成token失败")
+	}
+
+	refreshToken := model.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: token.Hash(rawRefreshToken),
+		ExpiresAt: time.Now().Add(jwt.RefreshTokenExpireDuration),
+	}
+	if err := tx.Create(&refreshToken).Error; err != nil {
+		return nil, nil, errors.New("保存刷新令牌失败")
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: rawRefreshToken,
+		ExpiresIn:    int64(jwt.TokenExpireDuration.Seconds()),
+	}, &refreshToken, nil
+}
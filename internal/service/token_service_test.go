@@ -0,0 +1,194 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/database"
+	"backend/pkg/token"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var testClient = ClientInfo{IP: "127.0.0.1", UserAgent: "go-test"}
+
+// assertFamilyRevoked 检查令牌族中的刷新令牌和会话全部已撤销
+func assertFamilyRevoked(t *testing.T, rawToken string) {
+	t.Helper()
+	var stored model.RefreshToken
+	if err := database.DB.Where("token_hash = ?", token.Hash(rawToken)).First(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var active int64
+	database.DB.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", stored.FamilyID).Count(&active)
+	if active != 0 {
+		t.Errorf("%d refresh tokens in the family are still active", active)
+	}
+	database.DB.Model(&model.Session{}).
+		Where("family_id = ? AND revoked_at IS NULL", stored.FamilyID).Count(&active)
+	if active != 0 {
+		t.Errorf("%d sessions in the family are still active", active)
+	}
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	setupTestDB(t)
+	user := createTestUser(t, "acme", "alice")
+	service := &TokenService{}
+
+	first, err := service.IssueTokenPair(user, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refreshedUser, second, err := service.Refresh(first.RefreshToken, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshedUser.ID != user.ID {
+		t.Errorf("refreshed user = %d, want %d", refreshedUser.ID, user.ID)
+	}
+	if second.RefreshToken == first.RefreshToken || second.AccessToken == "" {
+		t.Error("refresh did not issue a new token pair")
+	}
+
+	var previous model.RefreshToken
+	database.DB.Where("token_hash = ?", token.Hash(first.RefreshToken)).First(&previous)
+	if previous.UsedAt == nil || previous.ReplacedByID == nil {
+		t.Errorf("rotated token not marked as used: %+v", previous)
+	}
+
+	// 新令牌仍可继续轮换
+	if _, _, err := service.Refresh(second.RefreshToken, testClient); err != nil {
+		t.Errorf("refresh with the rotated token failed: %v", err)
+	}
+}
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	setupTestDB(t)
+	user := createTestUser(t, "acme", "alice")
+	service := &TokenService{}
+
+	first, err := service.IssueTokenPair(user, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, second, err := service.Refresh(first.RefreshToken, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 另一次登录的令牌族不受影响
+	other, err := service.IssueTokenPair(user, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := service.Refresh(first.RefreshToken, testClient); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("reusing a rotated token: err = %v, want ErrRefreshTokenReused", err)
+	}
+	assertFamilyRevoked(t, first.RefreshToken)
+
+	if _, _, err := service.Refresh(second.RefreshToken, testClient); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("refresh after reuse: err = %v, want ErrInvalidRefreshToken", err)
+	}
+	if _, _, err := service.Refresh(other.RefreshToken, testClient); err != nil {
+		t.Errorf("unrelated session was revoked: %v", err)
+	}
+}
+
+// 两个请求同时读到未使用的令牌时，条件更新失败的一方也要撤销整个令牌族
+func TestRefreshConcurrentReuseRevokesFamily(t *testing.T) {
+	setupTestDB(t)
+	user := createTestUser(t, "acme", "alice")
+	service := &TokenService{}
+
+	pair, err := service.IssueTokenPair(user, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 在轮换的条件更新执行前，模拟另一个请求抢先使用了同一令牌
+	injected := false
+	err = database.DB.Callback().Update().Before("gorm:update").Register("test:concurrent_refresh", func(tx *gorm.DB) {
+		if injected || tx.Statement.Table != "refresh_tokens" {
+			return
+		}
+		injected = true
+		if err := database.DB.Model(&model.RefreshToken{}).
+			Where("token_hash = ?", token.Hash(pair.RefreshToken)).
+			UpdateColumn("used_at", time.Now()).Error; err != nil {
+			t.Error(err)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := service.Refresh(pair.RefreshToken, testClient); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("err = %v, want ErrRefreshTokenReused", err)
+	}
+	assertFamilyRevoked(t, pair.RefreshToken)
+
+	var count int64
+	database.DB.Model(&model.RefreshToken{}).Where("user_id = ?", user.ID).Count(&count)
+	if count != 1 {
+		t.Errorf("%d refresh tokens stored, want no token issued for the losing request", count)
+	}
+}
+
+func TestRefreshRejectsInvalidTokens(t *testing.T) {
+	setupTestDB(t)
+	user := createTestUser(t, "acme", "alice")
+	service := &TokenService{}
+
+	expired, err := service.IssueTokenPair(user, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.DB.Model(&model.RefreshToken{}).
+		Where("token_hash = ?", token.Hash(expired.RefreshToken)).
+		Update("expires_at", time.Now().Add(-time.Minute))
+
+	revoked, err := service.IssueTokenPair(user, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.RevokeByRefreshToken(user.ID, revoked.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"unknown", "not-a-refresh-token"},
+		{"expired", expired.RefreshToken},
+		{"revoked", revoked.RefreshToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := service.Refresh(tt.token, testClient); !errors.Is(err, ErrInvalidRefreshToken) {
+				t.Errorf("err = %v, want ErrInvalidRefreshToken", err)
+			}
+		})
+	}
+}
+
+func TestRefreshRejectsInactiveUser(t *testing.T) {
+	setupTestDB(t)
+	user := createTestUser(t, "acme", "alice")
+	service := &TokenService{}
+
+	pair, err := service.IssueTokenPair(user, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.DB.Model(user).Update("status", model.UserStatusSuspended)
+
+	if _, _, err := service.Refresh(pair.RefreshToken, testClient); !errors.Is(err, ErrAccountDisabled) {
+		t.Errorf("err = %v, want ErrAccountDisabled", err)
+	}
+}
@@ -0,0 +1,449 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/database"
+	"backend/pkg/hasher"
+	"backend/pkg/logger"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound       = errors.New("用户不存在")
+	ErrAccountDisabled    = errors.New("账号已被停用，请联系组织管理员")
+	ErrAccountLocked      = errors.New("账号已被锁定，请联系组织管理员")
+	ErrAccountDeactivated = errors.New("账号已注销")
+	ErrStatusTransition   = errors.New("不允许的账号状态变更")
+)
+
+// CreateUserInput 管理员创建用户的信息
+type CreateUserInput struct {
+	OrganizationID uint // 为空时为操作者所在的组织，其他管理员只能指定自己所在的组织或其下级组织
+	Username       string
+	Password       string
+	Email          string
+	Role           string // 为空时为组织成员
+}
+
+// UpdateUserInput 管理员修改用户资料，为 nil 的字段保持不变
+type UpdateUserInput struct {
+	Username *string
+	Email    *string
+}
+
+// UserQuery 用户列表的筛选和分页条件
+type UserQuery struct {
+	OrganizationID uint   // 为空时超级管理员查看全部组织，其他管理员查看自己所在的组织
+	Role           string // 按角色筛选
+	Status         string // 按账号状态筛选
+	Search         string // 按用户名或邮箱模糊搜索
+	Page           int    // 页码，从1开始
+	PageSize       int    // 每页数量，默认20，最大100
+}
+
+// UserPage 分页的用户列表
+type UserPage struct {
+	Items    []model.User `json:"items"`
+	Total    int64        `json:"total"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+}
+
+// UserService 按组织边界管理用户
+// 超级管理员可以管理所有组织的用户，其他管理员只能管理本组织及下级组织中权限不超过自己的用户
+type UserService struct {
+	passwordService   PasswordPolicyService
+	revocationService RevocationService
+	emailService      EmailVerificationService
+	roleService       RoleService
+}
+
+// List 分页获取用户，可以按组织、角色、状态筛选并按用户名或邮箱搜索
+// 超级管理员未指定组织时返回所有用户，其他管理员未指定组织时返回自己所在的组织，也可以指定下级组织
+func (s *UserService) List(actor *model.User, q UserQuery) (*UserPage, error) {
+	organizationID := q.OrganizationID
+	if actor.Role != model.RoleSuperAdmin {
+		if organizationID == 0 {
+			organizationID = actor.OrganizationID
+		}
+		if !canAdministerOrganization(actor, organizationID) {
+			return nil, ErrForbidden
+		}
+	}
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize < 1 {
+		q.PageSize = 20
+	}
+	if q.PageSize > 100 {
+		q.PageSize = 100
+	}
+
+	query := database.DB.Model(&model.User{})
+	if organizationID != 0 {
+		query = query.Where("organization_id = ?", organizationID)
+	}
+	if actor.Role != model.RoleSuperAdmin {
+		query = query.Where("role <> ?", model.RoleSuperAdmin)
+	}
+	if q.Role != "" {
+		query = query.Where("role = ?", q.Role)
+	}
+	if q.Status != "" {
+		query = query.Where("status = ?", q.Status)
+	}
+	if search := strings.ToLower(strings.TrimSpace(q.Search)); search != "" {
+		pattern := "%" + search + "%"
+		query = query.Where("LOWER(username) LIKE ? OR LOWER(email) LIKE ?", pattern, pattern)
+	}
+
+	page := UserPage{Items: []model.User{}, Page: q.Page, PageSize: q.PageSize}
+	if err := query.Count(&page.Total).Error; err != nil {
+		return nil, errors.New("获取用户列表失败")
+	}
+	if err := query.Order("id").Offset((q.Page - 1) * q.PageSize).Limit(q.PageSize).Find(&page.Items).Error; err != nil {
+		return nil, errors.New("获取用户列表失败")
+	}
+	return &page, nil
+}
+
+// Get 获取用户，非超级管理员只能查看本组织及下级组织的用户
+func (s *UserService) Get(actor *model.User, userID uint) (*model.User, error) {
+	var user model.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+	if actor.Role != model.RoleSuperAdmin &&
+		(user.Role == model.RoleSuperAdmin || !canAdministerOrganization(actor, user.OrganizationID)) {
+		return nil, ErrForbidden
+	}
+	return &user, nil
+}
+
+// Create 在组织内创建用户，密码按组织的密码策略校验
+func (s *UserService) Create(actor *model.User, input CreateUserInput) (*model.User, error) {
+	if actor.Role != model.RoleSuperAdmin {
+		if input.OrganizationID == 0 {
+			input.OrganizationID = actor.OrganizationID
+		}
+		if !canAdministerOrganization(actor, input.OrganizationID) {
+			return nil, ErrForbidden
+		}
+	}
+	if input.OrganizationID == 0 {
+		return nil, errors.New("请指定用户所属的组织")
+	}
+	if input.Role == "" {
+		input.Role = model.RoleOrgMember
+	}
+
+	var org model.Organization
+	if err := database.DB.First(&org, input.OrganizationID).Error; err != nil {
+		return nil, errors.New("组织不存在")
+	}
+	if org.Code == "system" {
+		return nil, errors.New("不能在系统组织中创建用户，请使用创建管理员接口")
+	}
+	if err := s.roleService.Assignable(actor, org.ID, input.Role); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user := model.User{
+		Username:          input.Username,
+		Email:             input.Email,
+		Role:              input.Role,
+		OrganizationID:    org.ID,
+		PasswordChangedAt: &now,
+	}
+	if err := user.CheckUnique(database.DB); err != nil {
+		return nil, err
+	}
+	if err := s.passwordService.Validate(&user, input.Password); err != nil {
+		return nil, err
+	}
+	hashedPassword, err := hasher.Hash(input.Password)
+	if err != nil {
+		return nil, errors.New("密码哈希失败")
+	}
+	user.Password = hashedPassword
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return s.passwordService.Record(tx, user.ID, user.Password)
+	})
+	if err != nil {
+		return nil, errors.New("创建用户失败")
+	}
+
+	user.Organization = org
+	s.emailService.Send(&user)
+	s.log("user_created", actor, &user, "")
+	return &user, nil
+}
+
+// Update 修改用户的用户名或邮箱，按与创建相同的规则检查组织内唯一
+// 修改邮箱后需要重新验证
+func (s *UserService) Update(actor *model.User, userID uint, input UpdateUserInput) (*model.User, error) {
+	user, err := findManagedUser(actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if input.Username != nil && *input.Username != user.Username {
+		user.Username = *input.Username
+		updates["username"] = user.Username
+	}
+	if input.Email != nil && *input.Email != user.Email {
+		user.Email = *input.Email
+		user.EmailVerifiedAt = nil
+		updates["email"] = user.Email
+		updates["email_verified_at"] = nil
+	}
+	if len(updates) == 0 {
+		return user, nil
+	}
+
+	if err := user.CheckUnique(database.DB); err != nil {
+		return nil, err
+	}
+	if err := database.DB.Model(user).Updates(updates).Error; err != nil {
+		return nil, errors.New("修改用户失败")
+	}
+	if _, changed := updates["email"]; changed {
+		s.emailService.Restart(user)
+	}
+
+	s.log("user_updated", actor, user, "")
+	return user, nil
+}
+
+// ChangeRole 修改用户的角色，修改后在下一个请求时即按新角色授权
+// 不能修改自己或超级管理员的角色，不能分配超出操作者权限的角色
+func (s *UserService) ChangeRole(actor *model.User, userID uint, role string) (*model.User, error) {
+	user, err := findManagedUser(actor, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == actor.ID {
+		return nil, errors.New("不能修改自己的角色")
+	}
+	if user.Role == model.RoleSuperAdmin {
+		return nil, errors.New("不能修改超级管理员的角色")
+	}
+	if user.IsServiceAccount {
+		return nil, errors.New("服务账号的角色请通过OAuth2客户端修改")
+	}
+	if role == user.Role {
+		return user, nil
+	}
+	if err := s.roleService.Assignable(actor, user.OrganizationID, role); err != nil {
+		return nil, err
+	}
+
+	if err := database.DB.Model(user).Update("role", role).Error; err != nil {
+		return nil, errors.New("修改用户角色失败")
+	}
+	user.Role = role
+
+	s.log("user_role_changed", actor, user, "")
+	return user, nil
+}
+
+// Delete 删除用户：已签发的令牌和会话立即失效，同时移除其成员关系、团队成员关系、外部身份关联、个人资料和 API Key
+// 删除后组织内可以重新使用该用户名和邮箱
+func (s *UserService) Delete(actor *model.User, userID uint) error {
+	user, err := findManagedUser(actor, userID)
+	if err != nil {
+		return err
+	}
+	if user.ID == actor.ID {
+		return errors.New("不能删除自己")
+	}
+	if user.IsServiceAccount {
+		return errors.New("服务账号随OAuth2客户端删除")
+	}
+
+	if err := s.revocationService.RevokeUserTokens(user.ID, "deleted"); err != nil {
+		return err
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		for _, related := range []interface{}{&model.Membership{}, &model.TeamMember{}, &model.UserIdentity{}, &model.UserProfile{}} {
+			if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(related).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&model.APIKey{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(user).Error
+	})
+	if err != nil {
+		return errors.New("删除用户失败")
+	}
+
+	s.log("user_deleted", actor, user, "")
+	return nil
+}
+
+// Disable 停用用户：用户无法再登录，已签发的令牌和会话立即失效
+func (s *UserService) Disable(actor *model.User, userID uint, reason string) (*model.User, error) {
+	return s.ChangeStatus(actor, userID, model.UserStatusSuspended, reason)
+}
+
+// Enable 重新启用被停用、锁定或注销的用户
+func (s *UserService) Enable(actor *model.User, userID uint) (*model.User, error) {
+	return s.ChangeStatus(actor, userID, model.UserStatusActive, "")
+}
+
+// ChangeStatus 按允许的状态变更修改用户的账号状态，原因和操作者记入状态变更记录
+// 变为正常以外的状态后用户无法登录，已签发的令牌、会话和 API Key 立即失效
+func (s *UserService) ChangeStatus(actor *model.User, userID uint, status, reason string) (*model.User, error) {
+	user, err := findManagedUser(actor, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == actor.ID {
+		return nil, errors.New("不能修改自己的账号状态")
+	}
+	if user.Status == model.UserStatusPending {
+		return nil, errors.New("待审核的注册请通过注册审核处理")
+	}
+
+	if err := changeUserStatus(actor, user, status, reason); err != nil {
+		return nil, err
+	}
+	if status != model.UserStatusActive {
+		if err := s.revocationService.RevokeUserTokens(user.ID, "status_"+status); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// StatusHistory 获取用户的账号状态变更记录，最近的在前
+func (s *UserService) StatusHistory(actor *model.User, userID uint) ([]model.UserStatusChange, error) {
+	user, err := findManagedUser(actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []model.UserStatusChange
+	if err := database.DB.Where("user_id = ?", user.ID).Order("id DESC").Find(&changes).Error; err != nil {
+		return nil, errors.New("获取状态变更记录失败")
+	}
+	return changes, nil
+}
+
+func (s *UserService) log(event string, actor, user *model.User, reason string) {
+	logger.WithFields(map[string]interface{}{
+		"event":           event,
+		"user_id":         user.ID,
+		"organization_id": user.OrganizationID,
+		"role":            user.Role,
+		"actor_id":        actor.ID,
+		"reason":          reason,
+	}).Info("管理员修改了用户")
+}
+
+// CheckUserStatus 检查账号状态是否允许登录和使用已签发的令牌、API Key
+func CheckUserStatus(user *model.User) error {
+	switch user.Status {
+	case model.UserStatusActive:
+		return nil
+	case model.UserStatusPending:
+		return ErrAccountPending
+	case model.UserStatusLocked:
+		return ErrAccountLocked
+	case model.UserStatusDeactivated:
+		return ErrAccountDeactivated
+	default:
+		return ErrAccountDisabled
+	}
+}
+
+// changeUserStatus 修改用户的账号状态并记录原因和操作者
+// 条件更新保证并发修改时只有一次生效
+func changeUserStatus(actor, user *model.User, status, reason string) error {
+	if !model.IsUserStatus(status) {
+		return errors.New("无效的账号状态")
+	}
+	from := user.Status
+	if !model.CanTransitionUserStatus(from, status) {
+		return ErrStatusTransition
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.User{}).
+			Where("id = ? AND status = ?", user.ID, from).
+			Update("status", status)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStatusTransition
+		}
+		return tx.Create(&model.UserStatusChange{
+			UserID:     user.ID,
+			FromStatus: from,
+			ToStatus:   status,
+			Reason:     reason,
+			ActorID:    actor.ID,
+		}).Error
+	})
+	if errors.Is(err, ErrStatusTransition) {
+		return err
+	}
+	if err != nil {
+		return errors.New("修改账号状态失败")
+	}
+	user.Status = status
+
+	logger.WithFields(map[string]interface{}{
+		"event":           "user_status_changed",
+		"user_id":         user.ID,
+		"organization_id": user.OrganizationID,
+		"from_status":     from,
+		"to_status":       status,
+		"actor_id":        actor.ID,
+		"reason":          reason,
+	}).Info("用户的账号状态已变更")
+	return nil
+}
+
+// canManageUser 检查操作者是否可以管理目标用户，所需的权限由路由校验
+// 超级管理员可以管理所有用户；其他用户只能管理本组织及下级组织的用户，且目标的权限不能超出自己，避免借此提升权限
+func canManageUser(actor, target *model.User) bool {
+	if actor.Role == model.RoleSuperAdmin {
+		return true
+	}
+	if target.Role == model.RoleSuperAdmin || !canAdministerOrganization(actor, target.OrganizationID) {
+		return false
+	}
+	if actor.ID == target.ID {
+		return true
+	}
+
+	roleService := &RoleService{}
+	return roleService.checkGrantable(actor, roleService.Permissions(target)) == nil
+}
+
+// findManagedUser 查找操作者可以管理的用户，用于按用户ID执行的管理操作
+func findManagedUser(actor *model.User, userID uint) (*model.User, error) {
+	var user model.User
+	if err := database.DB.Preload("Organization").First(&user, userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !canManageUser(actor, &user) {
+		return nil, ErrForbidden
+	}
+	return &user, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/database"
+	"errors"
+	"testing"
+)
+
+// addTestUser 在已有组织中创建指定角色的用户
+func addTestUser(t *testing.T, organizationID uint, username, role string) *model.User {
+	t.Helper()
+	user := model.User{
+		Username:       username,
+		Email:          username + "@example.com",
+		Role:           role,
+		Status:         model.UserStatusActive,
+		OrganizationID: organizationID,
+	}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return &user
+}
+
+// 组织管理员不能查看或管理其他组织的用户
+func TestUserAdministrationStaysInOrganization(t *testing.T) {
+	setupTestDB(t)
+	member := createTestUser(t, "acme", "alice")
+	admin := addTestUser(t, member.OrganizationID, "acme-admin", model.RoleOrgAdmin)
+	outsider := createTestUser(t, "globex", "bob")
+	root := createSuperAdmin(t, "root")
+	service := &UserService{}
+
+	page, err := service.List(admin, UserQuery{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if page.Total != 2 {
+		t.Errorf("listed %d users, want the 2 users of acme", page.Total)
+	}
+	for _, user := range page.Items {
+		if user.OrganizationID != member.OrganizationID {
+			t.Errorf("listed user %s of organization %d", user.Username, user.OrganizationID)
+		}
+	}
+	if _, err := service.List(admin, UserQuery{OrganizationID: outsider.OrganizationID}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("List other organization: err = %v, want ErrForbidden", err)
+	}
+	if _, err := service.Get(admin, member.ID); err != nil {
+		t.Errorf("Get own member: %v", err)
+	}
+
+	username := "renamed"
+	checks := map[string]func(target uint) error{
+		"Get": func(target uint) error {
+			_, err := service.Get(admin, target)
+			return err
+		},
+		"Update": func(target uint) error {
+			_, err := service.Update(admin, target, UpdateUserInput{Username: &username})
+			return err
+		},
+		"ChangeRole": func(target uint) error {
+			_, err := service.ChangeRole(admin, target, model.RoleOrgAdmin)
+			return err
+		},
+		"Disable": func(target uint) error {
+			_, err := service.Disable(admin, target, "")
+			return err
+		},
+		"Delete": func(target uint) error {
+			return service.Delete(admin, target)
+		},
+	}
+	for name, check := range checks {
+		for _, target := range []*model.User{outsider, root} {
+			if err := check(target.ID); !errors.Is(err, ErrForbidden) {
+				t.Errorf("%s %s: err = %v, want ErrForbidden", name, target.Username, err)
+			}
+		}
+	}
+
+	if _, err := service.Create(admin, CreateUserInput{
+		OrganizationID: outsider.OrganizationID,
+		Username:       "carol",
+		Password:       "Correct-Horse-9",
+		Email:          "carol@example.com",
+	}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Create in other organization: err = %v, want ErrForbidden", err)
+	}
+
+	var unchanged model.User
+	database.DB.First(&unchanged, outsider.ID)
+	if unchanged.Username != "bob" || unchanged.Role != model.RoleOrgMember || unchanged.Status != model.UserStatusActive {
+		t.Errorf("user of other organization was modified: %+v", unchanged)
+	}
+}
+
+// 自定义角色只能管理权限不超过自己的用户，也不能授予自己没有的角色
+func TestUserAdministrationWithinOwnPermissions(t *testing.T) {
+	setupTestDB(t)
+	member := createTestUser(t, "acme", "alice")
+	admin := addTestUser(t, member.OrganizationID, "acme-admin", model.RoleOrgAdmin)
+
+	permissions, _ := model.BuiltinRolePermissions(model.RoleOrgMember)
+	role := model.Role{OrganizationID: member.OrganizationID, Name: "helpdesk",
+		Permissions: append(model.StringList{model.PermissionUserRead, model.PermissionUserUpdate}, permissions...)}
+	if err := database.DB.Create(&role).Error; err != nil {
+		t.Fatal(err)
+	}
+	helpdesk := addTestUser(t, member.OrganizationID, "helpdesk", role.Name)
+	service := &UserService{}
+
+	username := "alice2"
+	if _, err := service.Update(helpdesk, member.ID, UpdateUserInput{Username: &username}); err != nil {
+		t.Errorf("Update member: %v", err)
+	}
+	if _, err := service.Update(helpdesk, admin.ID, UpdateUserInput{Username: &username}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Update organization admin: err = %v, want ErrForbidden", err)
+	}
+	if _, err := service.ChangeRole(helpdesk, member.ID, model.RoleOrgAdmin); err == nil {
+		t.Error("granted organization admin without holding its permissions")
+	}
+	if _, err := service.ChangeRole(helpdesk, member.ID, model.RoleSuperAdmin); err == nil {
+		t.Error("granted super admin")
+	}
+	if _, err := service.ChangeRole(admin, helpdesk.ID, model.RoleOrgMember); err != nil {
+		t.Errorf("organization admin changing a custom role: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/pkg/database"
+	"backend/pkg/token"
+	"errors"
+	"time"
+)
+
+// ErrInvalidUserToken 令牌不存在、已使用或已过期
+var ErrInvalidUserToken = errors.New("链接无效或已过期")
+
+// UserTokenService 管理通过邮件发送的一次性令牌
+type UserTokenService struct{}
+
+// Issue 为用户签发指定用途的令牌，同一用途之前未使用的令牌全部作废
+func (s *UserTokenService) Issue(userID uint, purpose string, ttl time.Duration) (string, error) {
+	raw, err := token.Generate(32)
+	if err != nil {
+		return "", errors.New("生成令牌失败")
+	}
+
+	if err := s.Revoke(userID, purpose); err != nil {
+		return "", err
+	}
+	record := model.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: token.Hash(raw),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := database.DB.Create(&record).Error; err != nil {
+		return "", errors.New("保存令牌失败")
+	}
+	return raw, nil
+}
+
+// RecentlyIssued 检查用户在 interval 内是否已签发过指定用途的令牌，用于限制邮件发送频率
+func (s *UserTokenService) RecentlyIssued(userID uint, purpose string, interval time.Duration) bool {
+	var count int64
+	database.DB.Model(&model.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND created_at > ?", userID, purpose, time.Now().Add(-interval)).
+		Count(&count)
+	return count > 0
+}
+
+// Lookup 查找有效的令牌，不会将其标记为已使用
+func (s *UserTokenService) Lookup(raw, purpose string) (*model.UserToken, error) {
+	var record model.UserToken
+	err := database.DB.Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?",
+		token.Hash(raw), purpose, time.Now()).First(&record).Error
+	if err != nil {
+		return nil, ErrInvalidUserToken
+	}
+	return &record, nil
+}
+
+// Consume 将令牌标记为已使用，并发请求中只有一个能成功
+func (s *UserTokenService) Consume(record *model.UserToken) error {
+	now := time.Now()
+	result := database.DB.Model(&model.UserToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", record.ID, now).
+		Update("used_at", now)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return ErrInvalidUserToken
+	}
+	record.UsedAt = &now
+	return nil
+}
+
+// Revoke 作废用户指定用途的全部未使用令牌，同时清理过期的令牌
+func (s *UserTokenService) Revoke(userID uint, purpose string) error {
+	err := database.DB.Unscoped().
+		Where("user_id = ? AND purpose = ? AND (used_at IS NULL OR expires_at < ?)", userID, purpose, time.Now()).
+		Delete(&model.UserToken{}).Error
+	if err != nil {
+		return errors.New("作废令牌失败")
+	}
+	return nil
+}
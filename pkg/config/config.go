@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -34,3 +36,18 @@ func GetInt(key string) int {
 func GetBool(key string) bool {
 	return Config.GetBool(key)
 }
+
+// GetDuration 获取时间间隔配置
+func GetDuration(key string) time.Duration {
+	return Config.GetDuration(key)
+}
+
+// GetStringSlice 获取字符串列表配置
+func GetStringSlice(key string) []string {
+	return Config.GetStringSlice(key)
+}
+
+// UnmarshalKey 将配置项解析到结构体
+func UnmarshalKey(key string, rawVal interface{}) error {
+	return Config.UnmarshalKey(key, rawVal)
+}
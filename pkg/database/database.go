@@ -3,13 +3,13 @@ package database
 import (
 	"backend/internal/model"
 	"backend/pkg/config"
+	"backend/pkg/hasher"
 	"context"
 	"fmt"
 	"log"
 	"os"
 	"time"
 
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
@@ -90,12 +90,8 @@ func Init() error {
 		return fmt.Errorf("连接数据库失败: %v", err)
 	}
 
-	// 自动迁移数据库结构
-	if err := DB.AutoMigrate(
-		&model.User{},
-		&model.Organization{},
-	); err != nil {
-		return fmt.Errorf("数据库自动迁移失败: %v", err)
+	if err := Migrate(); err != nil {
+		return err
 	}
 
 	// 初始化超级管理员账号
@@ -132,6 +128,40 @@ func connectSQLite(gormConfig *gorm.Config) (*gorm.DB, error) {
 	return gorm.Open(sqlite.Open(config.GetString("database.sqlite.database")), gormConfig)
 }
 
+// Migrate 自动迁移数据库结构
+func Migrate() error {
+	if err := DB.AutoMigrate(
+		&model.User{},
+		&model.Organization{},
+		&model.RefreshToken{},
+		&model.RevokedToken{},
+		&model.UserMFA{},
+		&model.MFARecoveryCode{},
+		&model.MFAChallenge{},
+		&model.APIKey{},
+		&model.OAuthClient{},
+		&model.OIDCProvider{},
+		&model.UserIdentity{},
+		&model.OIDCLoginState{},
+		&model.LDAPConfig{},
+		&model.LoginThrottle{},
+		&model.PasswordPolicy{},
+		&model.PasswordHistory{},
+		&model.UserToken{},
+		&model.Session{},
+		&model.Invitation{},
+		&model.Role{},
+		&model.Membership{},
+		&model.Team{},
+		&model.TeamMember{},
+		&model.UserProfile{},
+		&model.UserStatusChange{},
+	); err != nil {
+		return fmt.Errorf("数据库自动迁移失败: %v", err)
+	}
+	return nil
+}
+
 // initSuperAdmin 初始化超级管理员账号
 func initSuperAdmin() error {
 	var count int64
@@ -160,7 +190,7 @@ func initSuperAdmin() error {
 	}
 
 	// 生成密码哈希
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(defaultPassword), bcrypt.DefaultCost)
+	hashedPassword, err := hasher.Hash(defaultPassword)
 	if err != nil {
 		return fmt.Errorf("密码加密失败: %w", err)
 	}
@@ -168,7 +198,7 @@ func initSuperAdmin() error {
 	// 创建超级管理员账号
 	admin := &model.User{
 		Username:       "admin",
-		Password:       string(hashedPassword),
+		Password:       hashedPassword,
 		Role:           "super_admin",
 		OrganizationID: org.ID,
 	}
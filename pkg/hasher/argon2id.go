@@ -0,0 +1,118 @@
+package hasher
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const argon2idPrefix = "$argon2id$"
+
+// Argon2id argon2id 算法，哈希以 PHC 字符串格式编码：
+// $argon2id$v=19$m=<内存KiB>,t=<迭代次数>,p=<并行度>$<盐>$<哈希>
+type Argon2id struct {
+	Memory      uint32 // 内存开销（KiB）
+	Iterations  uint32 // 迭代次数
+	Parallelism uint8  // 并行度
+	SaltLength  uint32 // 盐长度（字节）
+	KeyLength   uint32 // 哈希长度（字节）
+}
+
+// DefaultArgon2id 使用 OWASP 推荐的最低参数
+func DefaultArgon2id() *Argon2id {
+	return &Argon2id{
+		Memory:      19456,
+		Iterations:  2,
+		Parallelism: 1,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+// argon2idHash 解析后的 argon2id 编码哈希
+type argon2idHash struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+// Hash 使用随机盐计算密码哈希
+func (a *Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, a.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, a.Iterations, a.Memory, a.Parallelism, a.KeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		a.Memory, a.Iterations, a.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify 使用编码中的参数重新计算哈希并以常量时间比较
+func (a *Argon2id) Verify(password, encoded string) (bool, error) {
+	parsed, err := parseArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	key := argon2.IDKey([]byte(password), parsed.salt, parsed.iterations, parsed.memory, parsed.parallelism, uint32(len(parsed.key)))
+	return subtle.ConstantTimeCompare(key, parsed.key) == 1, nil
+}
+
+// Identify 检查是否为 argon2id 编码哈希
+func (a *Argon2id) Identify(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2idPrefix)
+}
+
+// Outdated 任一参数弱于当前配置时返回 true
+func (a *Argon2id) Outdated(encoded string) bool {
+	parsed, err := parseArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return parsed.memory < a.Memory ||
+		parsed.iterations < a.Iterations ||
+		parsed.parallelism < a.Parallelism ||
+		uint32(len(parsed.salt)) < a.SaltLength ||
+		uint32(len(parsed.key)) < a.KeyLength
+}
+
+// parseArgon2id 解析 PHC 格式的 argon2id 编码哈希
+func parseArgon2id(encoded string) (*argon2idHash, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, ErrUnknownFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, ErrUnknownFormat
+	}
+	if version != argon2.Version {
+		return nil, fmt.Errorf("不支持的 argon2 版本: %d", version)
+	}
+
+	var parsed argon2idHash
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &parsed.memory, &parsed.iterations, &parsed.parallelism); err != nil {
+		return nil, ErrUnknownFormat
+	}
+	if parsed.iterations == 0 || parsed.parallelism == 0 {
+		return nil, ErrUnknownFormat
+	}
+
+	var err error
+	if parsed.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, ErrUnknownFormat
+	}
+	if parsed.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(parsed.key) == 0 {
+		return nil, ErrUnknownFormat
+	}
+	return &parsed, nil
+}
@@ -0,0 +1,60 @@
+package hasher
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Bcrypt bcrypt 算法，哈希使用标准的 $2a$<成本>$<盐和哈希> 格式编码
+// bcrypt 只使用密码的前72个字节
+type Bcrypt struct {
+	Cost int // 计算成本，取值范围 4-31
+}
+
+// DefaultBcrypt 使用 bcrypt 默认成本
+func DefaultBcrypt() *Bcrypt {
+	return &Bcrypt{Cost: bcrypt.DefaultCost}
+}
+
+// Hash 计算密码哈希
+func (b *Bcrypt) Hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), b.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// Verify 校验密码，成本从编码中读取
+func (b *Bcrypt) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// Identify 检查是否为 bcrypt 编码哈希
+func (b *Bcrypt) Identify(encoded string) bool {
+	_, err := bcrypt.Cost([]byte(encoded))
+	return err == nil
+}
+
+// Outdated 成本低于当前配置时返回 true
+func (b *Bcrypt) Outdated(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost < b.Cost
+}
+
+func (b *Bcrypt) validate() error {
+	if b.Cost < bcrypt.MinCost || b.Cost > bcrypt.MaxCost {
+		return fmt.Errorf("bcrypt 成本必须在 %d 到 %d 之间", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	return nil
+}
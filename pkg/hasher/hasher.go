@@ -0,0 +1,88 @@
+package hasher
+
+import (
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownFormat 无法识别的密码哈希格式
+var ErrUnknownFormat = errors.New("无法识别的密码哈希格式")
+
+// Hasher 密码哈希算法，哈希结果以自描述的格式编码算法和参数
+type Hasher interface {
+	// Hash 计算密码的编码哈希
+	Hash(password string) (string, error)
+	// Verify 校验密码与编码哈希是否匹配，哈希参数从编码中读取
+	Verify(password, encoded string) (bool, error)
+	// Identify 检查编码哈希是否由该算法生成
+	Identify(encoded string) bool
+	// Outdated 检查编码哈希的参数是否弱于当前配置
+	Outdated(encoded string) bool
+}
+
+// Default 用于计算新密码哈希的算法，未初始化时使用默认参数的 argon2id
+var Default Hasher = DefaultArgon2id()
+
+// Init 按 security.password_hash 配置初始化密码哈希算法
+func Init() error {
+	switch algorithm := config.GetString("security.password_hash.algorithm"); algorithm {
+	case "", "argon2id":
+		argon := DefaultArgon2id()
+		if memory := config.GetInt("security.password_hash.argon2id.memory"); memory > 0 {
+			argon.Memory = uint32(memory)
+		}
+		if iterations := config.GetInt("security.password_hash.argon2id.iterations"); iterations > 0 {
+			argon.Iterations = uint32(iterations)
+		}
+		if parallelism := config.GetInt("security.password_hash.argon2id.parallelism"); parallelism > 0 {
+			if parallelism > 255 {
+				return fmt.Errorf("argon2id 并行度不能超过255")
+			}
+			argon.Parallelism = uint8(parallelism)
+		}
+		Default = argon
+	case "bcrypt":
+		bcryptHasher := DefaultBcrypt()
+		if cost := config.GetInt("security.password_hash.bcrypt.cost"); cost > 0 {
+			bcryptHasher.Cost = cost
+		}
+		if err := bcryptHasher.validate(); err != nil {
+			return err
+		}
+		Default = bcryptHasher
+	default:
+		return fmt.Errorf("不支持的密码哈希算法: %s", algorithm)
+	}
+	return nil
+}
+
+// Hash 使用当前配置的算法计算密码哈希
+func Hash(password string) (string, error) {
+	return Default.Hash(password)
+}
+
+// Verify 按编码哈希的算法校验密码，支持所有已知算法生成的哈希
+func Verify(password, encoded string) (bool, error) {
+	for _, hasher := range []Hasher{&Argon2id{}, &Bcrypt{}} {
+		if hasher.Identify(encoded) {
+			return hasher.Verify(password, encoded)
+		}
+	}
+	return false, ErrUnknownFormat
+}
+
+// Matches 校验密码是否匹配，哈希格式无法识别时视为不匹配
+func Matches(password, encoded string) bool {
+	ok, err := Verify(password, encoded)
+	return err == nil && ok
+}
+
+// NeedsRehash 检查编码哈希是否应使用当前配置重新计算
+// 算法与当前配置不同或参数弱于当前配置时返回 true
+func NeedsRehash(encoded string) bool {
+	if !Default.Identify(encoded) {
+		return true
+	}
+	return Default.Outdated(encoded)
+}
@@ -0,0 +1,219 @@
+package hasher
+
+import (
+	"backend/pkg/config"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// 测试使用较小的参数以加快运行
+func fastArgon2id() *Argon2id {
+	return &Argon2id{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+}
+
+func useDefault(t *testing.T, h Hasher) {
+	t.Helper()
+	previous := Default
+	Default = h
+	t.Cleanup(func() { Default = previous })
+}
+
+func TestHashAndVerify(t *testing.T) {
+	for name, h := range map[string]Hasher{
+		"argon2id": fastArgon2id(),
+		"bcrypt":   &Bcrypt{Cost: 4},
+	} {
+		t.Run(name, func(t *testing.T) {
+			useDefault(t, h)
+
+			encoded, err := Hash("correct horse")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !h.Identify(encoded) {
+				t.Errorf("Identify(%q) = false", encoded)
+			}
+			if !Matches("correct horse", encoded) {
+				t.Error("correct password does not match")
+			}
+			if Matches("wrong horse", encoded) {
+				t.Error("wrong password matches")
+			}
+			if NeedsRehash(encoded) {
+				t.Error("hash with current parameters needs rehash")
+			}
+		})
+	}
+}
+
+func TestArgon2idUsesRandomSalt(t *testing.T) {
+	h := fastArgon2id()
+	first, err := h.Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := h.Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Error("two hashes of the same password are identical")
+	}
+}
+
+func TestVerifyUnknownFormat(t *testing.T) {
+	for _, encoded := range []string{
+		"",
+		"plaintext",
+		"5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8",
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdA",
+		"$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=1$!!!$a2V5",
+	} {
+		if _, err := Verify("secret", encoded); err == nil {
+			t.Errorf("Verify(%q) returned no error", encoded)
+		}
+		if Matches("secret", encoded) {
+			t.Errorf("Matches(%q) = true", encoded)
+		}
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	weakArgon, err := (&Argon2id{Memory: 512, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}).Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	currentArgon, err := fastArgon2id().Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	weakBcrypt, err := (&Bcrypt{Cost: 4}).Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	currentBcrypt, err := (&Bcrypt{Cost: 5}).Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		current     Hasher
+		encoded     string
+		wantRehash  bool
+		wantMatches bool
+	}{
+		{"argon2id current parameters", fastArgon2id(), currentArgon, false, true},
+		{"argon2id weaker memory", fastArgon2id(), weakArgon, true, true},
+		{"bcrypt to argon2id", fastArgon2id(), currentBcrypt, true, true},
+		{"bcrypt current cost", &Bcrypt{Cost: 5}, currentBcrypt, false, true},
+		{"bcrypt lower cost", &Bcrypt{Cost: 5}, weakBcrypt, true, true},
+		{"argon2id to bcrypt", &Bcrypt{Cost: 5}, currentArgon, true, true},
+		{"unknown format", fastArgon2id(), "plaintext", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useDefault(t, tt.current)
+			if got := NeedsRehash(tt.encoded); got != tt.wantRehash {
+				t.Errorf("NeedsRehash = %v, want %v", got, tt.wantRehash)
+			}
+			// 升级前的旧哈希在切换算法或参数后仍能校验
+			if got := Matches("secret", tt.encoded); got != tt.wantMatches {
+				t.Errorf("Matches = %v, want %v", got, tt.wantMatches)
+			}
+		})
+	}
+}
+
+func TestInit(t *testing.T) {
+	previousConfig := config.Config
+	t.Cleanup(func() { config.Config = previousConfig })
+	useDefault(t, Default)
+
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		check    func(t *testing.T, h Hasher)
+		wantErr  string
+	}{
+		{
+			name:     "default argon2id",
+			settings: map[string]interface{}{},
+			check: func(t *testing.T, h Hasher) {
+				if a, ok := h.(*Argon2id); !ok || *a != *DefaultArgon2id() {
+					t.Errorf("Default = %#v, want default argon2id", h)
+				}
+			},
+		},
+		{
+			name: "argon2id parameters",
+			settings: map[string]interface{}{
+				"security.password_hash.algorithm":            "argon2id",
+				"security.password_hash.argon2id.memory":      65536,
+				"security.password_hash.argon2id.iterations":  3,
+				"security.password_hash.argon2id.parallelism": 4,
+			},
+			check: func(t *testing.T, h Hasher) {
+				a, ok := h.(*Argon2id)
+				if !ok || a.Memory != 65536 || a.Iterations != 3 || a.Parallelism != 4 {
+					t.Errorf("Default = %#v", h)
+				}
+			},
+		},
+		{
+			name: "bcrypt cost",
+			settings: map[string]interface{}{
+				"security.password_hash.algorithm":   "bcrypt",
+				"security.password_hash.bcrypt.cost": 12,
+			},
+			check: func(t *testing.T, h Hasher) {
+				if b, ok := h.(*Bcrypt); !ok || b.Cost != 12 {
+					t.Errorf("Default = %#v", h)
+				}
+			},
+		},
+		{
+			name: "bcrypt cost out of range",
+			settings: map[string]interface{}{
+				"security.password_hash.algorithm":   "bcrypt",
+				"security.password_hash.bcrypt.cost": 32,
+			},
+			wantErr: "bcrypt 成本",
+		},
+		{
+			name: "argon2id parallelism too large",
+			settings: map[string]interface{}{
+				"security.password_hash.argon2id.parallelism": 256,
+			},
+			wantErr: "并行度",
+		},
+		{
+			name:     "unknown algorithm",
+			settings: map[string]interface{}{"security.password_hash.algorithm": "md5"},
+			wantErr:  "不支持的密码哈希算法",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.Config = viper.New()
+			for key, value := range tt.settings {
+				config.Config.Set(key, value)
+			}
+
+			err := Init()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Init() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, Default)
+		})
+	}
+}
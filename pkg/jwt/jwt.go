@@ -1,20 +1,30 @@
 package jwt
 
 import (
+	"backend/pkg/config"
+	"backend/pkg/logger"
+	"backend/pkg/token"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 var (
-	// 密钥，实际应用中应该从配置文件读取
-	secretKey = []byte("windz-secret-key")
+	// Issuer 令牌签发者
+	Issuer = "windz"
 
-	// TokenExpireDuration token 过期时间
-	TokenExpireDuration = time.Hour * 24
+	// TokenExpireDuration 访问令牌过期时间
+	TokenExpireDuration = time.Minute * 15
+
+	// RefreshTokenExpireDuration 刷新令牌过期时间
+	RefreshTokenExpireDuration = time.Hour * 24 * 30
 
 	ErrInvalidToken = errors.New("token不合法")
+
+	// NewNumericDate 将时间转换为令牌中的时间声明，用于指定自定义的过期时间
+	NewNumericDate = jwt.NewNumericDate
 )
 
 // CustomClaims 自定义声明
@@ -23,35 +33,108 @@ type CustomClaims struct {
 	Username       string `json:"username"`
 	Role           string `json:"role"`
 	OrganizationID uint   `json:"organization_id"`
+	ClientID       string `json:"client_id,omitempty"` // OAuth2 客户端ID，仅客户端凭证令牌包含
+	Scope          string `json:"scope,omitempty"`     // 以空格分隔的访问范围，为空表示不受限制
+	SessionID      string `json:"sid,omitempty"`       // 登录会话ID，仅用户登录签发的令牌包含
+	Actor          *Actor `json:"act,omitempty"`       // 实际操作者，仅模拟登录令牌包含
 	jwt.RegisteredClaims
 }
 
-// GenerateToken 生成 JWT token
-func GenerateToken(userID uint, username string, role string, organizationID uint) (string, error) {
-	claims := CustomClaims{
-		UserID:         userID,
-		Username:       username,
-		Role:           role,
-		OrganizationID: organizationID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenExpireDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-		},
+// Actor 代表令牌主体进行操作的用户（RFC 8693 act 声明）
+type Actor struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// Init 从配置文件加载令牌有效期和签名密钥
+func Init() error {
+	if issuer := config.GetString("jwt.issuer"); issuer != "" {
+		Issuer = issuer
+	}
+	if ttl := config.GetDuration("jwt.access_token_ttl"); ttl > 0 {
+		TokenExpireDuration = ttl
+	}
+	if ttl := config.GetDuration("jwt.refresh_token_ttl"); ttl > 0 {
+		RefreshTokenExpireDuration = ttl
+	}
+
+	var keyConfigs []KeyConfig
+	if err := config.UnmarshalKey("jwt.keys", &keyConfigs); err != nil {
+		return fmt.Errorf("解析签名密钥配置失败: %w", err)
+	}
+
+	// 未配置密钥时使用临时密钥，重启后已签发的令牌全部失效
+	if len(keyConfigs) == 0 {
+		logger.Warn("未配置JWT签名密钥，使用临时生成的Ed25519密钥，仅适用于开发环境")
+		key, err := GenerateEphemeralKey()
+		if err != nil {
+			return fmt.Errorf("生成临时签名密钥失败: %w", err)
+		}
+		return SetKeys([]*Key{key}, key.ID)
+	}
+
+	keys := make([]*Key, 0, len(keyConfigs))
+	for _, keyConfig := range keyConfigs {
+		key, err := LoadKey(keyConfig)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	activeKeyID := config.GetString("jwt.active_key_id")
+	if activeKeyID == "" {
+		activeKeyID = keys[0].ID
+	}
+	return SetKeys(keys, activeKeyID)
+}
+
+// Sign 补全令牌ID、签发者和有效期等标准声明后签名
+// 未设置过期时间时使用 TokenExpireDuration
+func Sign(claims *CustomClaims) (string, error) {
+	// 生成唯一的令牌ID（jti），用于撤销单个令牌
+	jti, err := token.Generate(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims.ID = jti
+	claims.Issuer = Issuer
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.NotBefore = jwt.NewNumericDate(now)
+	if claims.ExpiresAt == nil {
+		claims.ExpiresAt = jwt.NewNumericDate(now.Add(TokenExpireDuration))
+	}
+
+	key, err := signingKey()
+	if err != nil {
+		return "", err
 	}
 
-	// 使用指定的签名方法创建签名对象
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	// 使用指定的secret签名并获得完整的编码后的字符串token
-	return token.SignedString(secretKey)
+	// 使用当前签名密钥的算法创建签名对象，并在头部标明密钥ID
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	// 使用私钥签名并获得完整的编码后的字符串token
+	return token.SignedString(key.PrivateKey)
 }
 
 // ParseToken 解析 JWT token
 func ParseToken(tokenString string) (*CustomClaims, error) {
 	// 解析token
 	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return secretKey, nil
-	})
+		// 根据头部的kid选择验证密钥，并确认算法与密钥一致
+		kid, _ := token.Header["kid"].(string)
+		key, ok := verificationKey(kid)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, ErrInvalidToken
+		}
+		return key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(Issuer))
 
 	if err != nil {
 		return nil, err
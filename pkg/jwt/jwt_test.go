@@ -0,0 +1,331 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	rsaOnce sync.Once
+	rsaKey  *rsa.PrivateKey
+)
+
+// testRSAKey 生成RSA密钥较慢，测试间共用同一个
+func testRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	rsaOnce.Do(func() {
+		var err error
+		if rsaKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			panic(err)
+		}
+	})
+	return rsaKey
+}
+
+func newRSAKey(t *testing.T, id string) *Key {
+	t.Helper()
+	private := testRSAKey(t)
+	return &Key{ID: id, Method: jwt.SigningMethodRS256, PrivateKey: private, PublicKey: &private.PublicKey}
+}
+
+func newEd25519Key(t *testing.T, id string) *Key {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Key{ID: id, Method: jwt.SigningMethodEdDSA, PrivateKey: private, PublicKey: public}
+}
+
+func setKeys(t *testing.T, keys []*Key, activeKeyID string) {
+	t.Helper()
+	if err := SetKeys(keys, activeKeyID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func headerKid(t *testing.T, tokenString string) string {
+	t.Helper()
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &CustomClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kid, _ := token.Header["kid"].(string)
+	return kid
+}
+
+// signUser 按登录时签发访问令牌的方式签名用户令牌
+func signUser(t *testing.T, userID uint, username, role string, organizationID uint) string {
+	t.Helper()
+	signed, err := Sign(&CustomClaims{
+		UserID:         userID,
+		Username:       username,
+		Role:           role,
+		OrganizationID: organizationID,
+		SessionID:      "session",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestSignAndParse(t *testing.T) {
+	for _, key := range []*Key{newRSAKey(t, "rsa"), newEd25519Key(t, "ed")} {
+		t.Run(key.Method.Alg(), func(t *testing.T) {
+			setKeys(t, []*Key{key}, key.ID)
+
+			signed := signUser(t, 7, "alice", "org_admin", 3)
+			if kid := headerKid(t, signed); kid != key.ID {
+				t.Errorf("kid = %q, want %q", kid, key.ID)
+			}
+
+			claims, err := ParseToken(signed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims.UserID != 7 || claims.Username != "alice" || claims.Role != "org_admin" || claims.OrganizationID != 3 || claims.SessionID != "session" {
+				t.Errorf("unexpected claims: %+v", claims)
+			}
+			if claims.ID == "" || claims.Issuer != Issuer || claims.ExpiresAt == nil {
+				t.Errorf("missing registered claims: %+v", claims.RegisteredClaims)
+			}
+		})
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKey := newEd25519Key(t, "2026-09")
+	newKey := newRSAKey(t, "2026-10")
+
+	setKeys(t, []*Key{oldKey}, oldKey.ID)
+	oldToken := signUser(t, 1, "alice", "org_member", 1)
+
+	// 切换签名密钥后，旧密钥仍可验证过渡期内签发的令牌
+	setKeys(t, []*Key{oldKey, newKey}, newKey.ID)
+	newToken := signUser(t, 1, "alice", "org_member", 1)
+	if kid := headerKid(t, newToken); kid != newKey.ID {
+		t.Errorf("new token kid = %q, want %q", kid, newKey.ID)
+	}
+	if _, err := ParseToken(oldToken); err != nil {
+		t.Errorf("token signed with the previous key rejected: %v", err)
+	}
+	if _, err := ParseToken(newToken); err != nil {
+		t.Errorf("token signed with the active key rejected: %v", err)
+	}
+
+	// 移除旧密钥后，旧令牌不再有效
+	setKeys(t, []*Key{newKey}, newKey.ID)
+	if _, err := ParseToken(oldToken); err == nil {
+		t.Error("token signed with a removed key accepted")
+	}
+	if _, err := ParseToken(newToken); err != nil {
+		t.Errorf("token signed with the active key rejected: %v", err)
+	}
+}
+
+func TestParseRejectsKidAndAlgorithmMismatch(t *testing.T) {
+	rsaKey := newRSAKey(t, "rsa")
+	edKey := newEd25519Key(t, "ed")
+	otherEdKey := newEd25519Key(t, "ed")
+	setKeys(t, []*Key{rsaKey, edKey}, edKey.ID)
+
+	claims := func() *CustomClaims {
+		now := time.Now()
+		return &CustomClaims{
+			UserID: 1,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    Issuer,
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			},
+		}
+	}
+	sign := func(method jwt.SigningMethod, kid interface{}, key interface{}) string {
+		token := jwt.NewWithClaims(method, claims())
+		if kid != nil {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	rsaPublicDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PrivateKey.(*rsa.PrivateKey).PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"missing kid", sign(jwt.SigningMethodEdDSA, nil, edKey.PrivateKey)},
+		{"unknown kid", sign(jwt.SigningMethodEdDSA, "unknown", edKey.PrivateKey)},
+		{"non-string kid", sign(jwt.SigningMethodEdDSA, 1, edKey.PrivateKey)},
+		{"RS256 token with Ed25519 kid", sign(jwt.SigningMethodRS256, edKey.ID, rsaKey.PrivateKey)},
+		{"EdDSA token with RSA kid", sign(jwt.SigningMethodEdDSA, rsaKey.ID, edKey.PrivateKey)},
+		{"HS256 signed with the RSA public key", sign(jwt.SigningMethodHS256, rsaKey.ID, rsaPublicDER)},
+		{"alg none", sign(jwt.SigningMethodNone, edKey.ID, jwt.UnsafeAllowNoneSignatureType)},
+		{"signed with a different key", sign(jwt.SigningMethodEdDSA, edKey.ID, otherEdKey.PrivateKey)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseToken(tt.token); err == nil {
+				t.Error("token accepted")
+			}
+		})
+	}
+}
+
+func TestParseRejectsInvalidClaims(t *testing.T) {
+	key := newEd25519Key(t, "ed")
+	setKeys(t, []*Key{key}, key.ID)
+
+	expired, err := Sign(&CustomClaims{
+		UserID:           1,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: NewNumericDate(time.Now().Add(-time.Minute))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseToken(expired); err == nil {
+		t.Error("expired token accepted")
+	}
+
+	previousIssuer := Issuer
+	t.Cleanup(func() { Issuer = previousIssuer })
+	Issuer = "someone-else"
+	foreign := signUser(t, 1, "alice", "org_member", 1)
+	Issuer = previousIssuer
+	if _, err := ParseToken(foreign); err == nil {
+		t.Error("token from another issuer accepted")
+	}
+}
+
+func TestSetKeys(t *testing.T) {
+	signing := newEd25519Key(t, "a")
+	verifyOnly := &Key{ID: "b", Method: jwt.SigningMethodEdDSA, PublicKey: newEd25519Key(t, "b").PublicKey}
+
+	tests := []struct {
+		name        string
+		keys        []*Key
+		activeKeyID string
+		wantErr     string
+	}{
+		{"valid", []*Key{signing, verifyOnly}, "a", ""},
+		{"duplicate kid", []*Key{signing, newEd25519Key(t, "a")}, "a", "密钥ID重复"},
+		{"unknown active key", []*Key{signing}, "missing", "未找到签名密钥"},
+		{"active key without private key", []*Key{signing, verifyOnly}, "b", "缺少私钥"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SetKeys(tt.keys, tt.activeKeyID)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("SetKeys() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadKey(t *testing.T) {
+	rsaPrivate := testRSAKey(t)
+	rsaPKCS8, err := x509.MarshalPKCS8PrivateKey(rsaPrivate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPublic, err := x509.MarshalPKIXPublicKey(&rsaPrivate.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPKCS8, err := x509.MarshalPKCS8PrivateKey(edPrivate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encode := func(blockType string, der []byte) string {
+		return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+	}
+
+	tests := []struct {
+		name        string
+		cfg         KeyConfig
+		wantPrivate bool
+		wantErr     string
+	}{
+		{"RSA PKCS8", KeyConfig{ID: "k", Algorithm: "RS256", PrivateKey: encode("PRIVATE KEY", rsaPKCS8)}, true, ""},
+		{"RSA PKCS1", KeyConfig{ID: "k", Algorithm: "RS256", PrivateKey: encode("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaPrivate))}, true, ""},
+		{"Ed25519", KeyConfig{ID: "k", Algorithm: "EdDSA", PrivateKey: encode("PRIVATE KEY", edPKCS8)}, true, ""},
+		{"public key only", KeyConfig{ID: "k", Algorithm: "RS256", PublicKey: encode("PUBLIC KEY", rsaPublic)}, false, ""},
+		{"missing id", KeyConfig{Algorithm: "RS256", PrivateKey: encode("PRIVATE KEY", rsaPKCS8)}, false, "密钥ID不能为空"},
+		{"unsupported algorithm", KeyConfig{ID: "k", Algorithm: "HS256", PrivateKey: encode("PRIVATE KEY", rsaPKCS8)}, false, "不支持的算法"},
+		{"RSA key declared as EdDSA", KeyConfig{ID: "k", Algorithm: "EdDSA", PrivateKey: encode("PRIVATE KEY", rsaPKCS8)}, false, "算法必须为RS256"},
+		{"Ed25519 key declared as RS256", KeyConfig{ID: "k", Algorithm: "RS256", PrivateKey: encode("PRIVATE KEY", edPKCS8)}, false, "算法必须为EdDSA"},
+		{"no key material", KeyConfig{ID: "k", Algorithm: "RS256"}, false, "未配置私钥或公钥"},
+		{"invalid PEM", KeyConfig{ID: "k", Algorithm: "RS256", PrivateKey: "not a pem"}, false, "无效的PEM数据"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := LoadKey(tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadKey() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (key.PrivateKey != nil) != tt.wantPrivate {
+				t.Errorf("has private key = %v, want %v", key.PrivateKey != nil, tt.wantPrivate)
+			}
+			if key.PublicKey == nil {
+				t.Error("public key not loaded")
+			}
+		})
+	}
+}
+
+func TestPublicJWKSKeepsConfigOrder(t *testing.T) {
+	keys := []*Key{newEd25519Key(t, "c"), newRSAKey(t, "a"), newEd25519Key(t, "b")}
+	setKeys(t, keys, "a")
+
+	for i := 0; i < 10; i++ {
+		jwks := PublicJWKS()
+		if len(jwks.Keys) != len(keys) {
+			t.Fatalf("got %d keys, want %d", len(jwks.Keys), len(keys))
+		}
+		for j, jwk := range jwks.Keys {
+			if jwk.Kid != keys[j].ID {
+				t.Fatalf("key %d kid = %q, want %q", j, jwk.Kid, keys[j].ID)
+			}
+		}
+	}
+
+	jwks := PublicJWKS()
+	if rsaJWK := jwks.Keys[1]; rsaJWK.Kty != "RSA" || rsaJWK.Alg != "RS256" || rsaJWK.N == "" || rsaJWK.E == "" {
+		t.Errorf("unexpected RSA JWK: %+v", rsaJWK)
+	}
+	if edJWK := jwks.Keys[0]; edJWK.Kty != "OKP" || edJWK.Crv != "Ed25519" || edJWK.Alg != "EdDSA" || edJWK.X == "" {
+		t.Errorf("unexpected Ed25519 JWK: %+v", edJWK)
+	}
+}
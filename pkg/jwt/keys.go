@@ -0,0 +1,245 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeyConfig 签名密钥配置
+// 只配置公钥的密钥仅用于验证，适用于密钥轮换的过渡期
+type KeyConfig struct {
+	ID             string `mapstructure:"id"`               // 密钥ID（kid）
+	Algorithm      string `mapstructure:"algorithm"`        // RS256 或 EdDSA
+	PrivateKey     string `mapstructure:"private_key"`      // PEM格式私钥
+	PrivateKeyFile string `mapstructure:"private_key_file"` // PEM格式私钥文件路径
+	PublicKey      string `mapstructure:"public_key"`       // PEM格式公钥
+	PublicKeyFile  string `mapstructure:"public_key_file"`  // PEM格式公钥文件路径
+}
+
+// Key 已加载的密钥
+type Key struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer // 仅用于验证的密钥为 nil
+	PublicKey  crypto.PublicKey
+}
+
+// JWK JSON Web Key（RFC 7517）
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// keySet 当前的签名密钥和全部验证密钥
+// keys 保持配置中的顺序，使 JWKS 的输出稳定，便于客户端缓存
+var keySet = struct {
+	sync.RWMutex
+	signing      *Key
+	keys         []*Key
+	verification map[string]*Key
+}{verification: make(map[string]*Key)}
+
+// SetKeys 设置签名密钥和验证密钥，activeKeyID 指定用于签发新令牌的密钥
+func SetKeys(keys []*Key, activeKeyID string) error {
+	verification := make(map[string]*Key, len(keys))
+	var signing *Key
+	for _, key := range keys {
+		if _, exists := verification[key.ID]; exists {
+			return fmt.Errorf("密钥ID重复: %s", key.ID)
+		}
+		verification[key.ID] = key
+		if key.ID == activeKeyID {
+			signing = key
+		}
+	}
+
+	if signing == nil {
+		return fmt.Errorf("未找到签名密钥: %s", activeKeyID)
+	}
+	if signing.PrivateKey == nil {
+		return fmt.Errorf("签名密钥缺少私钥: %s", activeKeyID)
+	}
+
+	keySet.Lock()
+	keySet.signing = signing
+	keySet.keys = append([]*Key(nil), keys...)
+	keySet.verification = verification
+	keySet.Unlock()
+	return nil
+}
+
+// LoadKey 根据配置加载密钥
+func LoadKey(cfg KeyConfig) (*Key, error) {
+	if cfg.ID == "" {
+		return nil, errors.New("密钥ID不能为空")
+	}
+
+	var method jwt.SigningMethod
+	switch cfg.Algorithm {
+	case "RS256":
+		method = jwt.SigningMethodRS256
+	case "EdDSA":
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("密钥 %s 使用了不支持的算法: %s", cfg.ID, cfg.Algorithm)
+	}
+
+	key := &Key{ID: cfg.ID, Method: method}
+
+	privatePEM, err := readPEM(cfg.PrivateKey, cfg.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取密钥 %s 的私钥失败: %w", cfg.ID, err)
+	}
+	if privatePEM != nil {
+		signer, err := parsePrivateKey(privatePEM)
+		if err != nil {
+			return nil, fmt.Errorf("解析密钥 %s 的私钥失败: %w", cfg.ID, err)
+		}
+		key.PrivateKey = signer
+		key.PublicKey = signer.Public()
+	} else {
+		publicPEM, err := readPEM(cfg.PublicKey, cfg.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取密钥 %s 的公钥失败: %w", cfg.ID, err)
+		}
+		if publicPEM == nil {
+			return nil, fmt.Errorf("密钥 %s 未配置私钥或公钥", cfg.ID)
+		}
+		if key.PublicKey, err = parsePublicKey(publicPEM); err != nil {
+			return nil, fmt.Errorf("解析密钥 %s 的公钥失败: %w", cfg.ID, err)
+		}
+	}
+
+	// 检查密钥类型与算法是否匹配
+	switch key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if method != jwt.SigningMethodRS256 {
+			return nil, fmt.Errorf("密钥 %s 为RSA密钥，算法必须为RS256", cfg.ID)
+		}
+	case ed25519.PublicKey:
+		if method != jwt.SigningMethodEdDSA {
+			return nil, fmt.Errorf("密钥 %s 为Ed25519密钥，算法必须为EdDSA", cfg.ID)
+		}
+	default:
+		return nil, fmt.Errorf("密钥 %s 的类型不受支持", cfg.ID)
+	}
+
+	return key, nil
+}
+
+// GenerateEphemeralKey 生成临时的Ed25519密钥，仅在未配置密钥时用于开发环境
+func GenerateEphemeralKey() (*Key, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{
+		ID:         "ephemeral",
+		Method:     jwt.SigningMethodEdDSA,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+	}, nil
+}
+
+// PublicJWKS 返回所有验证密钥的公钥集合，按配置中的顺序排列
+func PublicJWKS() JWKS {
+	keySet.RLock()
+	defer keySet.RUnlock()
+
+	jwks := JWKS{Keys: make([]JWK, 0, len(keySet.keys))}
+	for _, key := range keySet.keys {
+		jwk := JWK{Use: "sig", Alg: key.Method.Alg(), Kid: key.ID}
+		switch publicKey := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	return jwks
+}
+
+// signingKey 获取当前签名密钥
+func signingKey() (*Key, error) {
+	keySet.RLock()
+	defer keySet.RUnlock()
+	if keySet.signing == nil {
+		return nil, errors.New("未配置签名密钥")
+	}
+	return keySet.signing, nil
+}
+
+// verificationKey 根据kid获取验证密钥
+func verificationKey(kid string) (*Key, bool) {
+	keySet.RLock()
+	defer keySet.RUnlock()
+	key, ok := keySet.verification[kid]
+	return key, ok
+}
+
+// readPEM 读取内联或文件中的PEM数据，均未配置时返回 nil
+func readPEM(inline, file string) ([]byte, error) {
+	if inline != "" {
+		return []byte(inline), nil
+	}
+	if file != "" {
+		return os.ReadFile(file)
+	}
+	return nil, nil
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("无效的PEM数据")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("不支持的私钥类型")
+		}
+		return signer, nil
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("无效的PEM数据")
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return x509.ParsePKCS1PublicKey(block.Bytes)
+}
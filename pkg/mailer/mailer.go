@@ -0,0 +1,67 @@
+package mailer
+
+import (
+	"backend/pkg/config"
+	"backend/pkg/logger"
+	"fmt"
+)
+
+// Message 待发送的邮件
+type Message struct {
+	To      string
+	Subject string
+	Body    string // 纯文本正文
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// Default 全局使用的邮件发送器，未初始化时写入日志
+var Default Mailer = &LogMailer{}
+
+// Init 按 mail.driver 配置初始化全局邮件发送器
+func Init() error {
+	from := config.GetString("mail.from")
+	if from == "" {
+		from = "Windz <no-reply@localhost>"
+	}
+
+	switch driver := config.GetString("mail.driver"); driver {
+	case "", "log":
+		Default = &LogMailer{}
+	case "file":
+		path := config.GetString("mail.file_path")
+		if path == "" {
+			path = "logs/mail.log"
+		}
+		Default = &FileMailer{Path: path, From: from}
+	case "smtp":
+		smtpMailer := &SMTPMailer{
+			Host:     config.GetString("mail.smtp.host"),
+			Port:     config.GetInt("mail.smtp.port"),
+			Username: config.GetString("mail.smtp.username"),
+			Password: config.GetString("mail.smtp.password"),
+			TLS:      config.GetString("mail.smtp.tls"),
+			From:     from,
+			Timeout:  config.GetDuration("mail.smtp.timeout"),
+		}
+		if smtpMailer.Host == "" {
+			return fmt.Errorf("未配置SMTP服务器地址")
+		}
+		Default = smtpMailer
+	default:
+		return fmt.Errorf("不支持的邮件发送方式: %s", driver)
+	}
+
+	if _, ok := Default.(*LogMailer); ok {
+		logger.Warn("邮件将写入日志而不会真正发送，仅适用于开发环境")
+	}
+	return nil
+}
+
+// Send 使用全局邮件发送器发送邮件
+func Send(msg Message) error {
+	return Default.Send(msg)
+}
@@ -0,0 +1,51 @@
+package mailer
+
+import (
+	"backend/pkg/logger"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// LogMailer 将邮件内容写入应用日志，仅用于开发环境
+type LogMailer struct{}
+
+// Send 实现 Mailer 接口
+func (m *LogMailer) Send(msg Message) error {
+	logger.WithFields(map[string]interface{}{
+		"to":      msg.To,
+		"subject": msg.Subject,
+	}).Info("邮件内容:\n" + msg.Body)
+	return nil
+}
+
+// FileMailer 将邮件以可读的纯文本追加到文件，仅用于开发和测试环境
+type FileMailer struct {
+	Path string
+	From string
+	mu   sync.Mutex
+}
+
+// Send 实现 Mailer 接口
+func (m *FileMailer) Send(msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(m.Path), 0o755); err != nil {
+		return fmt.Errorf("创建邮件目录失败: %w", err)
+	}
+	f, err := os.OpenFile(m.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("打开邮件文件失败: %w", err)
+	}
+	defer f.Close()
+
+	content := fmt.Sprintf("From: %s\nTo: %s\nSubject: %s\nDate: %s\n\n%s\n\n",
+		m.From, msg.To, msg.Subject, time.Now().Format(time.RFC1123Z), msg.Body)
+	if _, err := f.WriteString(content); err != nil {
+		return fmt.Errorf("写入邮件失败: %w", err)
+	}
+	return nil
+}
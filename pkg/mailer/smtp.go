@@ -0,0 +1,163 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TLSStartTLS = "starttls" // 明文连接后升级为TLS（默认，端口587）
+	TLSImplicit = "tls"      // 直接建立TLS连接（端口465）
+	TLSNone     = "none"     // 不加密，仅用于本地测试服务器
+)
+
+// SMTPMailer 通过SMTP服务器发送邮件
+type SMTPMailer struct {
+	Host     string
+	Port     int
+	Username string // 为空时不进行认证
+	Password string
+	TLS      string // starttls、tls 或 none
+	From     string
+	Timeout  time.Duration
+}
+
+// Send 实现 Mailer 接口
+func (m *SMTPMailer) Send(msg Message) error {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return fmt.Errorf("发件人地址无效: %w", err)
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("收件人地址无效: %w", err)
+	}
+
+	client, err := m.dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if m.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.Username, m.Password, m.Host)); err != nil {
+			return fmt.Errorf("SMTP认证失败: %w", err)
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("SMTP发件人被拒绝: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("SMTP收件人被拒绝: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP发送数据失败: %w", err)
+	}
+	if _, err := w.Write(buildMessage(m.From, msg)); err != nil {
+		return fmt.Errorf("SMTP发送数据失败: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP发送数据失败: %w", err)
+	}
+	return client.Quit()
+}
+
+// dial 连接SMTP服务器，并按配置建立TLS
+func (m *SMTPMailer) dial() (*smtp.Client, error) {
+	port := m.Port
+	if port == 0 {
+		port = 587
+		if m.TLS == TLSImplicit {
+			port = 465
+		}
+	}
+	timeout := m.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	addr := net.JoinHostPort(m.Host, strconv.Itoa(port))
+	tlsConfig := &tls.Config{ServerName: m.Host}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	if m.TLS == TLSImplicit {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("连接SMTP服务器失败: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	client, err := smtp.NewClient(conn, m.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("连接SMTP服务器失败: %w", err)
+	}
+
+	if m.TLS == "" || m.TLS == TLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, fmt.Errorf("SMTP服务器不支持STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("SMTP建立TLS失败: %w", err)
+		}
+	}
+	return client, nil
+}
+
+// buildMessage 生成UTF-8编码的纯文本邮件
+func buildMessage(from string, msg Message) []byte {
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		buf.WriteString(key + ": " + value + "\r\n")
+	}
+	header("From", from)
+	header("To", msg.To)
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", messageID(from))
+	header("MIME-Version", "1.0")
+	header("Content-Type", `text/plain; charset="utf-8"`)
+	header("Content-Transfer-Encoding", "base64")
+	buf.WriteString("\r\n")
+
+	// 按76字符分行，符合 RFC 2045
+	encoded := base64.StdEncoding.EncodeToString([]byte(msg.Body))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	return buf.Bytes()
+}
+
+// messageID 生成邮件的 Message-ID
+func messageID(from string) string {
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(addr.Address, "@"); at >= 0 {
+			domain = addr.Address[at+1:]
+		}
+	}
+	buf := make([]byte, 12)
+	rand.Read(buf)
+	return "<" + hex.EncodeToString(buf) + "@" + domain + ">"
+}
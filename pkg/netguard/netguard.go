@@ -0,0 +1,130 @@
+// Package netguard 限制按组织配置发起的出站连接，拒绝访问回环、内网和链路本地地址，防止SSRF
+package netguard
+
+import (
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress 目标地址属于禁止访问的网段
+var ErrForbiddenAddress = errors.New("不允许连接内网地址")
+
+// blockedNetworks 默认禁止访问的网段，net.IP 的方法未覆盖的部分
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // 本网络
+	"100.64.0.0/10", // 运营商级NAT
+	"192.0.0.0/24",  // IETF协议分配
+	"198.18.0.0/15", // 基准测试
+	"240.0.0.0/4",   // 保留地址
+	"64:ff9b::/96",  // NAT64，可能映射到内网IPv4地址
+)
+
+var allowed struct {
+	sync.RWMutex
+	networks []*net.IPNet
+}
+
+// Init 按 security.outbound.allowed_networks 配置允许访问的内网网段
+func Init() error {
+	return SetAllowedNetworks(config.GetStringSlice("security.outbound.allowed_networks"))
+}
+
+// SetAllowedNetworks 设置允许访问的内网网段，支持CIDR和单个IP地址
+func SetAllowedNetworks(networks []string) error {
+	parsed := make([]*net.IPNet, 0, len(networks))
+	for _, network := range networks {
+		network = strings.TrimSpace(network)
+		if network == "" {
+			continue
+		}
+		if !strings.Contains(network, "/") {
+			ip := net.ParseIP(network)
+			if ip == nil {
+				return fmt.Errorf("无效的网段: %s", network)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			parsed = append(parsed, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(network)
+		if err != nil {
+			return fmt.Errorf("无效的网段: %s", network)
+		}
+		parsed = append(parsed, ipNet)
+	}
+
+	allowed.Lock()
+	allowed.networks = parsed
+	allowed.Unlock()
+	return nil
+}
+
+// Check 检查是否允许连接该IP地址
+func Check(ip net.IP) error {
+	allowed.RLock()
+	defer allowed.RUnlock()
+	for _, network := range allowed.networks {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+
+	if isBlocked(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+	}
+	return nil
+}
+
+// Dialer 返回在建立连接前校验目标地址的拨号器
+// 校验发生在域名解析之后，重定向和DNS重绑定同样受限
+func Dialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{Timeout: timeout, Control: control}
+}
+
+// control 拨号器在连接解析后的地址前调用
+func control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return Check(ip)
+}
+
+func isBlocked(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
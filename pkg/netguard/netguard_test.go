@@ -0,0 +1,98 @@
+package netguard
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestCheckBlocksInternalAddresses(t *testing.T) {
+	blocked := []string{
+		"127.0.0.1",
+		"10.0.0.1",
+		"172.16.5.4",
+		"192.168.1.1",
+		"169.254.169.254", // 云厂商元数据地址
+		"0.0.0.0",
+		"100.64.0.1",
+		"224.0.0.1",
+		"::1",
+		"::",
+		"fc00::1",
+		"fe80::1",
+		"::ffff:127.0.0.1", // IPv4映射的IPv6地址
+		"64:ff9b::a9fe:a9fe",
+	}
+	for _, address := range blocked {
+		if err := Check(net.ParseIP(address)); !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("Check(%s) = %v, want ErrForbiddenAddress", address, err)
+		}
+	}
+
+	for _, address := range []string{"93.184.216.34", "2606:2800:220:1:248:1893:25c8:1946"} {
+		if err := Check(net.ParseIP(address)); err != nil {
+			t.Errorf("Check(%s) = %v, want nil", address, err)
+		}
+	}
+}
+
+func TestSetAllowedNetworks(t *testing.T) {
+	t.Cleanup(func() { SetAllowedNetworks(nil) })
+
+	if err := SetAllowedNetworks([]string{"10.1.0.0/16", " 127.0.0.1 ", ""}); err != nil {
+		t.Fatal(err)
+	}
+	for _, address := range []string{"10.1.2.3", "127.0.0.1"} {
+		if err := Check(net.ParseIP(address)); err != nil {
+			t.Errorf("allowed %s: %v", address, err)
+		}
+	}
+	for _, address := range []string{"10.2.0.1", "127.0.0.2", "169.254.169.254"} {
+		if err := Check(net.ParseIP(address)); !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("Check(%s) = %v, want ErrForbiddenAddress", address, err)
+		}
+	}
+
+	if err := SetAllowedNetworks([]string{"not-a-network"}); err == nil {
+		t.Error("accepted an invalid network")
+	}
+	if err := SetAllowedNetworks([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("accepted an invalid CIDR")
+	}
+}
+
+// 拨号器在连接前校验解析后的地址，域名指向内网地址时同样拒绝
+func TestDialerRefusesInternalAddresses(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	for _, address := range []string{listener.Addr().String(), net.JoinHostPort("localhost", port)} {
+		if _, err := Dialer(time.Second).Dial("tcp", address); !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("Dial(%s) = %v, want ErrForbiddenAddress", address, err)
+		}
+	}
+
+	if err := SetAllowedNetworks([]string{"127.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetAllowedNetworks(nil) })
+	conn, err := Dialer(time.Second).Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial after allowing 127.0.0.1: %v", err)
+	}
+	conn.Close()
+}
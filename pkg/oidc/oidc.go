@@ -0,0 +1,356 @@
+// Package oidc 实现OpenID Connect授权码模式（含PKCE）客户端
+package oidc
+
+import (
+	"backend/pkg/netguard"
+	"backend/pkg/token"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// metadataTTL 发现文档和公钥集合的缓存时间
+	metadataTTL = time.Hour
+	// jwksRefreshInterval 遇到未知kid时重新获取公钥集合的最小间隔
+	jwksRefreshInterval = time.Minute
+	// clockSkew 校验ID令牌时间声明时允许的时钟偏差
+	clockSkew = time.Minute
+)
+
+// httpClient 访问身份提供方使用的HTTP客户端，连接前按 netguard 校验目标地址
+var httpClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext:         netguard.Dialer(10 * time.Second).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	},
+}
+
+// Provider 通过发现文档获取的身份提供方元数据
+type Provider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Config 客户端配置
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// TokenResponse 令牌端点响应
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Claims ID令牌声明
+type Claims map[string]interface{}
+
+// String 获取字符串声明
+func (c Claims) String(name string) string {
+	value, _ := c[name].(string)
+	return value
+}
+
+// Strings 获取字符串或字符串数组声明
+func (c Claims) Strings(name string) []string {
+	switch value := c[name].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// Bool 获取布尔声明，兼容部分身份提供方返回的字符串形式
+func (c Claims) Bool(name string) bool {
+	switch value := c[name].(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	}
+	return false
+}
+
+type cachedProvider struct {
+	provider  *Provider
+	fetchedAt time.Time
+}
+
+type cachedJWKS struct {
+	keys      map[string]jwksKey
+	fetchedAt time.Time
+}
+
+var cache = struct {
+	sync.Mutex
+	providers map[string]cachedProvider
+	jwks      map[string]cachedJWKS
+}{
+	providers: make(map[string]cachedProvider),
+	jwks:      make(map[string]cachedJWKS),
+}
+
+// Discover 获取身份提供方的发现文档（/.well-known/openid-configuration）
+func Discover(ctx context.Context, issuer string) (*Provider, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+
+	cache.Lock()
+	cached, ok := cache.providers[issuer]
+	cache.Unlock()
+	if ok && time.Since(cached.fetchedAt) < metadataTTL {
+		return cached.provider, nil
+	}
+
+	var provider Provider
+	if err := getJSON(ctx, issuer+"/.well-known/openid-configuration", &provider); err != nil {
+		return nil, fmt.Errorf("获取OIDC发现文档失败: %w", err)
+	}
+	if strings.TrimSuffix(provider.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("发现文档中的issuer不匹配: %s", provider.Issuer)
+	}
+	if provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JWKSURI == "" {
+		return nil, errors.New("发现文档缺少必要的端点")
+	}
+
+	cache.Lock()
+	cache.providers[issuer] = cachedProvider{provider: &provider, fetchedAt: time.Now()}
+	cache.Unlock()
+	return &provider, nil
+}
+
+// GeneratePKCE 生成PKCE校验码及其S256挑战值（RFC 7636）
+func GeneratePKCE() (verifier, challenge string, err error) {
+	verifier, err = token.Generate(32)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// AuthCodeURL 构建授权请求地址
+func AuthCodeURL(provider *Provider, cfg Config, state, nonce, codeChallenge string) string {
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "profile", "email"}
+	}
+	if !containsString(scopes, "openid") {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", cfg.ClientID)
+	params.Set("redirect_uri", cfg.RedirectURL)
+	params.Set("scope", strings.Join(scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(provider.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return provider.AuthorizationEndpoint + separator + params.Encode()
+}
+
+// Exchange 使用授权码和PKCE校验码换取令牌
+func Exchange(ctx context.Context, provider *Provider, cfg Config, code, codeVerifier string) (*TokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", cfg.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	if cfg.ClientSecret == "" {
+		// 公共客户端只在表单中携带客户端ID
+		form.Set("client_id", cfg.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(cfg.ClientID), url.QueryEscape(cfg.ClientSecret))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求令牌端点失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("读取令牌响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		if json.Unmarshal(body, &oauthErr) == nil && oauthErr.Error != "" {
+			return nil, fmt.Errorf("令牌端点返回错误: %s %s", oauthErr.Error, oauthErr.ErrorDescription)
+		}
+		return nil, fmt.Errorf("令牌端点返回状态码 %d", resp.StatusCode)
+	}
+
+	var tokenResp TokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("解析令牌响应失败: %w", err)
+	}
+	if tokenResp.IDToken == "" {
+		return nil, errors.New("令牌响应缺少id_token")
+	}
+	return &tokenResp, nil
+}
+
+// VerifyIDToken 校验ID令牌的签名、签发者、受众、有效期和nonce
+func VerifyIDToken(ctx context.Context, provider *Provider, clientID, rawIDToken, nonce string) (Claims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return lookupKey(ctx, provider.JWKSURI, kid, t.Method.Alg())
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(provider.Issuer),
+		jwt.WithAudience(clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ID令牌校验失败: %w", err)
+	}
+
+	idClaims := Claims(claims)
+	if idClaims.String("nonce") != nonce {
+		return nil, errors.New("ID令牌校验失败: nonce不匹配")
+	}
+	// 多个受众时 azp 必须为当前客户端
+	if azp := idClaims.String("azp"); azp != "" && azp != clientID {
+		return nil, errors.New("ID令牌校验失败: azp不匹配")
+	}
+	if idClaims.String("sub") == "" {
+		return nil, errors.New("ID令牌校验失败: 缺少sub")
+	}
+	return idClaims, nil
+}
+
+// lookupKey 根据kid查找身份提供方的验证公钥，未找到时刷新公钥集合
+func lookupKey(ctx context.Context, jwksURI, kid, alg string) (interface{}, error) {
+	cache.Lock()
+	cached, ok := cache.jwks[jwksURI]
+	cache.Unlock()
+
+	stale := !ok || time.Since(cached.fetchedAt) >= metadataTTL
+	if !stale {
+		if key, found := cached.find(kid, alg); found {
+			return key, nil
+		}
+		// 身份提供方可能已轮换密钥，限制刷新频率
+		stale = time.Since(cached.fetchedAt) >= jwksRefreshInterval
+	}
+	if !stale {
+		return nil, fmt.Errorf("未找到验证密钥: %s", kid)
+	}
+
+	keys, err := fetchJWKS(ctx, jwksURI)
+	if err != nil {
+		return nil, err
+	}
+	cached = cachedJWKS{keys: keys, fetchedAt: time.Now()}
+	cache.Lock()
+	cache.jwks[jwksURI] = cached
+	cache.Unlock()
+
+	if key, found := cached.find(kid, alg); found {
+		return key, nil
+	}
+	return nil, fmt.Errorf("未找到验证密钥: %s", kid)
+}
+
+// find 查找验证密钥，令牌未指定kid时使用唯一一个类型匹配的密钥
+func (c cachedJWKS) find(kid, alg string) (interface{}, bool) {
+	if kid != "" {
+		key, ok := c.keys[kid]
+		if !ok || !key.accepts(alg) {
+			return nil, false
+		}
+		return key.publicKey, true
+	}
+
+	var match interface{}
+	for _, key := range c.keys {
+		if key.accepts(alg) {
+			if match != nil {
+				return nil, false
+			}
+			match = key.publicKey
+		}
+	}
+	return match, match != nil
+}
+
+// getJSON 获取并解析JSON文档
+func getJSON(ctx context.Context, endpoint string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s 返回状态码 %d", endpoint, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+package oidc
+
+import (
+	"backend/pkg/netguard"
+	"backend/pkg/oidc/oidctest"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestMain(m *testing.M) {
+	// 模拟身份提供方监听在回环地址
+	if err := netguard.SetAllowedNetworks([]string{"127.0.0.1"}); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// newProvider 启动模拟身份提供方并获取其发现文档
+func newProvider(t *testing.T) (*oidctest.Server, *Provider) {
+	t.Helper()
+	server := oidctest.NewServer(t, "windz")
+	provider, err := Discover(context.Background(), server.Issuer())
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	return server, provider
+}
+
+func TestDiscover(t *testing.T) {
+	server, provider := newProvider(t)
+	if provider.Issuer != server.Issuer() || provider.TokenEndpoint != server.URL+"/token" || provider.JWKSURI != server.URL+"/jwks" {
+		t.Errorf("unexpected metadata: %+v", provider)
+	}
+
+	// 发现文档中的 issuer 必须与配置一致
+	mismatch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"issuer":"https://idp.example.com","authorization_endpoint":"https://idp.example.com/authorize",` +
+			`"token_endpoint":"https://idp.example.com/token","jwks_uri":"https://idp.example.com/jwks"}`))
+	}))
+	defer mismatch.Close()
+	if _, err := Discover(context.Background(), mismatch.URL); err == nil {
+		t.Error("issuer mismatch accepted")
+	}
+}
+
+func TestDiscoverBlocksPrivateNetwork(t *testing.T) {
+	server := oidctest.NewServer(t, "windz")
+	if err := netguard.SetAllowedNetworks(nil); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { netguard.SetAllowedNetworks([]string{"127.0.0.1"}) })
+
+	if _, err := Discover(context.Background(), server.Issuer()); !errors.Is(err, netguard.ErrForbiddenAddress) {
+		t.Errorf("err = %v, want ErrForbiddenAddress", err)
+	}
+}
+
+func TestExchangePKCE(t *testing.T) {
+	server, provider := newProvider(t)
+	cfg := Config{ClientID: "windz", RedirectURL: "https://app.example.com/callback"}
+	ctx := context.Background()
+
+	authorize := func(challenge string) string {
+		t.Helper()
+		code, state, err := server.Authorize(AuthCodeURL(provider, cfg, "state-1", "nonce-1", challenge), map[string]interface{}{"sub": "alice"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state != "state-1" {
+			t.Fatalf("state = %q", state)
+		}
+		return code
+	}
+
+	verifier, challenge, err := GeneratePKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := authorize(challenge)
+	resp, err := Exchange(ctx, provider, cfg, code, verifier)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	claims, err := VerifyIDToken(ctx, provider, cfg.ClientID, resp.IDToken, "nonce-1")
+	if err != nil {
+		t.Fatalf("VerifyIDToken: %v", err)
+	}
+	if claims.String("sub") != "alice" {
+		t.Errorf("sub = %q", claims.String("sub"))
+	}
+
+	// 授权码只能使用一次
+	if _, err := Exchange(ctx, provider, cfg, code, verifier); err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("reused code: err = %v, want invalid_grant", err)
+	}
+
+	// 校验码与挑战值不匹配
+	otherVerifier, _, err := GeneratePKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Exchange(ctx, provider, cfg, authorize(challenge), otherVerifier); err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("wrong verifier: err = %v, want invalid_grant", err)
+	}
+}
+
+func TestExchangeConfidentialClient(t *testing.T) {
+	server, provider := newProvider(t)
+	server.ClientSecret = "s3cret:+/"
+	cfg := Config{ClientID: "windz", ClientSecret: server.ClientSecret, RedirectURL: "https://app.example.com/callback"}
+	ctx := context.Background()
+
+	verifier, challenge, err := GeneratePKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL := AuthCodeURL(provider, cfg, "state-1", "nonce-1", challenge)
+
+	code, _, err := server.Authorize(authURL, map[string]interface{}{"sub": "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Exchange(ctx, provider, cfg, code, verifier); err != nil {
+		t.Errorf("Exchange with client secret: %v", err)
+	}
+
+	code, _, err = server.Authorize(authURL, map[string]interface{}{"sub": "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ClientSecret = "wrong"
+	if _, err := Exchange(ctx, provider, cfg, code, verifier); err == nil || !strings.Contains(err.Error(), "invalid_client") {
+		t.Errorf("wrong secret: err = %v, want invalid_client", err)
+	}
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	server, provider := newProvider(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(claims jwt.MapClaims)
+		key     *rsa.PrivateKey
+		wantErr bool
+	}{
+		{"valid", nil, nil, false},
+		{"multiple audiences with azp", func(c jwt.MapClaims) {
+			c["aud"] = []string{"windz", "other"}
+			c["azp"] = "windz"
+		}, nil, false},
+		{"bad signature", nil, otherKey, true},
+		{"wrong nonce", func(c jwt.MapClaims) { c["nonce"] = "other" }, nil, true},
+		{"missing nonce", func(c jwt.MapClaims) { delete(c, "nonce") }, nil, true},
+		{"wrong audience", func(c jwt.MapClaims) { c["aud"] = "other" }, nil, true},
+		{"wrong azp", func(c jwt.MapClaims) {
+			c["aud"] = []string{"windz", "other"}
+			c["azp"] = "other"
+		}, nil, true},
+		{"wrong issuer", func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }, nil, true},
+		{"expired", func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-5 * time.Minute).Unix() }, nil, true},
+		{"missing exp", func(c jwt.MapClaims) { delete(c, "exp") }, nil, true},
+		{"issued in the future", func(c jwt.MapClaims) { c["iat"] = time.Now().Add(5 * time.Minute).Unix() }, nil, true},
+		{"missing sub", func(c jwt.MapClaims) { delete(c, "sub") }, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.IDTokenHook = tt.mutate
+			server.SigningKey = tt.key
+			defer func() {
+				server.IDTokenHook = nil
+				server.SigningKey = nil
+			}()
+
+			raw, err := server.SignIDToken(jwt.MapClaims{"sub": "alice", "nonce": "nonce-1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = VerifyIDToken(context.Background(), provider, "windz", raw, "nonce-1")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// 只接受身份提供方公钥集合中的非对称签名
+func TestVerifyIDTokenRejectsUntrustedAlgorithms(t *testing.T) {
+	server, provider := newProvider(t)
+	claims := jwt.MapClaims{
+		"iss":   server.Issuer(),
+		"aud":   "windz",
+		"sub":   "alice",
+		"nonce": "nonce-1",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+	}
+
+	unknownKid := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	unknownKid.Header["kid"] = "other-key"
+	hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	hmac.Header["kid"] = server.KeyID
+	none := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
+	none.Header["kid"] = server.KeyID
+
+	for name, sign := range map[string]func() (string, error){
+		"unknown kid": func() (string, error) { return unknownKid.SignedString(server.Key) },
+		"HS256 with the public key": func() (string, error) {
+			return hmac.SignedString([]byte(server.Key.PublicKey.N.Bytes()))
+		},
+		"alg none": func() (string, error) { return none.SignedString(jwt.UnsafeAllowNoneSignatureType) },
+	} {
+		raw, err := sign()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if _, err := VerifyIDToken(context.Background(), provider, "windz", raw, "nonce-1"); err == nil {
+			t.Errorf("%s: token accepted", name)
+		}
+	}
+}
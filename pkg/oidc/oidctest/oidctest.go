@@ -0,0 +1,230 @@
+// Package oidctest 提供用于测试的模拟OpenID Connect身份提供方，
+// 支持发现文档、公钥集合和授权码模式（含PKCE）的令牌端点
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Server 模拟身份提供方，Issuer 为服务地址
+type Server struct {
+	*httptest.Server
+
+	ClientID     string
+	ClientSecret string // 为空时按公共客户端处理
+	Key          *rsa.PrivateKey
+	KeyID        string
+
+	// IDTokenHook 签发ID令牌前修改声明，用于构造异常令牌
+	IDTokenHook func(claims jwt.MapClaims)
+	// SigningKey 不为空时使用该密钥签发ID令牌，用于构造签名无效的令牌
+	SigningKey *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]*authRequest
+}
+
+// authRequest 已授权、尚未换取令牌的授权请求
+type authRequest struct {
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	claims        jwt.MapClaims
+}
+
+// NewServer 启动模拟身份提供方，测试结束时自动关闭
+func NewServer(t interface {
+	Helper()
+	Fatal(args ...interface{})
+	Cleanup(func())
+}, clientID string) *Server {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{
+		ClientID: clientID,
+		Key:      key,
+		KeyID:    "test-key",
+		codes:    make(map[string]*authRequest),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/jwks", s.jwks)
+	mux.HandleFunc("/token", s.token)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Issuer 签发者地址
+func (s *Server) Issuer() string {
+	return s.URL
+}
+
+// Authorize 模拟用户在身份提供方完成登录：校验授权请求并返回授权码和原样带回的 state
+// claims 为登录用户的声明（至少包含 sub），令牌端点签发的ID令牌会包含这些声明
+func (s *Server) Authorize(authURL string, claims map[string]interface{}) (code, state string, err error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	query := u.Query()
+	switch {
+	case query.Get("response_type") != "code":
+		return "", "", errors.New("response_type 必须为 code")
+	case query.Get("client_id") != s.ClientID:
+		return "", "", errors.New("client_id 不匹配")
+	case query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "":
+		return "", "", errors.New("缺少PKCE挑战值")
+	case query.Get("state") == "" || query.Get("nonce") == "":
+		return "", "", errors.New("缺少state或nonce")
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	code = base64.RawURLEncoding.EncodeToString(buf)
+
+	s.mu.Lock()
+	s.codes[code] = &authRequest{
+		redirectURI:   query.Get("redirect_uri"),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		claims:        jwt.MapClaims(claims),
+	}
+	s.mu.Unlock()
+	return code, query.Get("state"), nil
+}
+
+// SignIDToken 使用身份提供方的密钥签发ID令牌，补全 iss、aud、iat、exp
+func (s *Server) SignIDToken(claims jwt.MapClaims) (string, error) {
+	now := time.Now()
+	full := jwt.MapClaims{
+		"iss": s.Issuer(),
+		"aud": s.ClientID,
+		"iat": now.Unix(),
+		"exp": now.Add(5 * time.Minute).Unix(),
+	}
+	for name, value := range claims {
+		full[name] = value
+	}
+	if s.IDTokenHook != nil {
+		s.IDTokenHook(full)
+	}
+
+	key := s.Key
+	if s.SigningKey != nil {
+		key = s.SigningKey
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, full)
+	token.Header["kid"] = s.KeyID
+	return token.SignedString(key)
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                 s.Issuer(),
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"jwks_uri":               s.URL + "/jwks",
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	public := s.Key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": s.KeyID,
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}},
+	})
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "invalid_request", "")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID = r.PostForm.Get("client_id")
+	}
+	if clientID != s.ClientID || clientSecret != s.ClientSecret {
+		tokenError(w, "invalid_client", "")
+		return
+	}
+
+	// 授权码只能使用一次
+	code := r.PostForm.Get("code")
+	s.mu.Lock()
+	request, found := s.codes[code]
+	delete(s.codes, code)
+	s.mu.Unlock()
+	if !found {
+		tokenError(w, "invalid_grant", "授权码无效")
+		return
+	}
+	if r.PostForm.Get("redirect_uri") != request.redirectURI {
+		tokenError(w, "invalid_grant", "redirect_uri 不匹配")
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != request.codeChallenge {
+		tokenError(w, "invalid_grant", "PKCE校验失败")
+		return
+	}
+
+	claims := jwt.MapClaims{"nonce": request.nonce}
+	for name, value := range request.claims {
+		claims[name] = value
+	}
+	idToken, err := s.SignIDToken(claims)
+	if err != nil {
+		tokenError(w, "server_error", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": "access-" + code,
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+		"expires_in":   300,
+	})
+}
+
+func tokenError(w http.ResponseWriter, code, description string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code, "error_description": description})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		panic(fmt.Sprintf("oidctest: %v", err))
+	}
+}